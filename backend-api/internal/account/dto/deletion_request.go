@@ -0,0 +1,15 @@
+package dto
+
+import "time"
+
+// DeleteAccountRequest represents the request body for requesting account deletion
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// DeletionStatusResponse describes a scheduled account deletion
+type DeletionStatusResponse struct {
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+	Message             string     `json:"message"`
+}
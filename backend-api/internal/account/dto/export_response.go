@@ -0,0 +1,31 @@
+package dto
+
+import "time"
+
+// ExportIDParam binds the export id path parameter
+type ExportIDParam struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// UserIDParam binds the user id path parameter of admin routes
+type UserIDParam struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// ExportDownloadQuery holds the signed parameters of an export download link
+type ExportDownloadQuery struct {
+	Expires   int64  `form:"expires" binding:"required"`
+	Signature string `form:"signature" binding:"required"`
+}
+
+// ExportResponse describes a personal data export job
+type ExportResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	FileSize    int64      `json:"file_size,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	DownloadURL string     `json:"download_url,omitempty"` // Only set once the archive is ready
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/account/dto"
+	"karigar-backend/internal/account/service"
+	"karigar-backend/pkg/apperror"
+)
+
+type AccountHandler struct {
+	accountService *service.AccountService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(accountService *service.AccountService) *AccountHandler {
+	return &AccountHandler{
+		accountService: accountService,
+	}
+}
+
+// RequestDeletion schedules the authenticated user's account for deletion
+// @Summary Delete account
+// @Description Schedule the account for deletion after a grace period. Bookings and reviews are kept in anonymized form.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.DeleteAccountRequest true "Delete account request"
+// @Success 202 {object} dto.DeletionStatusResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me [delete]
+func (h *AccountHandler) RequestDeletion(c *gin.Context) {
+	var req dto.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.accountService.RequestDeletion(c.Request.Context(), c.GetString("user_id"), req.Password)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// CancelDeletion cancels a scheduled deletion of the authenticated user's account
+// @Summary Cancel account deletion
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Router /me/deletion/cancel [post]
+func (h *AccountHandler) CancelDeletion(c *gin.Context) {
+	err := h.accountService.CancelDeletion(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "account deletion cancelled"})
+}
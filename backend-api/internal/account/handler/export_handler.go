@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/account/dto"
+	"karigar-backend/internal/account/service"
+	"karigar-backend/pkg/apperror"
+)
+
+type ExportHandler struct {
+	exportService *service.ExportService
+}
+
+// NewExportHandler creates a new data export handler
+func NewExportHandler(exportService *service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// RequestExport starts an export of the authenticated user's personal data
+// @Summary Request personal data export
+// @Description Build a zip of JSON files with everything stored about the user. The archive is generated in the background; poll the export until it has a download_url.
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} dto.ExportResponse
+// @Failure 401 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/exports [post]
+func (h *ExportHandler) RequestExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	response, err := h.exportService.RequestExport(c.Request.Context(), userID, userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// ListExports lists the authenticated user's data exports
+// @Summary List personal data exports
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.ExportResponse
+// @Router /me/exports [get]
+func (h *ExportHandler) ListExports(c *gin.Context) {
+	response, err := h.exportService.ListExports(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetExport gets one of the authenticated user's data exports
+// @Summary Get personal data export
+// @Tags account
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Export ID"
+// @Success 200 {object} dto.ExportResponse
+// @Failure 404 {object} apperror.Problem
+// @Router /me/exports/{id} [get]
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	var param dto.ExportIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.exportService.GetExport(c.Request.Context(), c.GetString("user_id"), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Download serves an export archive. The link is signed and expires, so it works
+// without an Authorization header (e.g. opened directly in a browser).
+// @Summary Download personal data export
+// @Tags account
+// @Produce application/zip
+// @Param id path string true "Export ID"
+// @Param expires query int true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /exports/{id}/download [get]
+func (h *ExportHandler) Download(c *gin.Context) {
+	var param dto.ExportIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	var query dto.ExportDownloadQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	export, archive, err := h.exportService.OpenDownload(c.Request.Context(), param.ID, query.Expires, query.Signature)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", `attachment; filename="karigar-data-export-`+export.ID.String()+`.zip"`)
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+// AdminRequestExport starts an export of a user's personal data on their behalf
+// @Summary Request personal data export for a user
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 202 {object} dto.ExportResponse
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /admin/users/{id}/exports [post]
+func (h *ExportHandler) AdminRequestExport(c *gin.Context) {
+	var param dto.UserIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.exportService.RequestExport(c.Request.Context(), param.ID, c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// AdminListExports lists a user's data exports
+// @Summary List personal data exports for a user
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {array} dto.ExportResponse
+// @Router /admin/users/{id}/exports [get]
+func (h *ExportHandler) AdminListExports(c *gin.Context) {
+	var param dto.UserIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.exportService.ListExports(c.Request.Context(), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"karigar-backend/internal/account/dto"
+	"karigar-backend/internal/audit"
+	authservice "karigar-backend/internal/auth/service"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/auth"
+	"karigar-backend/pkg/jobs"
+)
+
+var (
+	ErrIncorrectPassword        = apperror.New(apperror.Unauthorized, "incorrect_password", "password is incorrect")
+	ErrDeletionAlreadyRequested = apperror.New(apperror.Conflict, "deletion_already_requested", "account deletion has already been requested")
+	ErrNoDeletionScheduled      = apperror.New(apperror.Invalid, "no_deletion_scheduled", "no account deletion is scheduled")
+)
+
+// purgeBatchSize limits how many accounts are deleted per sweep
+const purgeBatchSize = 100
+
+// purgeJob runs PurgeDueAccounts
+var purgeJob = jobs.NewKind[struct{}]("accounts.purge_deletions")
+
+// AccountService handles the account lifecycle: self-service deletion with a grace period
+type AccountService struct {
+	userRepo    repository.UserRepository
+	accountRepo repository.AccountRepository
+	passwords   *authservice.PasswordService
+	audit       *audit.Recorder
+	cfg         config.AccountConfig
+}
+
+// NewAccountService creates a new account service
+func NewAccountService(userRepo repository.UserRepository, accountRepo repository.AccountRepository, passwords *authservice.PasswordService, recorder *audit.Recorder, cfg *config.Config) *AccountService {
+	return &AccountService{
+		userRepo:    userRepo,
+		accountRepo: accountRepo,
+		passwords:   passwords,
+		audit:       recorder,
+		cfg:         cfg.Account,
+	}
+}
+
+// RequestDeletion schedules the user's account for deletion after the grace period.
+// The user can still log in and cancel until then.
+func (s *AccountService) RequestDeletion(ctx context.Context, userID, password string) (*dto.DeletionStatusResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.passwords.Verify(ctx, user, password); err != nil {
+		if errors.Is(err, auth.ErrPasswordMismatch) {
+			return nil, ErrIncorrectPassword
+		}
+		return nil, err
+	}
+
+	if user.DeletionScheduledAt != nil {
+		return nil, ErrDeletionAlreadyRequested
+	}
+
+	now := time.Now()
+	scheduledAt := now.Add(s.cfg.DeletionGracePeriod)
+	if err := s.userRepo.ScheduleDeletion(ctx, userID, now, scheduledAt); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, user.ID, domain.AuditAccountDeletionRequest, map[string]interface{}{"scheduled_at": scheduledAt})
+
+	return &dto.DeletionStatusResponse{
+		DeletionRequestedAt: &now,
+		DeletionScheduledAt: &scheduledAt,
+		Message:             "account scheduled for deletion; log in and cancel before the scheduled time to keep it",
+	}, nil
+}
+
+// CancelDeletion cancels a scheduled account deletion
+func (s *AccountService) CancelDeletion(ctx context.Context, userID string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.DeletionScheduledAt == nil {
+		return ErrNoDeletionScheduled
+	}
+
+	if err := s.userRepo.CancelDeletion(ctx, userID); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, user.ID, domain.AuditAccountDeletionCancel, nil)
+
+	return nil
+}
+
+// PurgeDueAccounts anonymizes and deletes accounts whose grace period has ended.
+// It returns the number of accounts deleted.
+func (s *AccountService) PurgeDueAccounts(ctx context.Context) (int, error) {
+	users, err := s.userRepo.ListDueForDeletion(ctx, time.Now(), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for _, user := range users {
+		if err := s.accountRepo.AnonymizeAndDelete(ctx, user.ID.String()); err != nil {
+			slog.ErrorContext(ctx, "failed to delete account", "user_id", user.ID, "error", err)
+			continue
+		}
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+// RegisterJobs schedules PurgeDueAccounts on queue every deletion sweep interval
+func (s *AccountService) RegisterJobs(queue *jobs.Queue) error {
+	jobs.Handle(queue, purgeJob, func(ctx context.Context, _ struct{}) error {
+		deleted, err := s.PurgeDueAccounts(ctx)
+		if deleted > 0 {
+			slog.InfoContext(ctx, "deleted accounts after grace period", "count", deleted)
+		}
+		return err
+	})
+	return jobs.Repeat(queue, purgeJob, jobs.Every(s.cfg.DeletionSweepInterval), struct{}{})
+}
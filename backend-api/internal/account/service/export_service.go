@@ -0,0 +1,357 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/account/dto"
+	"karigar-backend/internal/audit"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/jobs"
+)
+
+var (
+	ErrExportNotFound     = apperror.New(apperror.NotFound, "export_not_found", "data export not found")
+	ErrExportInProgress   = apperror.New(apperror.Conflict, "export_in_progress", "a data export is already in progress")
+	ErrExportNotReady     = apperror.New(apperror.Conflict, "export_not_ready", "data export is not ready for download")
+	ErrInvalidDownloadURL = apperror.New(apperror.Forbidden, "invalid_download_url", "download link is invalid or has expired")
+	ErrUserNotFound       = apperror.New(apperror.NotFound, "user_not_found", "user not found")
+)
+
+const exportBatchSize = 100
+
+// exportJob is the payload of buildExportJob
+type exportJob struct {
+	ExportID string `json:"export_id"`
+}
+
+var (
+	// buildExportJob builds the archive of one export
+	buildExportJob = jobs.NewKind[exportJob]("exports.build")
+	// sweepExportsJob removes expired archives and queues exports left without a job
+	sweepExportsJob = jobs.NewKind[struct{}]("exports.sweep")
+)
+
+// ExportService builds personal data archives in the background and serves them
+// through signed, expiring download links. Archives are stored in the database,
+// so any instance can serve one whichever instance built it.
+type ExportService struct {
+	userRepo     repository.UserRepository
+	exportRepo   repository.DataExportRepository
+	personalRepo repository.PersonalDataRepository
+	audit        *audit.Recorder
+	cfg          config.ExportConfig
+	queue        *jobs.Queue
+}
+
+// NewExportService creates a new export service
+func NewExportService(userRepo repository.UserRepository, exportRepo repository.DataExportRepository, personalRepo repository.PersonalDataRepository, recorder *audit.Recorder, queue *jobs.Queue, cfg *config.Config) *ExportService {
+	return &ExportService{
+		userRepo:     userRepo,
+		exportRepo:   exportRepo,
+		personalRepo: personalRepo,
+		audit:        recorder,
+		cfg:          cfg.Export,
+		queue:        queue,
+	}
+}
+
+// RequestExport queues a new export of the user's data. requestedBy is the admin
+// acting on the user's behalf, or the user themselves for self-service exports.
+func (s *ExportService) RequestExport(ctx context.Context, userID, requestedBy string) (*dto.ExportResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	actorID, err := uuid.Parse(requestedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	exports, err := s.exportRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, existing := range exports {
+		if existing.Status == domain.ExportStatusPending || existing.Status == domain.ExportStatusProcessing {
+			return nil, ErrExportInProgress
+		}
+	}
+
+	export := &domain.DataExport{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		Status: domain.ExportStatusPending,
+	}
+	if actorID != user.ID {
+		export.RequestedBy = &actorID
+	}
+	if err := s.exportRepo.Create(ctx, export); err != nil {
+		return nil, err
+	}
+
+	s.audit.RecordBy(ctx, user.ID, actorID, domain.AuditDataExportRequested, map[string]interface{}{"export_id": export.ID.String()})
+	s.enqueue(ctx, export.ID.String())
+
+	return s.newExportResponse(export), nil
+}
+
+// ListExports lists the user's exports, newest first
+func (s *ExportService) ListExports(ctx context.Context, userID string) ([]*dto.ExportResponse, error) {
+	exports, err := s.exportRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ExportResponse, 0, len(exports))
+	for _, export := range exports {
+		responses = append(responses, s.newExportResponse(export))
+	}
+
+	return responses, nil
+}
+
+// GetExport gets one of the user's exports
+func (s *ExportService) GetExport(ctx context.Context, userID, exportID string) (*dto.ExportResponse, error) {
+	export, err := s.exportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+	if export.UserID.String() != userID {
+		return nil, ErrExportNotFound
+	}
+
+	return s.newExportResponse(export), nil
+}
+
+// OpenDownload checks a signed download link and returns the export and its archive
+func (s *ExportService) OpenDownload(ctx context.Context, exportID string, expires int64, signature string) (*domain.DataExport, []byte, error) {
+	if time.Now().Unix() > expires || !hmac.Equal([]byte(signature), []byte(s.sign(exportID, expires))) {
+		return nil, nil, ErrInvalidDownloadURL
+	}
+
+	export, err := s.exportRepo.GetByID(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrExportNotFound
+		}
+		return nil, nil, err
+	}
+	if export.Status != domain.ExportStatusCompleted {
+		return nil, nil, ErrExportNotReady
+	}
+	archive, err := s.exportRepo.GetArchive(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, nil, ErrExportNotReady
+		}
+		return nil, nil, err
+	}
+
+	s.audit.Record(ctx, export.UserID, domain.AuditDataExportDownloaded, map[string]interface{}{"export_id": export.ID.String()})
+
+	return export, archive, nil
+}
+
+// RegisterJobs registers the export jobs on the queue: building archives, and
+// a sweep every sweep interval that removes expired archives and queues
+// exports that were never queued
+func (s *ExportService) RegisterJobs(queue *jobs.Queue) error {
+	jobs.Handle(queue, buildExportJob, func(ctx context.Context, job exportJob) error {
+		return s.process(ctx, job.ExportID)
+	})
+	jobs.Handle(queue, sweepExportsJob, func(ctx context.Context, _ struct{}) error {
+		s.sweepExpired(ctx)
+		return s.requeue(ctx)
+	})
+	return jobs.Repeat(queue, sweepExportsJob, jobs.Every(s.cfg.SweepInterval), struct{}{})
+}
+
+// enqueue queues the job that builds an export. An export that fails to be
+// queued stays pending and is queued by the next sweep.
+func (s *ExportService) enqueue(ctx context.Context, id string) {
+	_, err := jobs.Enqueue(ctx, s.queue, buildExportJob, exportJob{ExportID: id}, jobs.UniqueKey("export:"+id))
+	if err != nil && !errors.Is(err, jobs.ErrDuplicate) {
+		slog.ErrorContext(ctx, "failed to queue export, it will be queued on the next sweep", "export_id", id, "error", err)
+	}
+}
+
+// requeue queues unfinished exports that have no job, e.g. because queueing
+// failed when they were requested; the unique key skips the others
+func (s *ExportService) requeue(ctx context.Context) error {
+	for _, status := range []domain.ExportStatus{domain.ExportStatusPending, domain.ExportStatusProcessing} {
+		exports, err := s.exportRepo.ListByStatus(ctx, status, exportBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, export := range exports {
+			s.enqueue(ctx, export.ID.String())
+		}
+	}
+
+	return nil
+}
+
+// process builds the archive for a single export. Failures are returned so the
+// job is retried; the export is only marked as failed on the last attempt.
+func (s *ExportService) process(ctx context.Context, id string) error {
+	export, err := s.exportRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			// The user was deleted since
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	if export.Status != domain.ExportStatusPending && export.Status != domain.ExportStatusProcessing {
+		return nil
+	}
+
+	now := time.Now()
+	export.Status = domain.ExportStatusProcessing
+	export.StartedAt = &now
+	if err := s.exportRepo.Update(ctx, export); err != nil {
+		return fmt.Errorf("failed to start export: %w", err)
+	}
+
+	archive, err := s.buildArchive(ctx, export)
+	if err != nil {
+		if jobs.LastAttempt(ctx) {
+			export.Status = domain.ExportStatusFailed
+			export.Error = "failed to build export archive"
+			if err := s.exportRepo.Update(ctx, export); err != nil {
+				slog.ErrorContext(ctx, "failed to mark export as failed", "export_id", id, "error", err)
+			}
+		}
+		return fmt.Errorf("failed to build export archive: %w", err)
+	}
+
+	completedAt := time.Now()
+	expiresAt := completedAt.Add(s.cfg.LinkTTL)
+	export.Status = domain.ExportStatusCompleted
+	export.FileSize = int64(len(archive))
+	export.Error = ""
+	export.CompletedAt = &completedAt
+	export.ExpiresAt = &expiresAt
+	if err := s.exportRepo.Complete(ctx, export, archive); err != nil {
+		return fmt.Errorf("failed to complete export: %w", err)
+	}
+
+	return nil
+}
+
+// buildArchive builds the user's data as a zip of JSON files, one per section
+func (s *ExportService) buildArchive(ctx context.Context, export *domain.DataExport) ([]byte, error) {
+	data, err := s.personalRepo.Collect(ctx, export.UserID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"export.json", map[string]interface{}{
+			"export_id":    export.ID,
+			"user_id":      export.UserID,
+			"generated_at": time.Now().UTC(),
+		}},
+		{"user.json", data.User},
+		{"customer_profile.json", data.CustomerProfile},
+		{"provider_profile.json", data.ProviderProfile},
+		{"services.json", data.Services},
+		{"bookings.json", data.Bookings},
+		{"booking_events.json", data.BookingEvents},
+		{"reviews_written.json", data.ReviewsWritten},
+		{"reviews_received.json", data.ReviewsReceived},
+		{"availability.json", data.Availability},
+		{"audit_events.json", data.AuditEvents},
+		{"sessions.json", data.Sessions},
+	}
+	for _, f := range files {
+		w, err := archive.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(f.content); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// sweepExpired deletes archives whose download link has expired
+func (s *ExportService) sweepExpired(ctx context.Context) {
+	exports, err := s.exportRepo.ListExpired(ctx, time.Now(), exportBatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to list expired exports", "error", err)
+		return
+	}
+
+	for _, export := range exports {
+		if err := s.exportRepo.Expire(ctx, export); err != nil {
+			slog.ErrorContext(ctx, "failed to expire export", "export_id", export.ID, "error", err)
+		}
+	}
+}
+
+// newExportResponse builds the API representation, including a signed download
+// link once the archive is ready
+func (s *ExportService) newExportResponse(export *domain.DataExport) *dto.ExportResponse {
+	response := &dto.ExportResponse{
+		ID:          export.ID.String(),
+		Status:      string(export.Status),
+		FileSize:    export.FileSize,
+		Error:       export.Error,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+
+	if export.Status == domain.ExportStatusCompleted && export.ExpiresAt != nil {
+		expires := export.ExpiresAt.Unix()
+		query := url.Values{}
+		query.Set("expires", strconv.FormatInt(expires, 10))
+		query.Set("signature", s.sign(response.ID, expires))
+		response.DownloadURL = "/api/v1/exports/" + response.ID + "/download?" + query.Encode()
+	}
+
+	return response
+}
+
+// sign computes the download link signature for an export id and expiry
+func (s *ExportService) sign(exportID string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.SigningKey))
+	mac.Write([]byte(exportID + ":" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
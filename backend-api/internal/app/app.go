@@ -0,0 +1,356 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	accountservice "karigar-backend/internal/account/service"
+	"karigar-backend/internal/audit"
+	authservice "karigar-backend/internal/auth/service"
+	bookingservice "karigar-backend/internal/booking/service"
+	"karigar-backend/internal/config"
+	healthservice "karigar-backend/internal/health/service"
+	jobservice "karigar-backend/internal/jobs/service"
+	providerservice "karigar-backend/internal/provider/service"
+	"karigar-backend/internal/repository"
+	"karigar-backend/internal/repository/cached"
+	"karigar-backend/internal/repository/postgres"
+	"karigar-backend/pkg/database"
+	"karigar-backend/pkg/geoip"
+	"karigar-backend/pkg/jobs"
+	"karigar-backend/pkg/redis"
+	"karigar-backend/pkg/sms"
+	"karigar-backend/pkg/tracing"
+	"karigar-backend/pkg/validator"
+
+	"github.com/gin-gonic/gin"
+)
+
+// App wires configuration, infrastructure, repositories, services and the HTTP
+// router. Everything is passed explicitly through constructors, so several
+// Apps (e.g. in parallel tests) can run side by side.
+type App struct {
+	Config *config.Config
+	DB     *sql.DB
+	Store  redis.Store // Redis, or an in-memory store when Redis is disabled
+	Jobs   *jobs.Queue
+	Router *gin.Engine
+
+	Repositories *Repositories
+	Services     *Services
+
+	closers []func(context.Context) error
+}
+
+// Repositories holds the data access layer
+type Repositories struct {
+	Users                repository.UserRepository
+	UserSessions         repository.UserSessionRepository
+	MFA                  repository.MFARepository
+	PasswordHistory      repository.PasswordHistoryRepository
+	Accounts             repository.AccountRepository
+	Audit                repository.AuditRepository
+	DataExports          repository.DataExportRepository
+	PersonalData         repository.PersonalDataRepository
+	Providers            repository.ServiceProviderRepository // Cached
+	Services             repository.ServiceRepository         // Cached
+	Availability         repository.AvailabilityRepository    // Cached
+	Reviews              repository.ReviewRepository          // Invalidates cached providers
+	Customers            repository.CustomerRepository
+	ServiceRequests      repository.ServiceRequestRepository // Invalidates cached providers
+	CancellationPolicies repository.CancellationPolicyRepository
+	RescheduleProposals  repository.RescheduleProposalRepository
+	ServiceRequestEvents repository.ServiceRequestEventRepository
+}
+
+// Services holds the business layer
+type Services struct {
+	Audit     *audit.Recorder
+	Passwords *authservice.PasswordService
+	OTP       *authservice.OTPService
+	MFA       *authservice.MFAService
+	Auth      *authservice.AuthService
+	Sessions  *authservice.SessionService
+	Exports   *accountservice.ExportService
+	Accounts  *accountservice.AccountService
+	Health    *healthservice.HealthService
+	Providers *providerservice.ProviderService
+	Bookings  *bookingservice.BookingService
+	Jobs      *jobservice.JobService
+}
+
+// New connects to the database (running pending migrations) and Redis, and
+// builds the repositories, services and router. Call Close when done, also
+// when New fails part way.
+func New(ctx context.Context, cfg *config.Config) (*App, error) {
+	a := &App{Config: cfg}
+
+	// Set up tracing before connecting so database and Redis calls are instrumented
+	shutdownTracing, err := tracing.Setup(ctx, &cfg.Tracing, cfg.Server.Environment)
+	if err != nil {
+		return a, fmt.Errorf("failed to set up tracing: %w", err)
+	}
+	a.closers = append(a.closers, shutdownTracing)
+
+	// Connect to database
+	a.DB, err = database.Connect(&cfg.Database)
+	if err != nil {
+		return a, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	a.closers = append(a.closers, func(context.Context) error { return a.DB.Close() })
+
+	if err := database.RegisterMetrics(a.DB, cfg.Database.DBName); err != nil {
+		slog.WarnContext(ctx, "failed to register database metrics", "error", err)
+	}
+
+	schemaVersion := a.migrate(ctx)
+	healthChecks := []healthservice.Check{
+		healthservice.DatabaseCheck(a.DB),
+		healthservice.MigrationCheck(a.DB, schemaVersion),
+	}
+
+	// Connect to Redis (cache, rate limits, locks, pub/sub, optionally jobs). The server starts
+	// even if Redis is unreachable; requests that need it fail with a 503 until
+	// it is back, and readiness reports it.
+	var client *redis.Client
+	if cfg.Redis.Enabled {
+		client = redis.New(&redis.Config{
+			Host:      cfg.Redis.Host,
+			Port:      cfg.Redis.Port,
+			Password:  cfg.Redis.Password,
+			DB:        cfg.Redis.DB,
+			Namespace: cfg.Redis.Namespace,
+		})
+		healthChecks = append(healthChecks, healthservice.RedisCheck(client))
+
+		if err := client.Ping(ctx); err != nil {
+			slog.WarnContext(ctx, "failed to connect to Redis; features that need it are unavailable until it is reachable", "error", err)
+		} else {
+			slog.InfoContext(ctx, "connected to Redis")
+		}
+		a.Store = client
+	} else {
+		slog.InfoContext(ctx, "Redis is disabled; using an in-memory store, which is not shared between instances")
+		a.Store = redis.NewMemoryStore()
+	}
+	a.closers = append(a.closers, func(context.Context) error { return a.Store.Close() })
+
+	// Register custom request validation rules
+	if err := validator.Register(cfg.OTP.DefaultCountryCode); err != nil {
+		return a, fmt.Errorf("failed to register validators: %w", err)
+	}
+
+	if a.Jobs, err = newQueue(cfg, a.DB, client); err != nil {
+		return a, err
+	}
+
+	a.Repositories = newRepositories(a.DB, cached.NewCache(a.Store, cfg))
+	if a.Services, err = newServices(cfg, a.Repositories, a.Store, a.Jobs, healthChecks); err != nil {
+		return a, err
+	}
+	if a.Router, err = newRouter(cfg, a.Services, redis.NewCacheService(a.Store)); err != nil {
+		return a, err
+	}
+
+	return a, nil
+}
+
+// migrate runs pending migrations and returns the schema version readiness
+// expects (the newest migration file). Failures are logged rather than fatal:
+// the server still starts but does not report ready.
+func (a *App) migrate(ctx context.Context) string {
+	var schemaVersion string
+	migrationsDir, err := database.FindMigrationsDir()
+	if err == nil {
+		schemaVersion, err = database.LatestMigration(migrationsDir)
+	}
+	if err == nil {
+		slog.InfoContext(ctx, "running database migrations")
+		err = database.RunMigrations(a.DB, migrationsDir)
+	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to run migrations; server will continue, but will not report ready until migrations are run manually", "error", err)
+	} else {
+		slog.InfoContext(ctx, "database migrations completed", "version", schemaVersion)
+	}
+
+	return schemaVersion
+}
+
+// newQueue creates the background job queue on the configured backend
+func newQueue(cfg *config.Config, db *sql.DB, client *redis.Client) (*jobs.Queue, error) {
+	var store jobs.Store
+	switch cfg.Jobs.Backend {
+	case "postgres":
+		store = jobs.NewPostgresStore(db)
+	case "redis":
+		if client == nil {
+			return nil, errors.New("the redis job backend requires REDIS_ENABLED")
+		}
+		store = jobs.NewRedisStore(client)
+	default:
+		return nil, fmt.Errorf("unknown job backend %q", cfg.Jobs.Backend)
+	}
+
+	return jobs.NewQueue(store, jobs.Config{
+		Concurrency:  cfg.Jobs.Concurrency,
+		PollInterval: cfg.Jobs.PollInterval,
+		Timeout:      cfg.Jobs.Timeout,
+		MaxAttempts:  cfg.Jobs.MaxAttempts,
+		BackoffBase:  cfg.Jobs.BackoffBase,
+		BackoffMax:   cfg.Jobs.BackoffMax,
+		DrainTimeout: cfg.Jobs.DrainTimeout,
+		Retention:    cfg.Jobs.Retention,
+	}), nil
+}
+
+func newRepositories(db *sql.DB, cache *cached.Cache) *Repositories {
+	return &Repositories{
+		Users:                postgres.NewUserRepository(db),
+		UserSessions:         postgres.NewUserSessionRepository(db),
+		MFA:                  postgres.NewMFARepository(db),
+		PasswordHistory:      postgres.NewPasswordHistoryRepository(db),
+		Accounts:             postgres.NewAccountRepository(db),
+		Audit:                postgres.NewAuditRepository(db),
+		DataExports:          postgres.NewDataExportRepository(db),
+		PersonalData:         postgres.NewPersonalDataRepository(db),
+		Providers:            cached.NewServiceProviderRepository(postgres.NewServiceProviderRepository(db), cache),
+		Services:             cached.NewServiceRepository(postgres.NewServiceRepository(db), cache),
+		Availability:         cached.NewAvailabilityRepository(postgres.NewAvailabilityRepository(db), cache),
+		Reviews:              cached.NewReviewRepository(postgres.NewReviewRepository(db), cache),
+		Customers:            postgres.NewCustomerRepository(db),
+		ServiceRequests:      cached.NewServiceRequestRepository(postgres.NewServiceRequestRepository(db), cache),
+		CancellationPolicies: postgres.NewCancellationPolicyRepository(db),
+		RescheduleProposals:  postgres.NewRescheduleProposalRepository(db),
+		ServiceRequestEvents: postgres.NewServiceRequestEventRepository(db),
+	}
+}
+
+func newServices(cfg *config.Config, repos *Repositories, store redis.Store, queue *jobs.Queue, healthChecks []healthservice.Check) (*Services, error) {
+	smsSender, err := sms.NewSender(cfg.SMS.Provider, cfg.SMS.FilePath, cfg.Server.Environment)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize SMS sender: %w", err)
+	}
+	locator, err := geoip.NewLocator(cfg.GeoIP.Provider, cfg.GeoIP.FilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize GeoIP locator: %w", err)
+	}
+
+	s := &Services{
+		Audit:     audit.NewRecorder(repos.Audit),
+		OTP:       authservice.NewOTPService(store, smsSender, cfg),
+		Sessions:  authservice.NewSessionService(store, repos.UserSessions, cfg),
+		Health:    healthservice.NewHealthService(cfg, healthChecks...),
+		Providers: providerservice.NewProviderService(repos.Providers, repos.Services, repos.Availability, repos.CancellationPolicies, cfg),
+		Jobs:      jobservice.NewJobService(queue),
+	}
+
+	if s.Bookings, err = bookingservice.NewBookingService(repos.ServiceRequests, repos.Customers, repos.Providers, repos.Services, repos.CancellationPolicies, repos.Availability, repos.RescheduleProposals, repos.ServiceRequestEvents, bookingservice.LogBookingNotifier{}, cfg); err != nil {
+		return nil, fmt.Errorf("failed to initialize booking service: %w", err)
+	}
+	if s.Passwords, err = authservice.NewPasswordService(repos.Users, repos.PasswordHistory, cfg); err != nil {
+		return nil, fmt.Errorf("failed to initialize password service: %w", err)
+	}
+	if s.MFA, err = authservice.NewMFAService(repos.Users, repos.MFA, store, s.Audit, cfg); err != nil {
+		return nil, fmt.Errorf("failed to initialize MFA service: %w", err)
+	}
+	s.Auth = authservice.NewAuthService(repos.Users, repos.UserSessions, s.Passwords, s.OTP, s.MFA, s.Audit, locator, authservice.LogLoginNotifier{}, cfg)
+	s.Exports = accountservice.NewExportService(repos.Users, repos.DataExports, repos.PersonalData, s.Audit, queue, cfg)
+	s.Accounts = accountservice.NewAccountService(repos.Users, repos.Accounts, s.Passwords, s.Audit, cfg)
+
+	// Background work runs as jobs on the queue
+	for _, register := range []func(*jobs.Queue) error{
+		s.Accounts.RegisterJobs,
+		s.Exports.RegisterJobs,
+		s.Bookings.RegisterJobs,
+		s.Providers.RegisterJobs,
+	} {
+		if err := register(queue); err != nil {
+			return nil, fmt.Errorf("failed to register jobs: %w", err)
+		}
+	}
+
+	return s, nil
+}
+
+// Run starts the job queue and the HTTP server and blocks until ctx is
+// cancelled (e.g. on SIGTERM). Readiness is then failed for the configured drain
+// delay before in-flight requests and running jobs are drained.
+func (a *App) Run(ctx context.Context) error {
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	workersDone := make(chan struct{})
+	go func() {
+		a.Jobs.Run(workerCtx)
+		close(workersDone)
+	}()
+	// However Run returns, running jobs finish (or are cancelled once the jobs
+	// drain timeout passes) before Close releases the database and Redis
+	defer func() {
+		stopWorkers()
+		slog.Info("waiting for running jobs", "timeout", a.Config.Jobs.DrainTimeout.String())
+		<-workersDone
+	}()
+
+	addr := fmt.Sprintf("%s:%s", a.Config.Server.Host, a.Config.Server.Port)
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           a.Router,
+		ReadHeaderTimeout: a.Config.Server.ReadHeaderTimeout,
+		ReadTimeout:       a.Config.Server.ReadTimeout,
+		WriteTimeout:      a.Config.Server.WriteTimeout,
+		IdleTimeout:       a.Config.Server.IdleTimeout,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server starting", "addr", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	select {
+	case err := <-serverErr:
+		return fmt.Errorf("failed to start server: %w", err)
+	case <-ctx.Done():
+	}
+
+	// Fail readiness first and give load balancers time to stop routing new
+	// requests here before in-flight requests are drained
+	slog.Info("draining", "delay", a.Config.Server.DrainDelay.String())
+	a.Services.Health.Drain()
+	time.Sleep(a.Config.Server.DrainDelay)
+
+	slog.Info("shutting down server")
+	stopWorkers()
+
+	// In-flight requests cannot take longer than the write timeout
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.Config.Server.WriteTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("server forced to shutdown: %w", err)
+	}
+
+	slog.Info("server exited")
+	return nil
+}
+
+// Close releases what New acquired, in reverse order. It is safe to call twice.
+func (a *App) Close() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i](ctx); err != nil {
+			slog.Warn("failed to release resource", "error", err)
+		}
+	}
+	a.closers = nil
+}
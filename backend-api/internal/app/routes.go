@@ -0,0 +1,218 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	accounthandler "karigar-backend/internal/account/handler"
+	"karigar-backend/internal/audit"
+	authhandler "karigar-backend/internal/auth/handler"
+	bookinghandler "karigar-backend/internal/booking/handler"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	healthhandler "karigar-backend/internal/health/handler"
+	jobhandler "karigar-backend/internal/jobs/handler"
+	"karigar-backend/internal/middleware"
+	providerhandler "karigar-backend/internal/provider/handler"
+	"karigar-backend/pkg/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// authBodyLimit caps request bodies on the public auth routes, which only take
+// small forms such as credentials and codes
+const authBodyLimit = 16 << 10
+
+// newRouter sets up middleware and routes
+func newRouter(cfg *config.Config, services *Services, limiter middleware.RateLimiter) (*gin.Engine, error) {
+	// Set Gin mode based on environment
+	if cfg.Server.Environment == "production" {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	router := gin.New()
+
+	// Only believe forwarded client IPs from our own proxies; audit logs, login
+	// sessions and rate limits rely on them
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.TrustedPlatform = cfg.Server.TrustedPlatform
+
+	// Add middleware
+	router.Use(middleware.Tracing(cfg.Tracing.ServiceName))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.RequestLogger())
+	router.Use(middleware.Metrics())
+	router.Use(middleware.Recovery(cfg))
+	router.Use(middleware.ErrorHandler(cfg))
+	router.Use(middleware.SecurityHeaders(&cfg.Security))
+	router.Use(middleware.CORS(middleware.NewCORSPolicy(&cfg.CORS), corsRoutes()...))
+	router.Use(middleware.BodyLimit(cfg.Security.MaxBodyBytes))
+	router.Use(audit.Middleware())
+
+	// Health check endpoints
+	healthHandler := healthhandler.NewHealthHandler(services.Health)
+	router.GET("/livez", healthHandler.Livez)
+	router.GET("/readyz", healthHandler.Readyz)
+	router.GET("/health", healthHandler.Readyz) // Deprecated alias of /readyz
+
+	// Prometheus metrics
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Initialize handlers
+	authHandler := authhandler.NewAuthHandler(services.Auth)
+	mfaHandler := authhandler.NewMFAHandler(services.Auth, services.MFA)
+	sessionHandler := authhandler.NewSessionHandler(services.Auth, services.Sessions, cfg)
+	accountHandler := accounthandler.NewAccountHandler(services.Accounts)
+	exportHandler := accounthandler.NewExportHandler(services.Exports)
+	providerHandler := providerhandler.NewProviderHandler(services.Providers)
+	bookingHandler := bookinghandler.NewBookingHandler(services.Bookings)
+	jobHandler := jobhandler.NewJobHandler(services.Jobs)
+
+	authRateLimit := middleware.RateLimit("auth", limiter, cfg.RateLimit.Auth, cfg.RateLimit.Window)
+	publicRateLimit := middleware.RateLimit("public", limiter, cfg.RateLimit.Public, cfg.RateLimit.Window)
+
+	// API routes
+	api := router.Group("/api/v1")
+	{
+		// Auth routes (public)
+		auth := api.Group("/auth")
+		auth.Use(authRateLimit, middleware.BodyLimit(authBodyLimit))
+		{
+			auth.POST("/register", authHandler.Register)
+			auth.POST("/login", authHandler.Login)
+			auth.POST("/refresh", authHandler.RefreshToken)
+			auth.POST("/verify-email", authHandler.VerifyEmail)
+			auth.POST("/forgot-password", authHandler.ForgotPassword)
+			auth.POST("/reset-password", authHandler.ResetPassword)
+			auth.POST("/phone/send-otp", authHandler.SendLoginOTP)
+			auth.POST("/phone/login", authHandler.PhoneLogin)
+			auth.POST("/mfa/verify", mfaHandler.VerifyChallenge)
+			auth.POST("/mfa/enroll", mfaHandler.BeginEnrollmentWithToken)
+			auth.POST("/mfa/enroll/confirm", mfaHandler.ConfirmEnrollmentWithToken)
+		}
+
+		// Cookie sessions, the alternative to bearer tokens for browser clients
+		session := api.Group("/auth/session")
+		session.Use(middleware.BodyLimit(authBodyLimit))
+		{
+			session.POST("/login", authRateLimit, sessionHandler.Login)
+			session.POST("/phone/login", authRateLimit, sessionHandler.PhoneLogin)
+			session.POST("/mfa/verify", authRateLimit, sessionHandler.VerifyMFA)
+			session.POST("/mfa/enroll/confirm", authRateLimit, sessionHandler.ConfirmMFAEnrollment)
+			session.GET("", middleware.SessionMiddleware(cfg, services.Sessions), sessionHandler.Get)
+			session.DELETE("", middleware.SessionMiddleware(cfg, services.Sessions), sessionHandler.Logout)
+		}
+
+		// Provider search and profiles (public)
+		providers := api.Group("/providers")
+		providers.Use(publicRateLimit)
+		{
+			providers.GET("", providerHandler.Search)
+			providers.GET("/:id", providerHandler.GetProvider)
+			providers.GET("/:id/services", providerHandler.ListServices)
+			providers.GET("/:id/cancellation-policy", providerHandler.GetCancellationPolicy)
+		}
+
+		// Export downloads are authorized by the signed link
+		api.GET("/exports/:id/download", exportHandler.Download)
+
+		// Protected routes, authenticated by bearer token or session cookie
+		protected := api.Group("")
+		protected.Use(middleware.Authenticate(cfg, services.Sessions))
+		{
+			me := protected.Group("/me")
+			{
+				me.DELETE("", accountHandler.RequestDeletion)
+				me.POST("/deletion/cancel", accountHandler.CancelDeletion)
+				me.POST("/password", authHandler.ChangePassword)
+				me.GET("/sessions", authHandler.ListSessions)
+				me.DELETE("/sessions/:id", authHandler.RevokeSession)
+				me.POST("/phone/send-otp", authHandler.SendPhoneVerificationOTP)
+				me.POST("/phone/verify", authHandler.VerifyPhone)
+				me.POST("/mfa/enroll", mfaHandler.BeginEnrollment)
+				me.POST("/mfa/enroll/confirm", mfaHandler.ConfirmEnrollment)
+				me.POST("/mfa/disable", mfaHandler.Disable)
+				me.POST("/mfa/recovery-codes", mfaHandler.RegenerateRecoveryCodes)
+				me.POST("/exports", exportHandler.RequestExport)
+				me.GET("/exports", exportHandler.ListExports)
+				me.GET("/exports/:id", exportHandler.GetExport)
+			}
+
+			bookings := me.Group("/bookings")
+			bookings.Use(middleware.RequireRole(string(domain.RoleCustomer)))
+			{
+				bookings.POST("", bookingHandler.Create)
+				bookings.GET("", bookingHandler.ListCustomerBookings)
+				bookings.GET("/:id", bookingHandler.GetCustomerBooking)
+				bookings.POST("/:id/cancel", bookingHandler.CancelAsCustomer)
+				bookings.POST("/:id/no-show", bookingHandler.ReportNoShow)
+				bookings.GET("/:id/reschedules", bookingHandler.ListCustomerReschedules)
+				bookings.POST("/:id/reschedules", bookingHandler.ProposeRescheduleAsCustomer)
+				bookings.POST("/:id/reschedules/respond", bookingHandler.RespondToRescheduleAsCustomer)
+				bookings.GET("/:id/timeline", bookingHandler.GetCustomerTimeline)
+				bookings.POST("/:id/notes", bookingHandler.AddCustomerNote)
+			}
+
+			provider := me.Group("/provider")
+			provider.Use(middleware.RequireRole(string(domain.RoleServiceProvider)))
+			{
+				provider.GET("", providerHandler.GetProfile)
+				provider.PUT("", providerHandler.UpdateProfile)
+				provider.GET("/services", providerHandler.ListOwnServices)
+				provider.POST("/services", providerHandler.CreateService)
+				provider.PUT("/services/:id", providerHandler.UpdateService)
+				provider.DELETE("/services/:id", providerHandler.DeleteService)
+				provider.GET("/availability", providerHandler.ListAvailability)
+				provider.POST("/availability", providerHandler.AddAvailability)
+				provider.DELETE("/availability/:id", providerHandler.DeleteAvailability)
+				provider.GET("/bookings", bookingHandler.ListProviderBookings)
+				provider.GET("/bookings/:id", bookingHandler.GetProviderBooking)
+				provider.POST("/bookings/:id/confirm", bookingHandler.Confirm)
+				provider.POST("/bookings/:id/complete", bookingHandler.Complete)
+				provider.POST("/bookings/:id/cancel", bookingHandler.CancelAsProvider)
+				provider.GET("/bookings/:id/reschedules", bookingHandler.ListProviderReschedules)
+				provider.POST("/bookings/:id/reschedules", bookingHandler.ProposeRescheduleAsProvider)
+				provider.POST("/bookings/:id/reschedules/respond", bookingHandler.RespondToRescheduleAsProvider)
+				provider.GET("/bookings/:id/timeline", bookingHandler.GetProviderTimeline)
+				provider.POST("/bookings/:id/notes", bookingHandler.AddProviderNote)
+				provider.PUT("/bookings/:id/price", bookingHandler.ChangePrice)
+				provider.GET("/analytics", bookingHandler.GetProviderAnalytics)
+				provider.GET("/cancellation-policy", providerHandler.GetOwnCancellationPolicy)
+				provider.PUT("/cancellation-policy", providerHandler.ReplaceCancellationPolicy)
+			}
+
+			admin := protected.Group("/admin")
+			admin.Use(middleware.RequireRole(string(domain.RoleAdmin)))
+			{
+				admin.GET("/mfa-policies", mfaHandler.ListPolicies)
+				admin.PUT("/mfa-policies/:role", mfaHandler.SetPolicy)
+				admin.POST("/users/:id/exports", exportHandler.AdminRequestExport)
+				admin.GET("/users/:id/exports", exportHandler.AdminListExports)
+				admin.GET("/analytics/bookings", bookingHandler.GetAnalytics)
+				admin.GET("/jobs", jobHandler.ListJobs)
+				admin.GET("/health", healthHandler.Debug)
+			}
+		}
+	}
+
+	return router, nil
+}
+
+// corsRoutes lists the routes whose cross-origin policy differs from the
+// configured default
+func corsRoutes() []middleware.CORSRoute {
+	// Provider search and profiles are public and read-only; any site may embed them
+	public := middleware.CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "HEAD"},
+		AllowedHeaders: []string{"Content-Type", "X-Request-ID", "traceparent", "tracestate"},
+		ExposedHeaders: []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"},
+		MaxAge:         time.Hour,
+	}
+
+	return []middleware.CORSRoute{
+		{PathPrefix: "/api/v1/providers", Policy: public},
+	}
+}
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+type contextKey struct{}
+
+// client identifies where a request came from
+type client struct {
+	ip        string
+	userAgent string
+}
+
+// WithClient returns a context carrying the client IP address and user agent,
+// which are attached to audit events recorded with it
+func WithClient(ctx context.Context, ip, userAgent string) context.Context {
+	return context.WithValue(ctx, contextKey{}, client{ip: ip, userAgent: userAgent})
+}
+
+// ClientFromContext returns the client IP address and user agent stored by
+// WithClient, or empty strings outside a request
+func ClientFromContext(ctx context.Context) (ip, userAgent string) {
+	c, _ := ctx.Value(contextKey{}).(client)
+	return c.ip, c.userAgent
+}
+
+// Middleware stores the client IP address and user agent in the request context
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(WithClient(c.Request.Context(), c.ClientIP(), c.Request.UserAgent()))
+		c.Next()
+	}
+}
+
+// Recorder writes audit events. Recording is best effort: a failure is logged
+// but never fails the action being audited.
+type Recorder struct {
+	repo repository.AuditRepository
+}
+
+// NewRecorder creates a new audit recorder
+func NewRecorder(repo repository.AuditRepository) *Recorder {
+	return &Recorder{
+		repo: repo,
+	}
+}
+
+// Record records an action performed by a user on their own account
+func (r *Recorder) Record(ctx context.Context, userID uuid.UUID, action string, metadata map[string]interface{}) {
+	r.RecordBy(ctx, userID, userID, action, metadata)
+}
+
+// RecordBy records an action performed by actorID on userID's account
+func (r *Recorder) RecordBy(ctx context.Context, userID, actorID uuid.UUID, action string, metadata map[string]interface{}) {
+	if r == nil {
+		return
+	}
+
+	event := &domain.AuditEvent{
+		ID:       uuid.New(),
+		UserID:   &userID,
+		ActorID:  &actorID,
+		Action:   action,
+		Metadata: metadata,
+	}
+	if c, ok := ctx.Value(contextKey{}).(client); ok {
+		event.IPAddress = c.ip
+		event.UserAgent = c.userAgent
+	}
+
+	if err := r.repo.Create(ctx, event); err != nil {
+		slog.ErrorContext(ctx, "failed to record audit event", "action", action, "user_id", userID, "error", err)
+	}
+}
@@ -1,12 +1,23 @@
 package dto
 
-import "karigar-backend/internal/domain"
+import (
+	"time"
 
-// AuthResponse represents the authentication response
+	"karigar-backend/internal/domain"
+)
+
+// AuthResponse represents the authentication response.
+// When a second factor is needed, only the MFA fields are set and MFAToken must be
+// exchanged at /auth/mfa/verify (or /auth/mfa/enroll when enrolment is required).
 type AuthResponse struct {
-	AccessToken  string      `json:"access_token"`
-	RefreshToken string      `json:"refresh_token"`
-	User         *UserInfo   `json:"user"`
+	AccessToken  string      `json:"access_token,omitempty"`
+	RefreshToken string      `json:"refresh_token,omitempty"`
+	User         *UserInfo   `json:"user,omitempty"`
+	SessionID    string      `json:"session_id,omitempty"` // Listed at GET /me/sessions
+
+	MFARequired           bool   `json:"mfa_required,omitempty"`
+	MFAEnrollmentRequired bool   `json:"mfa_enrollment_required,omitempty"`
+	MFAToken              string `json:"mfa_token,omitempty"`
 }
 
 // UserInfo represents user information in the response
@@ -15,5 +26,26 @@ type UserInfo struct {
 	Email     string          `json:"email"`
 	Role      domain.UserRole `json:"role"`
 	IsEmailVerified bool      `json:"is_email_verified"`
+	Phone     *string         `json:"phone,omitempty"`
+	IsPhoneVerified bool      `json:"is_phone_verified"`
+	MFAEnabled      bool      `json:"mfa_enabled"`
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
 }
 
+
+// SessionResponse is returned by the cookie session endpoints. The session ID is
+// only ever sent in its HttpOnly cookie; CSRFToken must be echoed in the
+// X-CSRF-Token header of unsafe requests (it is also set as a readable cookie).
+type SessionResponse struct {
+	UserID    string          `json:"user_id"`
+	Role      domain.UserRole `json:"role"`
+	CSRFToken string          `json:"csrf_token"`
+	ExpiresAt time.Time       `json:"expires_at"`
+	User      *UserInfo       `json:"user,omitempty"` // Set on login
+}
+
+// MFAEnrollmentSessionResponse is returned when enrolment completes during a cookie session login
+type MFAEnrollmentSessionResponse struct {
+	*SessionResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -0,0 +1,36 @@
+package dto
+
+import "karigar-backend/internal/domain"
+
+// MFAChallengeRequest represents the request body for completing a two-step login
+type MFAChallengeRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code" binding:"omitempty,numeric,len=6"`
+	RecoveryCode string `json:"recovery_code" binding:"required_without=Code"`
+}
+
+// MFAEnrollRequest represents the request body for starting enrolment with an enrolment token
+type MFAEnrollRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+}
+
+// MFAEnrollConfirmRequest represents the request body for confirming enrolment with an enrolment token
+type MFAEnrollConfirmRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required,numeric,len=6"`
+}
+
+// MFACodeRequest represents a request authorized by a current TOTP code
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required,numeric,len=6"`
+}
+
+// MFAPolicyRequest represents the request body for setting a role's MFA policy
+type MFAPolicyRequest struct {
+	MFARequired *bool `json:"mfa_required" binding:"required"`
+}
+
+// MFAPolicyRoleParam represents the role path parameter for MFA policy endpoints
+type MFAPolicyRoleParam struct {
+	Role domain.UserRole `uri:"role" binding:"required,userrole"`
+}
@@ -0,0 +1,18 @@
+package dto
+
+// MFAEnrollmentResponse contains the TOTP secret for a pending enrolment
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"` // Render as a QR code for authenticator apps
+}
+
+// MFARecoveryCodesResponse contains freshly generated recovery codes. They are only shown once.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+// MFAEnrollmentCompleteResponse is returned when enrolment completes during login
+type MFAEnrollmentCompleteResponse struct {
+	*AuthResponse
+	RecoveryCodes []string `json:"recovery_codes"`
+}
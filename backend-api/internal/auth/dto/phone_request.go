@@ -0,0 +1,18 @@
+package dto
+
+// SendPhoneOTPRequest represents the request body for sending a phone OTP
+type SendPhoneOTPRequest struct {
+	Phone string `json:"phone" binding:"required,phone"`
+}
+
+// VerifyPhoneRequest represents the request body for verifying a phone number with an OTP
+type VerifyPhoneRequest struct {
+	Phone string `json:"phone" binding:"required,phone"`
+	Code  string `json:"code" binding:"required,numeric"`
+}
+
+// PhoneLoginRequest represents the request body for passwordless phone login
+type PhoneLoginRequest struct {
+	Phone string `json:"phone" binding:"required,phone"`
+	Code  string `json:"code" binding:"required,numeric"`
+}
@@ -6,7 +6,7 @@ import "karigar-backend/internal/domain"
 type RegisterRequest struct {
 	Email    string          `json:"email" binding:"required,email"`
 	Password string          `json:"password" binding:"required,min=8"`
-	Role     domain.UserRole `json:"role" binding:"required,oneof=customer service_provider"`
+	Role     domain.UserRole `json:"role" binding:"required,userrole,ne=admin"`
 	Name     string          `json:"name" binding:"required,min=2"`
 }
 
@@ -0,0 +1,22 @@
+package dto
+
+import (
+	"time"
+)
+
+// UserSessionResponse describes a login session and the device it was made from
+type UserSessionResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	Location   string    `json:"location,omitempty"` // Approximate, from the IP address
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // The session making the request
+}
+
+// SessionIDParam binds the id path parameter of session routes
+type SessionIDParam struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
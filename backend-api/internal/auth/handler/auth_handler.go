@@ -1,12 +1,13 @@
 package handler
 
 import (
-	"log"
+	"log/slog"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"karigar-backend/internal/auth/dto"
 	"karigar-backend/internal/auth/service"
+	"karigar-backend/pkg/apperror"
 )
 
 type AuthHandler struct {
@@ -28,34 +29,23 @@ func NewAuthHandler(authService *service.AuthService) *AuthHandler {
 // @Produce json
 // @Param request body dto.RegisterRequest true "Registration request"
 // @Success 201 {object} dto.AuthResponse
-// @Failure 400 {object} map[string]string
-// @Failure 409 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.InvalidRequest(err))
 		return
 	}
 
 	response, err := h.authService.Register(c.Request.Context(), &req)
 	if err != nil {
-		log.Printf("Registration error: %v", err)
-		log.Printf("Error details: %+v", err)
-		switch err {
-		case service.ErrUserAlreadyExists:
-			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
-		default:
-			// Log the actual error for debugging
-			log.Printf("Registration failed with error: %+v", err)
-			// Return detailed error in development, generic in production
-			errorMsg := err.Error()
-			c.JSON(http.StatusInternalServerError, gin.H{"error": errorMsg})
-		}
+		c.Error(err)
 		return
 	}
 
-	log.Printf("User registered successfully: %s", req.Email)
+	slog.InfoContext(c.Request.Context(), "user registered", "role", req.Role)
 
 	c.JSON(http.StatusCreated, response)
 }
@@ -68,26 +58,19 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Produce json
 // @Param request body dto.LoginRequest true "Login request"
 // @Success 200 {object} dto.AuthResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.InvalidRequest(err))
 		return
 	}
 
 	response, err := h.authService.Login(c.Request.Context(), &req)
 	if err != nil {
-		switch err {
-		case service.ErrInvalidCredentials:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		case service.ErrEmailNotVerified:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to login"})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -102,24 +85,19 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Produce json
 // @Param request body dto.RefreshRequest true "Refresh request"
 // @Success 200 {object} dto.AuthResponse
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req dto.RefreshRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.InvalidRequest(err))
 		return
 	}
 
 	response, err := h.authService.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
-		switch err {
-		case service.ErrInvalidToken:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to refresh token"})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -134,26 +112,19 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 // @Produce json
 // @Param request body dto.VerifyEmailRequest true "Verification request"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
 // @Router /auth/verify-email [post]
 func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 	var req dto.VerifyEmailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.InvalidRequest(err))
 		return
 	}
 
 	err := h.authService.VerifyEmail(c.Request.Context(), req.Token)
 	if err != nil {
-		switch err {
-		case service.ErrInvalidToken, service.ErrTokenExpired:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		case service.ErrEmailAlreadyVerified:
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify email"})
-		}
+		c.Error(err)
 		return
 	}
 
@@ -168,18 +139,18 @@ func (h *AuthHandler) VerifyEmail(c *gin.Context) {
 // @Produce json
 // @Param request body dto.ForgotPasswordRequest true "Forgot password request"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
 // @Router /auth/forgot-password [post]
 func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 	var req dto.ForgotPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.InvalidRequest(err))
 		return
 	}
 
 	err := h.authService.ForgotPassword(c.Request.Context(), req.Email)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to process request"})
+		c.Error(err)
 		return
 	}
 
@@ -195,27 +166,219 @@ func (h *AuthHandler) ForgotPassword(c *gin.Context) {
 // @Produce json
 // @Param request body dto.ResetPasswordRequest true "Reset password request"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
 // @Router /auth/reset-password [post]
 func (h *AuthHandler) ResetPassword(c *gin.Context) {
 	var req dto.ResetPasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.Error(apperror.InvalidRequest(err))
 		return
 	}
 
 	err := h.authService.ResetPassword(c.Request.Context(), req.Token, req.Password)
 	if err != nil {
-		switch err {
-		case service.ErrInvalidToken, service.ErrTokenExpired:
-			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-		default:
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset password"})
-		}
+		c.Error(err)
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "password reset successfully"})
 }
 
+
+// SendPhoneVerificationOTP sends a verification code to the authenticated user's phone
+// @Summary Send phone verification code
+// @Description Send an OTP by SMS to verify a phone number for the current user
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.SendPhoneOTPRequest true "Phone OTP request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Failure 429 {object} apperror.Problem
+// @Router /me/phone/send-otp [post]
+func (h *AuthHandler) SendPhoneVerificationOTP(c *gin.Context) {
+	var req dto.SendPhoneOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	err := h.authService.SendPhoneVerificationOTP(c.Request.Context(), c.GetString("user_id"), req.Phone)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "verification code sent"})
+}
+
+// VerifyPhone verifies the authenticated user's phone number
+// @Summary Verify phone number
+// @Description Verify a phone number with the OTP sent by SMS
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.VerifyPhoneRequest true "Verify phone request"
+// @Success 200 {object} dto.UserInfo
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/phone/verify [post]
+func (h *AuthHandler) VerifyPhone(c *gin.Context) {
+	var req dto.VerifyPhoneRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	user, err := h.authService.VerifyPhone(c.Request.Context(), c.GetString("user_id"), req.Phone, req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// SendLoginOTP sends a passwordless login code
+// @Summary Send phone login code
+// @Description Send an OTP by SMS to a verified phone number for passwordless login
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.SendPhoneOTPRequest true "Phone OTP request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} apperror.Problem
+// @Failure 429 {object} apperror.Problem
+// @Router /auth/phone/send-otp [post]
+func (h *AuthHandler) SendLoginOTP(c *gin.Context) {
+	var req dto.SendPhoneOTPRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	err := h.authService.SendLoginOTP(c.Request.Context(), req.Phone)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Always return success (don't reveal if phone exists)
+	c.JSON(http.StatusOK, gin.H{"message": "if the phone number is registered, a login code has been sent"})
+}
+
+// PhoneLogin handles passwordless login with a phone OTP
+// @Summary Login with phone
+// @Description Authenticate with a verified phone number and OTP and return JWT tokens
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.PhoneLoginRequest true "Phone login request"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/phone/login [post]
+func (h *AuthHandler) PhoneLogin(c *gin.Context) {
+	var req dto.PhoneLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.authService.PhoneLogin(c.Request.Context(), req.Phone, req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+
+// ChangePassword changes the authenticated user's password
+// @Summary Change password
+// @Description Change the password after verifying the current one. Other sessions are revoked. Bearer clients get a new token pair; cookie sessions stay logged in as they are and get 204 No Content.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangePasswordRequest true "Change password request"
+// @Success 200 {object} dto.AuthResponse
+// @Success 204
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /me/password [post]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	// Cookie sessions must not receive tokens, which page scripts could read
+	if _, ok := c.Get("session"); ok {
+		if err := h.authService.ChangeSessionPassword(c.Request.Context(), c.GetString("user_id"), c.GetString("session_id"), req.CurrentPassword, req.NewPassword); err != nil {
+			c.Error(err)
+			return
+		}
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	response, err := h.authService.ChangePassword(c.Request.Context(), c.GetString("user_id"), c.GetString("session_id"), req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListSessions lists the authenticated user's sessions
+// @Summary List sessions
+// @Description List where the current user is logged in: active sessions with their device, approximate location and last-seen time
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.UserSessionResponse
+// @Failure 401 {object} apperror.Problem
+// @Router /me/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	sessions, err := h.authService.ListSessions(c.Request.Context(), c.GetString("user_id"), c.GetString("session_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession revokes one of the authenticated user's sessions
+// @Summary Revoke session
+// @Description Log a session out. Its refresh token stops working immediately; an access token already issued to it stays valid until it expires (at most 15 minutes).
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 400 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	var param dto.SessionIDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), c.GetString("user_id"), param.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,256 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/auth/dto"
+	"karigar-backend/internal/auth/service"
+	"karigar-backend/pkg/apperror"
+)
+
+type MFAHandler struct {
+	authService *service.AuthService
+	mfaService  *service.MFAService
+}
+
+// NewMFAHandler creates a new MFA handler
+func NewMFAHandler(authService *service.AuthService, mfaService *service.MFAService) *MFAHandler {
+	return &MFAHandler{
+		authService: authService,
+		mfaService:  mfaService,
+	}
+}
+
+// VerifyChallenge completes a two-step login
+// @Summary Verify MFA challenge
+// @Description Exchange the MFA token from login and a TOTP or recovery code for JWT tokens
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAChallengeRequest true "MFA challenge request"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 429 {object} apperror.Problem
+// @Router /auth/mfa/verify [post]
+func (h *MFAHandler) VerifyChallenge(c *gin.Context) {
+	var req dto.MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.authService.VerifyMFA(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BeginEnrollmentWithToken starts enrolment during a login that requires MFA
+// @Summary Start required MFA enrolment
+// @Description Start TOTP enrolment using the enrolment token returned by login
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAEnrollRequest true "MFA enrol request"
+// @Success 200 {object} dto.MFAEnrollmentResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/mfa/enroll [post]
+func (h *MFAHandler) BeginEnrollmentWithToken(c *gin.Context) {
+	var req dto.MFAEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.authService.BeginMFAEnrollment(c.Request.Context(), req.MFAToken)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ConfirmEnrollmentWithToken confirms enrolment during a login that requires MFA
+// @Summary Confirm required MFA enrolment
+// @Description Confirm TOTP enrolment and receive JWT tokens and recovery codes
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAEnrollConfirmRequest true "MFA enrol confirm request"
+// @Success 200 {object} dto.MFAEnrollmentCompleteResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/mfa/enroll/confirm [post]
+func (h *MFAHandler) ConfirmEnrollmentWithToken(c *gin.Context) {
+	var req dto.MFAEnrollConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.authService.ConfirmMFAEnrollment(c.Request.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// BeginEnrollment starts enrolment for the authenticated user
+// @Summary Start MFA enrolment
+// @Description Generate a TOTP secret and otpauth URI for the current user
+// @Tags mfa
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.MFAEnrollmentResponse
+// @Failure 409 {object} apperror.Problem
+// @Router /me/mfa/enroll [post]
+func (h *MFAHandler) BeginEnrollment(c *gin.Context) {
+	response, err := h.mfaService.BeginEnrollment(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ConfirmEnrollment enables MFA for the authenticated user
+// @Summary Confirm MFA enrolment
+// @Description Enable MFA with a code from the authenticator app and receive recovery codes
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MFACodeRequest true "MFA code request"
+// @Success 200 {object} dto.MFARecoveryCodesResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /me/mfa/enroll/confirm [post]
+func (h *MFAHandler) ConfirmEnrollment(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	codes, err := h.mfaService.ConfirmEnrollment(c.Request.Context(), c.GetString("user_id"), req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// Disable turns off MFA for the authenticated user
+// @Summary Disable MFA
+// @Description Disable MFA with a current TOTP code
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MFACodeRequest true "MFA code request"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /me/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	if err := h.mfaService.Disable(c.Request.Context(), c.GetString("user_id"), req.Code); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "two-factor authentication disabled"})
+}
+
+// RegenerateRecoveryCodes replaces the authenticated user's recovery codes
+// @Summary Regenerate recovery codes
+// @Description Invalidate existing recovery codes and generate new ones
+// @Tags mfa
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MFACodeRequest true "MFA code request"
+// @Success 200 {object} dto.MFARecoveryCodesResponse
+// @Failure 401 {object} apperror.Problem
+// @Router /me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	codes, err := h.mfaService.RegenerateRecoveryCodes(c.Request.Context(), c.GetString("user_id"), req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.MFARecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// ListPolicies lists MFA enforcement per role
+// @Summary List MFA role policies
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} domain.MFARolePolicy
+// @Router /admin/mfa-policies [get]
+func (h *MFAHandler) ListPolicies(c *gin.Context) {
+	policies, err := h.mfaService.ListPolicies(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policies)
+}
+
+// SetPolicy enforces or relaxes MFA for a role
+// @Summary Set MFA role policy
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param role path string true "Role"
+// @Param request body dto.MFAPolicyRequest true "MFA policy request"
+// @Success 200 {object} domain.MFARolePolicy
+// @Failure 400 {object} apperror.Problem
+// @Router /admin/mfa-policies/{role} [put]
+func (h *MFAHandler) SetPolicy(c *gin.Context) {
+	var param dto.MFAPolicyRoleParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	var req dto.MFAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	policy, err := h.mfaService.SetPolicy(c.Request.Context(), c.GetString("user_id"), param.Role, *req.MFARequired)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
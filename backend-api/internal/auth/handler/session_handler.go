@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/auth/dto"
+	"karigar-backend/internal/auth/service"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/pkg/apperror"
+)
+
+// SessionHandler serves the cookie session variants of the login endpoints.
+// They verify credentials exactly like their token counterparts, but on success
+// start a server-side session instead of returning a token pair.
+type SessionHandler struct {
+	authService *service.AuthService
+	sessions    *service.SessionService
+	config      config.SessionConfig
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(authService *service.AuthService, sessions *service.SessionService, cfg *config.Config) *SessionHandler {
+	return &SessionHandler{
+		authService: authService,
+		sessions:    sessions,
+		config:      cfg.Session,
+	}
+}
+
+// Login handles email and password login into a cookie session
+// @Summary Login with a session cookie
+// @Description Authenticate and start a cookie session. When a second factor is needed the MFA token is returned instead, to be exchanged at /auth/session/mfa/verify or /auth/session/mfa/enroll/confirm.
+// @Tags session
+// @Accept json
+// @Produce json
+// @Param request body dto.LoginRequest true "Login request"
+// @Success 200 {object} dto.SessionResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/session/login [post]
+func (h *SessionHandler) Login(c *gin.Context) {
+	var req dto.LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.authService.Login(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, response)
+}
+
+// PhoneLogin handles phone and OTP login into a cookie session
+// @Summary Login with phone into a session cookie
+// @Description Verify a login OTP sent with /auth/phone/send-otp and start a cookie session, or return an MFA token when a second factor is needed
+// @Tags session
+// @Accept json
+// @Produce json
+// @Param request body dto.PhoneLoginRequest true "Phone login request"
+// @Success 200 {object} dto.SessionResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 429 {object} apperror.Problem
+// @Router /auth/session/phone/login [post]
+func (h *SessionHandler) PhoneLogin(c *gin.Context) {
+	var req dto.PhoneLoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.authService.PhoneLogin(c.Request.Context(), req.Phone, req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, response)
+}
+
+// VerifyMFA completes a two-step login into a cookie session
+// @Summary Verify MFA challenge into a session cookie
+// @Description Exchange the MFA token from session login and a TOTP or recovery code for a cookie session
+// @Tags session
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAChallengeRequest true "MFA challenge request"
+// @Success 200 {object} dto.SessionResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 429 {object} apperror.Problem
+// @Router /auth/session/mfa/verify [post]
+func (h *SessionHandler) VerifyMFA(c *gin.Context) {
+	var req dto.MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.authService.VerifyMFA(c.Request.Context(), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.respond(c, response)
+}
+
+// ConfirmMFAEnrollment confirms enrolment during a session login that requires MFA
+// @Summary Confirm required MFA enrolment into a session cookie
+// @Description Confirm TOTP enrolment started with /auth/mfa/enroll, start a cookie session and receive recovery codes
+// @Tags session
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAEnrollConfirmRequest true "MFA enrol confirm request"
+// @Success 200 {object} dto.MFAEnrollmentSessionResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/session/mfa/enroll/confirm [post]
+func (h *SessionHandler) ConfirmMFAEnrollment(c *gin.Context) {
+	var req dto.MFAEnrollConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	result, err := h.authService.ConfirmMFAEnrollment(c.Request.Context(), req.MFAToken, req.Code)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	session, err := h.start(c, result.AuthResponse)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, &dto.MFAEnrollmentSessionResponse{
+		SessionResponse: session,
+		RecoveryCodes:   result.RecoveryCodes,
+	})
+}
+
+// Get returns the current session
+// @Summary Get current session
+// @Description Return the current cookie session, including the CSRF token to send with unsafe requests
+// @Tags session
+// @Produce json
+// @Security SessionCookie
+// @Success 200 {object} dto.SessionResponse
+// @Failure 401 {object} apperror.Problem
+// @Router /auth/session [get]
+func (h *SessionHandler) Get(c *gin.Context) {
+	session := c.MustGet("session").(*domain.Session)
+
+	c.JSON(http.StatusOK, &dto.SessionResponse{
+		UserID:    session.UserID.String(),
+		Role:      session.Role,
+		CSRFToken: session.CSRFToken,
+		ExpiresAt: session.ExpiresAt,
+	})
+}
+
+// Logout ends the current session
+// @Summary Logout of the session
+// @Description End the current cookie session and clear its cookies
+// @Tags session
+// @Security SessionCookie
+// @Param X-CSRF-Token header string true "CSRF token"
+// @Success 204
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /auth/session [delete]
+func (h *SessionHandler) Logout(c *gin.Context) {
+	if err := h.sessions.Destroy(c.Request.Context(), c.MustGet("session").(*domain.Session)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.setCookies(c, "", "", time.Time{})
+	c.Status(http.StatusNoContent)
+}
+
+// respond starts a session once login is complete, or passes on the MFA
+// challenge when a second factor is still needed
+func (h *SessionHandler) respond(c *gin.Context, response *dto.AuthResponse) {
+	if response.User == nil {
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	session, err := h.start(c, response)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
+
+// start creates a session for a completed login and sets its cookies. The
+// login's token pair is discarded; the session takes its place.
+func (h *SessionHandler) start(c *gin.Context, login *dto.AuthResponse) (*dto.SessionResponse, error) {
+	user := login.User
+	session, err := h.sessions.Create(c.Request.Context(), user, login.SessionID, c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		return nil, err
+	}
+
+	h.setCookies(c, session.ID, session.CSRFToken, session.ExpiresAt)
+	return &dto.SessionResponse{
+		UserID:    user.ID,
+		Role:      user.Role,
+		CSRFToken: session.CSRFToken,
+		ExpiresAt: session.ExpiresAt,
+		User:      user,
+	}, nil
+}
+
+// setCookies sets the session and CSRF cookies, or clears them when expires is zero
+func (h *SessionHandler) setCookies(c *gin.Context, sessionID, csrfToken string, expires time.Time) {
+	maxAge := -1
+	if !expires.IsZero() {
+		maxAge = int(time.Until(expires).Seconds())
+	}
+
+	http.SetCookie(c.Writer, h.cookie(h.config.CookieName, sessionID, maxAge, true))
+	http.SetCookie(c.Writer, h.cookie(h.config.CSRFCookieName, csrfToken, maxAge, false))
+}
+
+func (h *SessionHandler) cookie(name, value string, maxAge int, httpOnly bool) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   h.config.CookieDomain,
+		MaxAge:   maxAge,
+		Secure:   h.config.CookieSecure,
+		HttpOnly: httpOnly,
+		SameSite: sameSite(h.config.SameSite),
+	}
+}
+
+func sameSite(mode string) http.SameSite {
+	switch strings.ToLower(mode) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
@@ -6,61 +6,84 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"strings"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"karigar-backend/internal/audit"
 	"karigar-backend/internal/auth/dto"
 	"karigar-backend/internal/config"
 	"karigar-backend/internal/domain"
 	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/apperror"
 	"karigar-backend/pkg/auth"
+	"karigar-backend/pkg/geoip"
+	"karigar-backend/pkg/metrics"
+	"karigar-backend/pkg/tracing"
 )
 
+var tracer = otel.Tracer("karigar-backend/internal/auth/service")
+
 var (
-	ErrUserAlreadyExists    = errors.New("user with this email already exists")
-	ErrInvalidCredentials   = errors.New("invalid email or password")
-	ErrEmailNotVerified     = errors.New("email not verified")
-	ErrInvalidToken         = errors.New("invalid token")
-	ErrTokenExpired         = errors.New("token has expired")
-	ErrEmailAlreadyVerified = errors.New("email already verified")
+	ErrUserAlreadyExists    = apperror.New(apperror.Conflict, "user_already_exists", "user with this email already exists")
+	ErrInvalidCredentials   = apperror.New(apperror.Unauthorized, "invalid_credentials", "invalid email or password")
+	ErrEmailNotVerified     = apperror.New(apperror.Unauthorized, "email_not_verified", "email not verified")
+	ErrInvalidToken         = apperror.New(apperror.Unauthorized, "invalid_token", "invalid token")
+	ErrTokenExpired         = apperror.New(apperror.Unauthorized, "token_expired", "token has expired")
+	ErrEmailAlreadyVerified = apperror.New(apperror.Invalid, "email_already_verified", "email already verified")
+	ErrInvalidPhone         = apperror.New(apperror.Invalid, "invalid_phone", "invalid phone number")
+	ErrPhoneAlreadyInUse    = apperror.New(apperror.Conflict, "phone_in_use", "phone number is already verified by another account")
+	ErrIncorrectPassword    = apperror.New(apperror.Unauthorized, "incorrect_password", "current password is incorrect")
+	ErrUserSessionNotFound  = apperror.New(apperror.NotFound, "session_not_found", "session not found")
 )
 
 type AuthService struct {
-	userRepo  repository.UserRepository
-	jwtMgr    *auth.JWTManager
-	config    *config.Config
+	userRepo    repository.UserRepository
+	sessionRepo repository.UserSessionRepository
+	passwords   *PasswordService
+	otp         *OTPService
+	mfa         *MFAService
+	audit       *audit.Recorder
+	locator     geoip.Locator
+	notifier    LoginNotifier
+	jwtMgr      *auth.JWTManager
+	config      *config.Config
 }
 
 // NewAuthService creates a new auth service
-func NewAuthService(userRepo repository.UserRepository, cfg *config.Config) *AuthService {
+func NewAuthService(userRepo repository.UserRepository, sessionRepo repository.UserSessionRepository, passwords *PasswordService, otp *OTPService, mfa *MFAService, recorder *audit.Recorder, locator geoip.Locator, notifier LoginNotifier, cfg *config.Config) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
-		jwtMgr:   auth.NewJWTManager(&cfg.JWT),
-		config:   cfg,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		passwords:   passwords,
+		otp:         otp,
+		mfa:         mfa,
+		audit:       recorder,
+		locator:     locator,
+		notifier:    notifier,
+		jwtMgr:      auth.NewJWTManager(&cfg.JWT),
+		config:      cfg,
 	}
 }
 
 // Register registers a new user
-func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) (response *dto.AuthResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Register")
+	defer func() { tracing.End(span, err) }()
+
 	// Check if user already exists
 	existingUser, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err == nil && existingUser != nil {
 		return nil, ErrUserAlreadyExists
 	}
-	// If error is not "user not found", it's a real error (like database connection issue)
-	if err != nil {
-		// Check if error is "user not found" (expected when user doesn't exist)
-		// We need to check the error message since we can't import postgres package here
-		errMsg := err.Error()
-		if !strings.Contains(errMsg, "user not found") && !strings.Contains(errMsg, "failed to get user by email") {
-			return nil, fmt.Errorf("failed to check existing user: %w", err)
-		}
-		// If it's "user not found", that's fine - user doesn't exist, we can proceed
+	// "Not found" is expected; anything else is a real error (like a database connection issue)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, fmt.Errorf("failed to check existing user: %w", err)
 	}
 
-	// Hash password
-	hashedPassword, err := auth.HashPassword(req.Password)
+	// Validate against the password policy and hash
+	hashedPassword, err := s.passwords.Hash(ctx, req.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -84,43 +107,43 @@ func (s *AuthService) Register(ctx context.Context, req *dto.RegisterRequest) (*
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
+		// Lost a race with a concurrent registration for the same email
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrUserAlreadyExists
+		}
 		return nil, err
 	}
+	metrics.Registrations.WithLabelValues(string(user.Role)).Inc()
 
 	// TODO: Send verification email (mock for MVP)
 	// For MVP, we'll just log the token
 	// In production, send email with verification link
 
-	// Generate tokens
-	accessToken, refreshToken, err := s.jwtMgr.GenerateTokenPair(user)
-	if err != nil {
-		return nil, err
-	}
-
-	return &dto.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User: &dto.UserInfo{
-			ID:              user.ID.String(),
-			Email:           user.Email,
-			Role:            user.Role,
-			IsEmailVerified: user.IsEmailVerified,
-		},
-	}, nil
+	// Generate tokens (or an MFA enrolment challenge if the role requires MFA)
+	return s.completeLogin(ctx, user)
 }
 
 // Login authenticates a user
-func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
+func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (response *dto.AuthResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.Login")
+	defer func() { tracing.End(span, err) }()
+	defer func() { observeLogin("password", response, err) }()
+
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, ErrInvalidCredentials
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
 	}
 
-	// Verify password
-	if err := auth.ComparePassword(user.Password, req.Password); err != nil {
+	// Verify password (outdated hashes are upgraded transparently)
+	if err := s.passwords.Verify(ctx, user, req.Password); err != nil {
+		s.audit.Record(ctx, user.ID, domain.AuditLoginFailed, map[string]interface{}{"method": "password"})
 		return nil, ErrInvalidCredentials
 	}
+	s.audit.Record(ctx, user.ID, domain.AuditLogin, map[string]interface{}{"method": "password"})
 
 	// Check if email is verified (optional - can be removed for MVP)
 	// For MVP, we might allow login without verification
@@ -128,28 +151,18 @@ func (s *AuthService) Login(ctx context.Context, req *dto.LoginRequest) (*dto.Au
 	// 	return nil, ErrEmailNotVerified
 	// }
 
-	// Generate tokens
-	accessToken, refreshToken, err := s.jwtMgr.GenerateTokenPair(user)
-	if err != nil {
-		return nil, err
-	}
-
-	return &dto.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User: &dto.UserInfo{
-			ID:              user.ID.String(),
-			Email:           user.Email,
-			Role:            user.Role,
-			IsEmailVerified: user.IsEmailVerified,
-		},
-	}, nil
+	// Generate tokens, or an MFA challenge if a second factor is needed
+	return s.completeLogin(ctx, user)
 }
 
-// RefreshToken generates a new access token from a refresh token
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*dto.AuthResponse, error) {
+// RefreshToken exchanges a refresh token for a new token pair. Refresh tokens
+// rotate: only the newest one issued for a session is accepted.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (response *dto.AuthResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.RefreshToken")
+	defer func() { tracing.End(span, err) }()
+
 	// Validate refresh token
-	claims, err := s.jwtMgr.ValidateToken(refreshToken)
+	claims, err := s.jwtMgr.ValidateTokenType(refreshToken, auth.TokenTypeRefresh)
 	if err != nil {
 		return nil, ErrInvalidToken
 	}
@@ -160,26 +173,47 @@ func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (*d
 		return nil, ErrInvalidToken
 	}
 
-	// Generate new token pair
-	accessToken, newRefreshToken, err := s.jwtMgr.GenerateTokenPair(user)
+	// Refresh tokens issued before a password change (or reset) are revoked
+	if claims.Version != user.TokenVersion {
+		return nil, ErrInvalidToken
+	}
+
+	// Sessions that predate an MFA policy must log in again and enrol
+	if !user.MFAEnabled {
+		required, err := s.mfa.IsRequired(ctx, user.Role)
+		if err != nil {
+			return nil, err
+		}
+		if required {
+			return nil, ErrInvalidToken
+		}
+	}
+
+	// Tokens issued before login sessions were recorded cannot be rotated or
+	// revoked, so their holders must log in again
+	if claims.SessionID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, claims.SessionID)
 	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidToken
+		}
 		return nil, err
 	}
+	if session.UserID != user.ID || !session.IsActive(time.Now()) || session.RefreshTokenID.String() != claims.ID {
+		return nil, ErrInvalidToken
+	}
 
-	return &dto.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: newRefreshToken,
-		User: &dto.UserInfo{
-			ID:              user.ID.String(),
-			Email:           user.Email,
-			Role:            user.Role,
-			IsEmailVerified: user.IsEmailVerified,
-		},
-	}, nil
+	return s.rotateSession(ctx, user, session)
 }
 
 // VerifyEmail verifies a user's email
-func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
+func (s *AuthService) VerifyEmail(ctx context.Context, token string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.VerifyEmail")
+	defer func() { tracing.End(span, err) }()
+
 	// Get user by verification token
 	user, err := s.userRepo.GetByEmailVerifyToken(ctx, token)
 	if err != nil {
@@ -209,7 +243,10 @@ func (s *AuthService) VerifyEmail(ctx context.Context, token string) error {
 }
 
 // ForgotPassword initiates password reset
-func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
+func (s *AuthService) ForgotPassword(ctx context.Context, email string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ForgotPassword")
+	defer func() { tracing.End(span, err) }()
+
 	// Get user by email
 	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
@@ -240,7 +277,10 @@ func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
 }
 
 // ResetPassword resets a user's password
-func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
+func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ResetPassword")
+	defer func() { tracing.End(span, err) }()
+
 	// Get user by reset token
 	user, err := s.userRepo.GetByPasswordResetToken(ctx, token)
 	if err != nil {
@@ -252,24 +292,462 @@ func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword stri
 		return ErrTokenExpired
 	}
 
-	// Hash new password
-	hashedPassword, err := auth.HashPassword(newPassword)
-	if err != nil {
+	// Update password (checked against policy and history), clear reset token
+	// and revoke existing sessions
+	user.PasswordResetToken = nil
+	user.PasswordResetExpiry = nil
+	user.TokenVersion++
+
+	if err := s.passwords.SetPassword(ctx, user, newPassword); err != nil {
+		return err
+	}
+	if _, err := s.sessionRepo.RevokeAllExcept(ctx, user.ID.String(), ""); err != nil {
 		return err
 	}
+	s.audit.Record(ctx, user.ID, domain.AuditPasswordReset, nil)
 
-	// Update password and clear reset token
-	user.Password = hashedPassword
-	user.PasswordResetToken = nil
-	user.PasswordResetExpiry = nil
+	return nil
+}
 
-	if err := s.userRepo.Update(ctx, user); err != nil {
+// ChangePassword changes the password of a logged-in user after checking their current
+// password. All sessions but the caller's (sessionID) are revoked; the caller receives
+// a fresh token pair.
+func (s *AuthService) ChangePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) (response *dto.AuthResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ChangePassword")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := s.changePassword(ctx, userID, sessionID, currentPassword, newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bumping the token version revoked the caller's refresh token too
+	if sessionID != "" {
+		session, err := s.sessionRepo.GetByID(ctx, sessionID)
+		if err == nil && session.UserID == user.ID && session.IsActive(time.Now()) {
+			// A session revoked or refreshed meanwhile gets replaced below
+			response, err := s.rotateSession(ctx, user, session)
+			if !errors.Is(err, ErrInvalidToken) {
+				return response, err
+			}
+		} else if err != nil && !errors.Is(err, repository.ErrNotFound) {
+			return nil, err
+		}
+	}
+	session, err := s.createSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, session)
+}
+
+// ChangeSessionPassword is ChangePassword for callers authenticated by session
+// cookie. Their session is kept as it is and no tokens are issued, so bearer
+// credentials are never handed to page scripts.
+func (s *AuthService) ChangeSessionPassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ChangeSessionPassword")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = s.changePassword(ctx, userID, sessionID, currentPassword, newPassword)
+	return err
+}
+
+// changePassword checks the current password, sets the new one and revokes the
+// user's refresh tokens and all sessions but sessionID
+func (s *AuthService) changePassword(ctx context.Context, userID, sessionID, currentPassword, newPassword string) (*domain.User, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.passwords.Verify(ctx, user, currentPassword); err != nil {
+		if errors.Is(err, auth.ErrPasswordMismatch) {
+			return nil, ErrIncorrectPassword
+		}
+		return nil, err
+	}
+
+	user.TokenVersion++
+	if err := s.passwords.SetPassword(ctx, user, newPassword); err != nil {
+		return nil, err
+	}
+	if _, err := s.sessionRepo.RevokeAllExcept(ctx, userID, sessionID); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, user.ID, domain.AuditPasswordChanged, nil)
+
+	return user, nil
+}
+
+// ListSessions lists a user's active login sessions, most recently used first.
+// currentSessionID marks the session making the request.
+func (s *AuthService) ListSessions(ctx context.Context, userID, currentSessionID string) (sessions []*dto.UserSessionResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ListSessions")
+	defer func() { tracing.End(span, err) }()
+
+	active, err := s.sessionRepo.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions = make([]*dto.UserSessionResponse, 0, len(active))
+	for _, session := range active {
+		sessions = append(sessions, &dto.UserSessionResponse{
+			ID:         session.ID.String(),
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			Location:   session.Location,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			ExpiresAt:  session.ExpiresAt,
+			Current:    session.ID.String() == currentSessionID,
+		})
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession revokes one of a user's sessions: its refresh token stops working,
+// and its access token at the latest when it expires
+func (s *AuthService) RevokeSession(ctx context.Context, userID, sessionID string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.RevokeSession")
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.sessionRepo.Revoke(ctx, userID, sessionID); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserSessionNotFound
+		}
+		return err
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
 		return err
 	}
+	s.audit.Record(ctx, id, domain.AuditSessionRevoked, map[string]interface{}{"session_id": sessionID})
 
 	return nil
 }
 
+// SendPhoneVerificationOTP sends a code to verify a phone number for a logged-in user
+func (s *AuthService) SendPhoneVerificationOTP(ctx context.Context, userID, rawPhone string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.SendPhoneVerificationOTP")
+	defer func() { tracing.End(span, err) }()
+
+	number, err := s.otp.NormalizePhone(rawPhone)
+	if err != nil {
+		return ErrInvalidPhone
+	}
+
+	// Refuse numbers already verified by someone else before spending an SMS
+	if owner, err := s.userRepo.GetByVerifiedPhone(ctx, number); err == nil && owner.ID.String() != userID {
+		return ErrPhoneAlreadyInUse
+	}
+
+	return s.otp.Send(ctx, OTPPurposeVerifyPhone, number)
+}
+
+// VerifyPhone verifies a code and attaches the phone number to the user
+func (s *AuthService) VerifyPhone(ctx context.Context, userID, rawPhone, code string) (info *dto.UserInfo, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.VerifyPhone")
+	defer func() { tracing.End(span, err) }()
+
+	number, err := s.otp.NormalizePhone(rawPhone)
+	if err != nil {
+		return nil, ErrInvalidPhone
+	}
+
+	if err := s.otp.Verify(ctx, OTPPurposeVerifyPhone, number, code); err != nil {
+		return nil, err
+	}
+
+	if owner, err := s.userRepo.GetByVerifiedPhone(ctx, number); err == nil && owner.ID.String() != userID {
+		return nil, ErrPhoneAlreadyInUse
+	}
+
+	if err := s.userRepo.SetVerifiedPhone(ctx, userID, number); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, user.ID, domain.AuditPhoneVerified, nil)
+
+	return newUserInfo(user), nil
+}
+
+// SendLoginOTP sends a passwordless login code to a verified phone number
+func (s *AuthService) SendLoginOTP(ctx context.Context, rawPhone string) (err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.SendLoginOTP")
+	defer func() { tracing.End(span, err) }()
+
+	number, err := s.otp.NormalizePhone(rawPhone)
+	if err != nil {
+		return ErrInvalidPhone
+	}
+
+	if _, err := s.userRepo.GetByVerifiedPhone(ctx, number); err != nil {
+		// Don't reveal if the phone number is registered (security best practice)
+		return nil
+	}
+
+	return s.otp.Send(ctx, OTPPurposeLogin, number)
+}
+
+// PhoneLogin authenticates a user with a code sent to their verified phone number
+func (s *AuthService) PhoneLogin(ctx context.Context, rawPhone, code string) (response *dto.AuthResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.PhoneLogin")
+	defer func() { tracing.End(span, err) }()
+	defer func() { observeLogin("phone", response, err) }()
+
+	number, err := s.otp.NormalizePhone(rawPhone)
+	if err != nil {
+		return nil, ErrInvalidPhone
+	}
+
+	if err := s.otp.Verify(ctx, OTPPurposeLogin, number, code); err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetByVerifiedPhone(ctx, number)
+	if err != nil {
+		return nil, ErrOTPInvalid
+	}
+	s.audit.Record(ctx, user.ID, domain.AuditLogin, map[string]interface{}{"method": "phone"})
+
+	return s.completeLogin(ctx, user)
+}
+
+// VerifyMFA completes a two-step login by exchanging an MFA challenge token and a
+// TOTP or recovery code for a token pair
+func (s *AuthService) VerifyMFA(ctx context.Context, req *dto.MFAChallengeRequest) (response *dto.AuthResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.VerifyMFA")
+	defer func() { tracing.End(span, err) }()
+	defer func() { observeLogin("mfa", response, err) }()
+
+	user, err := s.userFromMFAToken(ctx, req.MFAToken, auth.TokenTypeMFAChallenge)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code == "" && req.RecoveryCode == "" {
+		return nil, ErrInvalidMFACode
+	}
+	if err := s.mfa.VerifySecondFactor(ctx, user, req.Code, req.RecoveryCode); err != nil {
+		s.audit.Record(ctx, user.ID, domain.AuditLoginFailed, map[string]interface{}{"method": "mfa"})
+		return nil, err
+	}
+	s.audit.Record(ctx, user.ID, domain.AuditLogin, map[string]interface{}{"method": "mfa", "recovery_code": req.RecoveryCode != ""})
+
+	return s.startSession(ctx, user)
+}
+
+// BeginMFAEnrollment starts TOTP enrolment for a user whose role requires MFA, using
+// the enrolment token returned by Login
+func (s *AuthService) BeginMFAEnrollment(ctx context.Context, mfaToken string) (enrollment *dto.MFAEnrollmentResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.BeginMFAEnrollment")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := s.userFromMFAToken(ctx, mfaToken, auth.TokenTypeMFAEnroll)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.mfa.BeginEnrollment(ctx, user.ID.String())
+}
+
+// ConfirmMFAEnrollment confirms enrolment started with BeginMFAEnrollment and completes the login
+func (s *AuthService) ConfirmMFAEnrollment(ctx context.Context, mfaToken, code string) (result *dto.MFAEnrollmentCompleteResponse, err error) {
+	ctx, span := tracer.Start(ctx, "AuthService.ConfirmMFAEnrollment")
+	defer func() { tracing.End(span, err) }()
+
+	user, err := s.userFromMFAToken(ctx, mfaToken, auth.TokenTypeMFAEnroll)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := s.mfa.ConfirmEnrollment(ctx, user.ID.String(), code)
+	if err != nil {
+		return nil, err
+	}
+
+	// Reload so the response reflects the enabled state
+	user, err = s.userRepo.GetByID(ctx, user.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.startSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAEnrollmentCompleteResponse{
+		AuthResponse:  response,
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// completeLogin starts a session once the first factor is verified, or issues an
+// MFA challenge if the user has MFA enabled or their role requires it
+func (s *AuthService) completeLogin(ctx context.Context, user *domain.User) (*dto.AuthResponse, error) {
+	if user.MFAEnabled {
+		token, err := s.jwtMgr.GenerateMFAToken(user, auth.TokenTypeMFAChallenge)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.AuthResponse{MFARequired: true, MFAToken: token}, nil
+	}
+
+	required, err := s.mfa.IsRequired(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		token, err := s.jwtMgr.GenerateMFAToken(user, auth.TokenTypeMFAEnroll)
+		if err != nil {
+			return nil, err
+		}
+		return &dto.AuthResponse{MFAEnrollmentRequired: true, MFAToken: token}, nil
+	}
+
+	return s.startSession(ctx, user)
+}
+
+// userFromMFAToken validates an MFA token of the given type and loads its user
+func (s *AuthService) userFromMFAToken(ctx context.Context, token string, tokenType auth.TokenType) (*domain.User, error) {
+	claims, err := s.jwtMgr.ValidateTokenType(token, tokenType)
+	if err != nil {
+		if errors.Is(err, auth.ErrExpiredToken) {
+			return nil, ErrTokenExpired
+		}
+		return nil, ErrInvalidToken
+	}
+
+	user, err := s.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	return user, nil
+}
+
+// startSession records a new login session, notifies the user if it is from a
+// device they have not used before, and issues the session's token pair
+func (s *AuthService) startSession(ctx context.Context, user *domain.User) (*dto.AuthResponse, error) {
+	// Count before creating, so the new session does not count as a known device
+	_, agent := audit.ClientFromContext(ctx)
+	total, matching, err := s.sessionRepo.CountByUserAgent(ctx, user.ID.String(), agent)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.createSession(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// A user's first session is not a new device, just a new account
+	if total > 0 && matching == 0 {
+		s.audit.Record(ctx, user.ID, domain.AuditNewDeviceLogin, map[string]interface{}{"session_id": session.ID, "location": session.Location})
+		if err := s.notifier.NotifyNewDevice(ctx, user, session); err != nil {
+			slog.ErrorContext(ctx, "failed to send new device notification", "user_id", user.ID, "error", err)
+		}
+	}
+
+	return s.issueTokens(user, session)
+}
+
+// createSession records a login session for the requesting client
+func (s *AuthService) createSession(ctx context.Context, user *domain.User) (*domain.UserSession, error) {
+	ip, agent := audit.ClientFromContext(ctx)
+	session := &domain.UserSession{
+		ID:             uuid.New(),
+		UserID:         user.ID,
+		RefreshTokenID: uuid.New(),
+		UserAgent:      agent,
+		IPAddress:      ip,
+		Location:       s.locator.Locate(ctx, ip),
+		ExpiresAt:      time.Now().Add(s.jwtMgr.RefreshExpiry()),
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// rotateSession issues a session's next token pair, which makes the previous
+// refresh token unusable, and records where the session was last seen. When a
+// concurrent refresh or revocation got there first, it returns ErrInvalidToken,
+// so each refresh token is redeemed at most once.
+func (s *AuthService) rotateSession(ctx context.Context, user *domain.User, session *domain.UserSession) (*dto.AuthResponse, error) {
+	ip, _ := audit.ClientFromContext(ctx)
+	if ip != "" && ip != session.IPAddress {
+		session.IPAddress = ip
+		session.Location = s.locator.Locate(ctx, ip)
+	}
+	now := time.Now()
+	previousRefreshTokenID := session.RefreshTokenID.String()
+	session.RefreshTokenID = uuid.New()
+	session.LastSeenAt = now
+	session.ExpiresAt = now.Add(s.jwtMgr.RefreshExpiry())
+
+	if err := s.sessionRepo.Rotate(ctx, session, previousRefreshTokenID); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return s.issueTokens(user, session)
+}
+
+// issueTokens generates a token pair for a session and builds the auth response
+func (s *AuthService) issueTokens(user *domain.User, session *domain.UserSession) (*dto.AuthResponse, error) {
+	accessToken, refreshToken, err := s.jwtMgr.GenerateTokenPair(user, session.ID.String(), session.RefreshTokenID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		User:         newUserInfo(user),
+		SessionID:    session.ID.String(),
+	}, nil
+}
+
+// observeLogin counts a login attempt by method and outcome
+func observeLogin(method string, response *dto.AuthResponse, err error) {
+	outcome := metrics.LoginSuccess
+	switch {
+	case err != nil:
+		outcome = metrics.LoginFailure
+	case response.MFARequired:
+		outcome = metrics.LoginMFARequired
+	case response.MFAEnrollmentRequired:
+		outcome = metrics.LoginMFAEnrollmentRequired
+	}
+	metrics.Logins.WithLabelValues(method, outcome).Inc()
+}
+
+// newUserInfo builds the public user representation
+func newUserInfo(user *domain.User) *dto.UserInfo {
+	return &dto.UserInfo{
+		ID:              user.ID.String(),
+		Email:           user.Email,
+		Role:            user.Role,
+		IsEmailVerified: user.IsEmailVerified,
+		Phone:           user.Phone,
+		IsPhoneVerified: user.IsPhoneVerified,
+		MFAEnabled:      user.MFAEnabled,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+	}
+}
+
 // generateSecureToken generates a secure random token
 func generateSecureToken() (string, error) {
 	bytes := make([]byte, 32)
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"karigar-backend/internal/domain"
+)
+
+// LoginNotifier is told when a user logs in from a device they have not logged
+// in from before. Implementations deliver the notification (email, SMS, push);
+// they are called during login, so slow deliveries should be queued.
+type LoginNotifier interface {
+	NotifyNewDevice(ctx context.Context, user *domain.User, session *domain.UserSession) error
+}
+
+// LogLoginNotifier writes new device logins to the application log
+type LogLoginNotifier struct{}
+
+// NotifyNewDevice logs the login instead of notifying the user
+func (LogLoginNotifier) NotifyNewDevice(ctx context.Context, user *domain.User, session *domain.UserSession) error {
+	slog.InfoContext(ctx, "login from a new device",
+		"user_id", user.ID,
+		"session_id", session.ID,
+		"user_agent", session.UserAgent,
+		"location", session.Location,
+	)
+	return nil
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/audit"
+	"karigar-backend/internal/auth/dto"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/auth"
+	"karigar-backend/pkg/redis"
+)
+
+var (
+	ErrMFANotEnabled           = apperror.New(apperror.Invalid, "mfa_not_enabled", "two-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled       = apperror.New(apperror.Conflict, "mfa_already_enabled", "two-factor authentication is already enabled")
+	ErrMFAEnrollmentNotStarted = apperror.New(apperror.Invalid, "mfa_enrollment_not_started", "two-factor enrolment has not been started")
+	ErrInvalidMFACode          = apperror.New(apperror.Unauthorized, "invalid_mfa_code", "invalid authentication code")
+	ErrMFATooManyAttempts      = apperror.New(apperror.TooManyRequests, "mfa_too_many_attempts", "too many authentication attempts, try again later")
+	ErrMFARequiredByPolicy     = apperror.New(apperror.Forbidden, "mfa_required_by_policy", "two-factor authentication is required for your role")
+)
+
+const (
+	recoveryCodeCount    = 10
+	recoveryCodeLength   = 10
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789" // No look-alike characters
+)
+
+// MFAService manages TOTP enrolment, second-factor verification and per-role MFA policies
+type MFAService struct {
+	userRepo repository.UserRepository
+	mfaRepo  repository.MFARepository
+	store    redis.Store
+	box      *auth.SecretBox
+	audit    *audit.Recorder
+	cfg      config.MFAConfig
+}
+
+// NewMFAService creates a new MFA service
+func NewMFAService(userRepo repository.UserRepository, mfaRepo repository.MFARepository, store redis.Store, recorder *audit.Recorder, cfg *config.Config) (*MFAService, error) {
+	box, err := auth.NewSecretBox(cfg.MFA.EncryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize MFA encryption: %w", err)
+	}
+
+	return &MFAService{
+		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		store:    store,
+		box:      box,
+		audit:    recorder,
+		cfg:      cfg.MFA,
+	}, nil
+}
+
+// BeginEnrollment generates a new TOTP secret for the user. MFA stays disabled
+// until the user confirms a code from their authenticator app.
+func (s *MFAService) BeginEnrollment(ctx context.Context, userID string) (*dto.MFAEnrollmentResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	sealed, err := s.box.Seal(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFASecret = &sealed
+	user.MFAEnrolledAt = nil
+	user.MFALastUsedStep = nil
+	if err := s.userRepo.UpdateMFA(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPProvisioningURI(s.cfg.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmEnrollment enables MFA once the user proves their authenticator app works
+// and returns a fresh set of recovery codes
+func (s *MFAService) ConfirmEnrollment(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.MFASecret == nil {
+		return nil, ErrMFAEnrollmentNotStarted
+	}
+
+	if err := s.checkAttempts(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	secret, err := s.box.Open(*user.MFASecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt MFA secret: %w", err)
+	}
+	step, ok := auth.ValidateTOTPCode(secret, code, time.Now())
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	now := time.Now()
+	user.MFAEnabled = true
+	user.MFAEnrolledAt = &now
+	user.MFALastUsedStep = &step
+	if err := s.userRepo.UpdateMFA(ctx, user); err != nil {
+		return nil, err
+	}
+	s.resetAttempts(ctx, userID)
+	s.audit.Record(ctx, user.ID, domain.AuditMFAEnabled, nil)
+
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// Disable turns off MFA after verifying a current code. Users whose role requires MFA cannot disable it.
+func (s *MFAService) Disable(ctx context.Context, userID, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	required, err := s.IsRequired(ctx, user.Role)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequiredByPolicy
+	}
+
+	if err := s.VerifySecondFactor(ctx, user, code, ""); err != nil {
+		return err
+	}
+
+	user.MFAEnabled = false
+	user.MFASecret = nil
+	user.MFAEnrolledAt = nil
+	user.MFALastUsedStep = nil
+	if err := s.userRepo.UpdateMFA(ctx, user); err != nil {
+		return err
+	}
+	s.audit.Record(ctx, user.ID, domain.AuditMFADisabled, nil)
+
+	return s.mfaRepo.DeleteRecoveryCodes(ctx, userID)
+}
+
+// RegenerateRecoveryCodes replaces all recovery codes after verifying a current code
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, ErrMFANotEnabled
+	}
+
+	if err := s.VerifySecondFactor(ctx, user, code, ""); err != nil {
+		return nil, err
+	}
+	s.audit.Record(ctx, user.ID, domain.AuditMFARecoveryCodesReset, nil)
+
+	return s.replaceRecoveryCodes(ctx, userID)
+}
+
+// VerifySecondFactor checks either a TOTP code or a one-time recovery code.
+// Failed attempts are rate limited per user.
+func (s *MFAService) VerifySecondFactor(ctx context.Context, user *domain.User, code, recoveryCode string) error {
+	if !user.MFAEnabled || user.MFASecret == nil {
+		return ErrMFANotEnabled
+	}
+
+	userID := user.ID.String()
+	if err := s.checkAttempts(ctx, userID); err != nil {
+		return err
+	}
+
+	if recoveryCode != "" {
+		ok, err := s.mfaRepo.ConsumeRecoveryCode(ctx, userID, hashRecoveryCode(recoveryCode))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidMFACode
+		}
+		s.resetAttempts(ctx, userID)
+		return nil
+	}
+
+	secret, err := s.box.Open(*user.MFASecret)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt MFA secret: %w", err)
+	}
+	step, ok := auth.ValidateTOTPCode(secret, code, time.Now())
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	// Reject a code that was already used, even if it is still within its time window
+	fresh, err := s.userRepo.RecordMFAStep(ctx, userID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidMFACode
+	}
+
+	s.resetAttempts(ctx, userID)
+	return nil
+}
+
+// IsRequired reports whether MFA is enforced for a role
+func (s *MFAService) IsRequired(ctx context.Context, role domain.UserRole) (bool, error) {
+	policy, err := s.mfaRepo.GetRolePolicy(ctx, role)
+	if err != nil {
+		return false, err
+	}
+	return policy.MFARequired, nil
+}
+
+// ListPolicies lists the MFA policy for every role
+func (s *MFAService) ListPolicies(ctx context.Context) ([]*domain.MFARolePolicy, error) {
+	stored, err := s.mfaRepo.ListRolePolicies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byRole := make(map[domain.UserRole]*domain.MFARolePolicy, len(stored))
+	for _, policy := range stored {
+		byRole[policy.Role] = policy
+	}
+
+	roles := []domain.UserRole{domain.RoleCustomer, domain.RoleServiceProvider, domain.RoleAdmin}
+	policies := make([]*domain.MFARolePolicy, 0, len(roles))
+	for _, role := range roles {
+		if policy, ok := byRole[role]; ok {
+			policies = append(policies, policy)
+		} else {
+			policies = append(policies, &domain.MFARolePolicy{Role: role})
+		}
+	}
+
+	return policies, nil
+}
+
+// SetPolicy enforces or relaxes MFA for a role
+func (s *MFAService) SetPolicy(ctx context.Context, adminID string, role domain.UserRole, required bool) (*domain.MFARolePolicy, error) {
+	policy := &domain.MFARolePolicy{
+		Role:        role,
+		MFARequired: required,
+	}
+	if id, err := uuid.Parse(adminID); err == nil {
+		policy.UpdatedBy = &id
+	}
+
+	if err := s.mfaRepo.UpsertRolePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	return s.mfaRepo.GetRolePolicy(ctx, role)
+}
+
+// replaceRecoveryCodes generates new recovery codes and stores their hashes
+func (s *MFAService) replaceRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashRecoveryCode(code)
+	}
+
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// checkAttempts counts a second-factor attempt and rejects it once the limit is reached
+func (s *MFAService) checkAttempts(ctx context.Context, userID string) error {
+	key := redis.Key("mfa_attempts", userID)
+	attempts, err := s.store.Increment(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to record MFA attempt: %w", err)
+	}
+	if attempts == 1 {
+		if err := s.store.Expire(ctx, key, s.cfg.LockoutWindow); err != nil {
+			return fmt.Errorf("failed to set MFA attempts expiry: %w", err)
+		}
+	}
+	if attempts > int64(s.cfg.MaxAttempts) {
+		return ErrMFATooManyAttempts
+	}
+	return nil
+}
+
+func (s *MFAService) resetAttempts(ctx context.Context, userID string) {
+	_ = s.store.Delete(ctx, redis.Key("mfa_attempts", userID))
+}
+
+// generateRecoveryCode generates a human-friendly code such as "k7mpx-3rhwq"
+func generateRecoveryCode() (string, error) {
+	bytes := make([]byte, recoveryCodeLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	var code strings.Builder
+	for i, b := range bytes {
+		if i == recoveryCodeLength/2 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(recoveryCodeAlphabet[int(b)%len(recoveryCodeAlphabet)])
+	}
+	return code.String(), nil
+}
+
+// hashRecoveryCode normalizes and hashes a recovery code. Codes are high-entropy,
+// so a fast hash is sufficient.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"karigar-backend/internal/config"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/phone"
+	"karigar-backend/pkg/redis"
+	"karigar-backend/pkg/sms"
+)
+
+var (
+	ErrOTPInvalid         = apperror.New(apperror.Unauthorized, "otp_invalid", "invalid or expired code")
+	ErrOTPTooManyAttempts = apperror.New(apperror.Unauthorized, "otp_too_many_attempts", "too many attempts, request a new code")
+	ErrOTPCooldown        = apperror.New(apperror.TooManyRequests, "otp_cooldown", "please wait before requesting another code")
+)
+
+// OTPPurpose scopes a one-time password so a code sent for one flow cannot be used in another
+type OTPPurpose string
+
+const (
+	OTPPurposeVerifyPhone OTPPurpose = "verify_phone"
+	OTPPurposeLogin       OTPPurpose = "login"
+)
+
+// OTPService issues and verifies short-lived SMS codes. Codes are stored in
+// the key-value store (Redis) as HMAC hashes, never in plain text.
+type OTPService struct {
+	store  redis.Store
+	sender sms.Sender
+	cfg    config.OTPConfig
+	secret []byte
+}
+
+// NewOTPService creates a new OTP service
+func NewOTPService(store redis.Store, sender sms.Sender, cfg *config.Config) *OTPService {
+	return &OTPService{
+		store:  store,
+		sender: sender,
+		cfg:    cfg.OTP,
+		secret: []byte(cfg.JWT.SecretKey),
+	}
+}
+
+// NormalizePhone converts a phone number to E.164 using the configured default country code
+func (s *OTPService) NormalizePhone(raw string) (string, error) {
+	return phone.NormalizeE164(raw, s.cfg.DefaultCountryCode)
+}
+
+// Send generates a new code for the given purpose and phone number and sends it by SMS
+func (s *OTPService) Send(ctx context.Context, purpose OTPPurpose, number string) error {
+	// Enforce a cooldown between sends to limit SMS abuse
+	ok, err := s.store.SetNX(ctx, s.cooldownKey(purpose, number), 1, s.cfg.ResendCooldown)
+	if err != nil {
+		return fmt.Errorf("failed to check OTP cooldown: %w", err)
+	}
+	if !ok {
+		return ErrOTPCooldown
+	}
+
+	code, err := generateNumericCode(s.cfg.Length)
+	if err != nil {
+		return err
+	}
+
+	if err := s.store.Set(ctx, s.codeKey(purpose, number), s.hash(purpose, number, code), s.cfg.TTL); err != nil {
+		return fmt.Errorf("failed to store OTP: %w", err)
+	}
+	if err := s.store.Delete(ctx, s.attemptsKey(purpose, number)); err != nil {
+		return fmt.Errorf("failed to reset OTP attempts: %w", err)
+	}
+
+	message := fmt.Sprintf("Your Karigar code is %s. It expires in %d minutes.", code, int(s.cfg.TTL.Minutes()))
+	if err := s.sender.Send(ctx, number, message); err != nil {
+		return fmt.Errorf("failed to send OTP: %w", err)
+	}
+
+	return nil
+}
+
+// Verify checks a code and consumes it on success
+func (s *OTPService) Verify(ctx context.Context, purpose OTPPurpose, number, code string) error {
+	attemptsKey := s.attemptsKey(purpose, number)
+	attempts, err := s.store.Increment(ctx, attemptsKey)
+	if err != nil {
+		return fmt.Errorf("failed to record OTP attempt: %w", err)
+	}
+	if attempts == 1 {
+		if err := s.store.Expire(ctx, attemptsKey, s.cfg.TTL); err != nil {
+			return fmt.Errorf("failed to set OTP attempts expiry: %w", err)
+		}
+	}
+	if attempts > int64(s.cfg.MaxAttempts) {
+		// Burn the code so it cannot be brute-forced further
+		_ = s.store.Delete(ctx, s.codeKey(purpose, number))
+		return ErrOTPTooManyAttempts
+	}
+
+	stored, err := s.store.Get(ctx, s.codeKey(purpose, number))
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return ErrOTPInvalid
+		}
+		return fmt.Errorf("failed to get OTP: %w", err)
+	}
+
+	if !hmac.Equal([]byte(stored), []byte(s.hash(purpose, number, code))) {
+		return ErrOTPInvalid
+	}
+
+	_ = s.store.Delete(ctx, s.codeKey(purpose, number))
+	_ = s.store.Delete(ctx, attemptsKey)
+	return nil
+}
+
+func (s *OTPService) hash(purpose OTPPurpose, number, code string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(string(purpose) + ":" + number + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *OTPService) codeKey(purpose OTPPurpose, number string) string {
+	return redis.Key("otp", string(purpose), number)
+}
+
+func (s *OTPService) attemptsKey(purpose OTPPurpose, number string) string {
+	return redis.Key("otp_attempts", string(purpose), number)
+}
+
+func (s *OTPService) cooldownKey(purpose OTPPurpose, number string) string {
+	return redis.Key("otp_cooldown", string(purpose), number)
+}
+
+// generateNumericCode generates a random numeric code of the given length
+func generateNumericCode(length int) (string, error) {
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		code[i] = byte('0' + n.Int64())
+	}
+	return string(code), nil
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/auth"
+	"karigar-backend/pkg/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// PasswordService applies the password policy, prevents reuse of recent passwords
+// and transparently upgrades password hashes
+type PasswordService struct {
+	userRepo    repository.UserRepository
+	historyRepo repository.PasswordHistoryRepository
+	policy      *auth.PasswordPolicy
+	hasher      *auth.PasswordHasher
+	historySize int
+}
+
+// NewPasswordService creates a new password service from configuration
+func NewPasswordService(userRepo repository.UserRepository, historyRepo repository.PasswordHistoryRepository, cfg *config.Config) (*PasswordService, error) {
+	pc := cfg.Password
+
+	hasher, err := auth.NewPasswordHasher(pc.Algorithm, pc.BcryptCost, auth.Argon2Params{
+		Memory:      uint32(pc.Argon2Memory),
+		Iterations:  uint32(pc.Argon2Iterations),
+		Parallelism: uint8(pc.Argon2Parallelism),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	maxLength := pc.MaxLength
+	if pc.Algorithm != auth.AlgorithmArgon2id && (maxLength <= 0 || maxLength > 72) {
+		// bcrypt ignores everything after 72 bytes, so longer passwords would be silently truncated
+		maxLength = 72
+	}
+
+	policy := &auth.PasswordPolicy{
+		MinLength:     pc.MinLength,
+		MaxLength:     maxLength,
+		RequireUpper:  pc.RequireUpper,
+		RequireLower:  pc.RequireLower,
+		RequireNumber: pc.RequireNumber,
+	}
+
+	if pc.BreachedHashFile != "" {
+		checker, err := auth.OpenBreachedPasswordFile(pc.BreachedHashFile, pc.BreachedMinCount)
+		if err != nil {
+			return nil, err
+		}
+		slog.Info("opened breached password file", "bytes", checker.Size())
+		policy.Breached = checker
+	}
+
+	return &PasswordService{
+		userRepo:    userRepo,
+		historyRepo: historyRepo,
+		policy:      policy,
+		hasher:      hasher,
+		historySize: pc.HistorySize,
+	}, nil
+}
+
+// Hash validates a new password against the policy and hashes it. Use SetPassword
+// for existing users so reuse is checked as well.
+func (s *PasswordService) Hash(ctx context.Context, password string) (string, error) {
+	if err := s.policy.Validate(password); err != nil {
+		return "", err
+	}
+
+	return s.hash(ctx, password)
+}
+
+// Verify checks a password for a user. If the stored hash uses an outdated
+// algorithm or cost it is replaced with a fresh hash.
+func (s *PasswordService) Verify(ctx context.Context, user *domain.User, password string) error {
+	if err := s.compare(ctx, user.Password, password); err != nil {
+		return err
+	}
+
+	if s.hasher.NeedsRehash(user.Password) {
+		newHash, err := s.hash(ctx, password)
+		if err != nil {
+			slog.WarnContext(ctx, "failed to rehash password", "user_id", user.ID, "error", err)
+			return nil
+		}
+		user.Password = newHash
+		if err := s.userRepo.Update(ctx, user); err != nil {
+			// The old hash still works, so a failed upgrade must not fail the login
+			slog.WarnContext(ctx, "failed to store rehashed password", "user_id", user.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// SetPassword validates and sets a new password for an existing user, rejecting the
+// current password and the last N passwords. The user is saved with userRepo.Update,
+// so callers can change other fields (e.g. clear a reset token) in the same write.
+func (s *PasswordService) SetPassword(ctx context.Context, user *domain.User, newPassword string) error {
+	if err := s.policy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	if err := s.checkReuse(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	newHash, err := s.hash(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+
+	oldHash := user.Password
+	user.Password = newHash
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if s.historySize > 0 && oldHash != "" {
+		userID := user.ID.String()
+		if err := s.historyRepo.Add(ctx, userID, oldHash); err != nil {
+			return err
+		}
+		if err := s.historyRepo.Prune(ctx, userID, s.historySize); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkReuse rejects the current password and any of the last N passwords
+func (s *PasswordService) checkReuse(ctx context.Context, user *domain.User, password string) error {
+	if user.Password != "" && s.compare(ctx, user.Password, password) == nil {
+		return auth.ErrPasswordReused
+	}
+
+	if s.historySize <= 0 {
+		return nil
+	}
+
+	previous, err := s.historyRepo.GetRecent(ctx, user.ID.String(), s.historySize)
+	if err != nil {
+		return err
+	}
+
+	for _, hash := range previous {
+		err := s.compare(ctx, hash, password)
+		if err == nil {
+			return auth.ErrPasswordReused
+		}
+		if !errors.Is(err, auth.ErrPasswordMismatch) {
+			return fmt.Errorf("failed to compare password history: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// hash hashes a password in its own span, since hashing is deliberately slow
+func (s *PasswordService) hash(ctx context.Context, password string) (hash string, err error) {
+	_, span := tracer.Start(ctx, "PasswordService.hash")
+	defer func() { tracing.End(span, err) }()
+
+	return s.hasher.Hash(password)
+}
+
+// compare checks a password against a hash in its own span. A mismatch is an
+// expected outcome and does not mark the span as failed.
+func (s *PasswordService) compare(ctx context.Context, hashedPassword, password string) (err error) {
+	_, span := tracer.Start(ctx, "PasswordService.compare")
+	defer func() {
+		if errors.Is(err, auth.ErrPasswordMismatch) {
+			span.SetAttributes(attribute.Bool("password.match", false))
+			tracing.End(span, nil)
+			return
+		}
+		tracing.End(span, err)
+	}()
+
+	return s.hasher.Compare(hashedPassword, password)
+}
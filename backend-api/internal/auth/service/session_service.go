@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/auth/dto"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/redis"
+	"karigar-backend/pkg/tracing"
+)
+
+var ErrSessionNotFound = apperror.New(apperror.Unauthorized, "invalid_session", "session is invalid or has expired")
+
+// sessionTouchInterval limits how often a session's idle expiry is pushed back,
+// so that busy sessions are not rewritten on every request
+const sessionTouchInterval = time.Minute
+
+// SessionService manages server-side sessions for clients that use cookies
+// rather than bearer tokens. Sessions live in the store with a sliding idle
+// expiry, capped by an absolute maximum age. Each is tied to the login session
+// (UserSession) it was started from, and ends when that is revoked.
+type SessionService struct {
+	cache       *redis.CacheService
+	sessionRepo repository.UserSessionRepository
+	config      config.SessionConfig
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(store redis.Store, sessionRepo repository.UserSessionRepository, cfg *config.Config) *SessionService {
+	return &SessionService{
+		cache:       redis.NewCacheService(store),
+		sessionRepo: sessionRepo,
+		config:      cfg.Session,
+	}
+}
+
+// Create starts a session for a user who has completed login into the login
+// session userSessionID
+func (s *SessionService) Create(ctx context.Context, user *dto.UserInfo, userSessionID, ipAddress, userAgent string) (session *domain.Session, err error) {
+	ctx, span := tracer.Start(ctx, "SessionService.Create")
+	defer func() { tracing.End(span, err) }()
+
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	userSession, err := s.sessionRepo.GetByID(ctx, userSessionID)
+	if err != nil {
+		return nil, err
+	}
+	id, err := generateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+	csrfToken, err := generateSecureToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session = &domain.Session{
+		ID:            id,
+		UserSessionID: userSession.ID,
+		UserID:        userID,
+		Email:         user.Email,
+		Role:          user.Role,
+		CSRFToken:     csrfToken,
+		IPAddress:     ipAddress,
+		UserAgent:     userAgent,
+		CreatedAt:     now,
+		LastSeenAt:    now,
+		ExpiresAt:     now.Add(s.config.MaxAge),
+	}
+
+	// The login session lives as long as the cookie session rather than its
+	// (unused) refresh token
+	userSession.ExpiresAt = session.ExpiresAt
+	if err := s.sessionRepo.Update(ctx, userSession); err != nil {
+		return nil, err
+	}
+	if err := s.save(ctx, session, now); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Touch loads a session and pushes back its idle expiry. At most once per
+// sessionTouchInterval, it also checks that the login session is still active
+// and records the activity there.
+func (s *SessionService) Touch(ctx context.Context, sessionID string) (session *domain.Session, err error) {
+	ctx, span := tracer.Start(ctx, "SessionService.Touch")
+	defer func() { tracing.End(span, err) }()
+
+	session, err = s.get(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if now.Sub(session.LastSeenAt) < sessionTouchInterval {
+		return session, nil
+	}
+
+	userSession, err := s.sessionRepo.GetByID(ctx, session.UserSessionID.String())
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+	if err != nil || !userSession.IsActive(now) {
+		// Revoked, e.g. from another device or by a password change
+		if err := s.cache.InvalidateSession(ctx, hashSessionID(sessionID)); err != nil {
+			return nil, err
+		}
+		return nil, ErrSessionNotFound
+	}
+
+	userSession.LastSeenAt = now
+	if err := s.sessionRepo.Update(ctx, userSession); err != nil {
+		return nil, err
+	}
+	session.LastSeenAt = now
+	if err := s.save(ctx, session, now); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Destroy ends a session and revokes its login session
+func (s *SessionService) Destroy(ctx context.Context, session *domain.Session) (err error) {
+	ctx, span := tracer.Start(ctx, "SessionService.Destroy")
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.cache.InvalidateSession(ctx, hashSessionID(session.ID)); err != nil {
+		return err
+	}
+
+	// The login session may already have been revoked, e.g. from another device
+	err = s.sessionRepo.Revoke(ctx, session.UserID.String(), session.UserSessionID.String())
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// get loads a session, treating sessions past their absolute expiry as missing
+func (s *SessionService) get(ctx context.Context, sessionID string) (*domain.Session, error) {
+	if sessionID == "" {
+		return nil, ErrSessionNotFound
+	}
+
+	var session domain.Session
+	if err := s.cache.GetCachedSession(ctx, hashSessionID(sessionID), &session); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	if !time.Now().Before(session.ExpiresAt) {
+		return nil, ErrSessionNotFound
+	}
+
+	session.ID = sessionID
+	return &session, nil
+}
+
+// save stores a session until its idle timeout, or its absolute expiry if sooner
+func (s *SessionService) save(ctx context.Context, session *domain.Session, now time.Time) error {
+	ttl := s.config.IdleTimeout
+	if remaining := session.ExpiresAt.Sub(now); remaining < ttl {
+		ttl = remaining
+	}
+	if ttl <= 0 {
+		return ErrSessionNotFound
+	}
+
+	return s.cache.CacheSession(ctx, hashSessionID(session.ID), session, ttl)
+}
+
+// hashSessionID derives the store key of a session, so that session IDs cannot
+// be recovered from the store
+func hashSessionID(sessionID string) string {
+	sum := sha256.Sum256([]byte(sessionID))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+// IDParam binds the id path parameter of booking routes
+type IDParam struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// CreateBookingRequest represents the request body for booking a service
+type CreateBookingRequest struct {
+	ServiceID     string    `json:"service_id" binding:"required,uuid"`
+	RequestedDate time.Time `json:"requested_date" binding:"required"` // When the service should start
+	Address       string    `json:"address" binding:"required,max=1000"`
+	Notes         string    `json:"notes" binding:"omitempty,max=2000"`
+}
+
+// CancelBookingRequest represents the request body for cancelling a booking
+type CancelBookingRequest struct {
+	Reason string `json:"reason" binding:"required,max=50"` // e.g. schedule_conflict, provider_unavailable, other
+	Note   string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// ProposeRescheduleRequest represents the request body for proposing a new date for a booking
+type ProposeRescheduleRequest struct {
+	ProposedDate time.Time `json:"proposed_date" binding:"required"` // When the service should start instead
+	Note         string    `json:"note" binding:"omitempty,max=1000"`
+}
+
+// RespondRescheduleRequest represents the request body for answering the other
+// party's reschedule proposal. Countering requires a date of one's own.
+type RespondRescheduleRequest struct {
+	Action       string     `json:"action" binding:"required,oneof=accept reject counter"`
+	ProposedDate *time.Time `json:"proposed_date" binding:"required_if=Action counter"`
+	Note         string     `json:"note" binding:"omitempty,max=1000"`
+}
+
+// ReportNoShowRequest represents the request body for reporting that the provider did not turn up
+type ReportNoShowRequest struct {
+	Note string `json:"note" binding:"omitempty,max=1000"`
+}
+
+// AddNoteRequest represents the request body for adding a note to a booking's timeline
+type AddNoteRequest struct {
+	Note string `json:"note" binding:"required,max=2000"`
+}
+
+// ChangePriceRequest represents the request body for changing the quoted price of a booking
+type ChangePriceRequest struct {
+	QuotedPrice float64 `json:"quoted_price" binding:"required,gt=0"`
+	Note        string  `json:"note" binding:"omitempty,max=1000"` // e.g. why the price changed
+}
+
+// AnalyticsQuery selects the bookings requested between two dates, inclusive,
+// in the booking timezone. Defaults to the last 30 days.
+type AnalyticsQuery struct {
+	From       string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To         string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	ProviderID string `form:"provider_id" binding:"omitempty,uuid"` // Admin only; all providers if empty
+}
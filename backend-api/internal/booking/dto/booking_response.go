@@ -0,0 +1,96 @@
+package dto
+
+import "time"
+
+// BookingResponse describes a booking with the terms the service was booked on
+type BookingResponse struct {
+	ID               string     `json:"id"`
+	Status           string     `json:"status"`
+	CustomerID       string     `json:"customer_id,omitempty"` // Empty once the customer account is deleted
+	ProviderID       string     `json:"provider_id,omitempty"` // Empty once the provider account is deleted
+	ServiceID        string     `json:"service_id,omitempty"`  // Empty once the service is deleted
+	ServiceName      string     `json:"service_name"`
+	QuotedPrice      float64    `json:"quoted_price"`
+	Currency         string     `json:"currency"`
+	DurationMinutes  int        `json:"duration_minutes"`
+	RequestedDate    time.Time  `json:"requested_date"`
+	ScheduledDate    *time.Time `json:"scheduled_date,omitempty"`
+	EstimatedEndDate *time.Time `json:"estimated_end_date,omitempty"`
+	Address          string     `json:"address"`
+	Notes            string     `json:"notes,omitempty"`
+	OverdueAt        *time.Time `json:"overdue_at,omitempty"` // Set once confirmed and not completed long after its end
+
+	CancellationPolicy []CancellationWindowResponse `json:"cancellation_policy"` // The provider's policy when booked
+	Cancellation       *CancellationResponse        `json:"cancellation,omitempty"`
+	CreatedAt          time.Time                    `json:"created_at"`
+	UpdatedAt          time.Time                    `json:"updated_at"`
+}
+
+// CancellationWindowResponse describes the fee for cancelling less than
+// HoursBefore hours before the start
+type CancellationWindowResponse struct {
+	HoursBefore int     `json:"hours_before"`
+	FeePercent  float64 `json:"fee_percent"`
+}
+
+// CancellationResponse describes who cancelled a booking, when and why
+type CancellationResponse struct {
+	At     time.Time `json:"at"`
+	By     string    `json:"by"`   // customer, provider or system
+	From   string    `json:"from"` // Status the booking was cancelled from
+	Reason string    `json:"reason"`
+	Note   string    `json:"note,omitempty"`
+	Fee    float64   `json:"fee"` // Charged to the customer, in the booking's currency
+}
+
+// RescheduleProposalResponse describes a proposed new date for a booking and its answer
+type RescheduleProposalResponse struct {
+	ID           string     `json:"id"`
+	ProposedBy   string     `json:"proposed_by"` // customer or provider
+	ProposedDate time.Time  `json:"proposed_date"`
+	Note         string     `json:"note,omitempty"`
+	Status       string     `json:"status"`                  // pending, accepted, rejected, countered or expired
+	CounterToID  string     `json:"counter_to_id,omitempty"` // The proposal this one answers
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// RescheduleNegotiationResponse describes a booking with its reschedule
+// proposals, oldest first
+type RescheduleNegotiationResponse struct {
+	Booking   *BookingResponse              `json:"booking"`
+	Proposals []*RescheduleProposalResponse `json:"proposals"`
+}
+
+// BookingEventResponse describes an entry in a booking's timeline
+type BookingEventResponse struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Actor      string                 `json:"actor"`                 // customer, provider or system
+	FromStatus string                 `json:"from_status,omitempty"` // status_changed only
+	ToStatus   string                 `json:"to_status,omitempty"`   // status_changed only
+	Note       string                 `json:"note,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// BookingAnalyticsResponse summarizes what happened to the bookings requested in a period
+type BookingAnalyticsResponse struct {
+	From                   string   `json:"from"`
+	To                     string   `json:"to"`
+	Requested              int      `json:"requested"`
+	Confirmed              int      `json:"confirmed"`
+	Declined               int      `json:"declined"` // Cancelled by the provider before confirming
+	Completed              int      `json:"completed"`
+	CancelledByCustomer    int      `json:"cancelled_by_customer"`
+	CancelledByProvider    int      `json:"cancelled_by_provider"` // After confirming
+	CancelledBySystem      int      `json:"cancelled_by_system"`   // Expired before the provider answered
+	NoShows                int      `json:"no_shows"`              // Included in cancelled_by_customer
+	FlaggedOverdue         int      `json:"flagged_overdue"`
+	AcceptanceRate         *float64 `json:"acceptance_rate"`          // Percentage of answered requests confirmed
+	AverageResponseMinutes *float64 `json:"average_response_minutes"` // Until the provider confirmed or declined
+	RescheduleProposals    int      `json:"reschedule_proposals"`     // Including counter-proposals
+	ReschedulesAccepted    int      `json:"reschedules_accepted"`
+	PriceChanges           int      `json:"price_changes"`
+	CancellationFees       float64  `json:"cancellation_fees"`
+}
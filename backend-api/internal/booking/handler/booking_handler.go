@@ -0,0 +1,692 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/booking/dto"
+	"karigar-backend/internal/booking/service"
+	"karigar-backend/internal/domain"
+	"karigar-backend/pkg/apperror"
+)
+
+type BookingHandler struct {
+	bookingService *service.BookingService
+}
+
+// NewBookingHandler creates a new booking handler
+func NewBookingHandler(bookingService *service.BookingService) *BookingHandler {
+	return &BookingHandler{
+		bookingService: bookingService,
+	}
+}
+
+// Create books a service for the authenticated customer
+// @Summary Book a service
+// @Description Book an active service of an active, verified provider. The service's name, price and duration are recorded on the booking.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateBookingRequest true "Booking"
+// @Success 201 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/bookings [post]
+func (h *BookingHandler) Create(c *gin.Context) {
+	var req dto.CreateBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.Create(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListCustomerBookings lists the authenticated customer's bookings
+// @Summary List own bookings
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.BookingResponse
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /me/bookings [get]
+func (h *BookingHandler) ListCustomerBookings(c *gin.Context) {
+	response, err := h.bookingService.ListCustomerBookings(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCustomerBooking returns one of the authenticated customer's bookings
+// @Summary Get own booking
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/bookings/{id} [get]
+func (h *BookingHandler) GetCustomerBooking(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.GetCustomerBooking(c.Request.Context(), c.GetString("user_id"), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListProviderBookings lists the bookings the authenticated provider has received
+// @Summary List received bookings
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.BookingResponse
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/bookings [get]
+func (h *BookingHandler) ListProviderBookings(c *gin.Context) {
+	response, err := h.bookingService.ListProviderBookings(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetProviderBooking returns one of the bookings the authenticated provider has received
+// @Summary Get received booking
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/bookings/{id} [get]
+func (h *BookingHandler) GetProviderBooking(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.GetProviderBooking(c.Request.Context(), c.GetString("user_id"), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Confirm accepts a requested booking
+// @Summary Confirm booking
+// @Description Accept a requested booking at its quoted price. It is scheduled for the requested date, or the date agreed by rescheduling. Fails with 409 schedule_conflict if the provider has another confirmed booking at that time.
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/confirm [post]
+func (h *BookingHandler) Confirm(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.Confirm(c.Request.Context(), c.GetString("user_id"), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Complete marks a confirmed booking as done
+// @Summary Complete booking
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/complete [post]
+func (h *BookingHandler) Complete(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.Complete(c.Request.Context(), c.GetString("user_id"), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CancelAsCustomer cancels one of the authenticated customer's bookings
+// @Summary Cancel own booking
+// @Description Cancel a requested or confirmed booking with a reason code (schedule_conflict, found_alternative, no_longer_needed, price_too_high, emergency or other). A fee is charged for cancelling a confirmed booking if its cancellation policy calls for one, unless the reason is price_too_high. A provider who did not turn up is reported through the no-show endpoint instead.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.CancelBookingRequest true "Cancellation reason"
+// @Success 200 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/bookings/{id}/cancel [post]
+func (h *BookingHandler) CancelAsCustomer(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+	var req dto.CancelBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.CancelAsCustomer(c.Request.Context(), c.GetString("user_id"), param.ID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReportNoShow cancels a confirmed booking whose provider did not turn up
+// @Summary Report provider no-show
+// @Description Report that the provider did not turn up for a confirmed booking, which cancels it free of charge and lowers the provider's reliability score. It can be reported once the booking is past its start by the no-show grace period. Both parties are notified.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.ReportNoShowRequest true "Optional note"
+// @Success 200 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/bookings/{id}/no-show [post]
+func (h *BookingHandler) ReportNoShow(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+	var req dto.ReportNoShowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.ReportNoShow(c.Request.Context(), c.GetString("user_id"), param.ID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CancelAsProvider cancels or declines a booking the authenticated provider has received
+// @Summary Cancel received booking
+// @Description Decline a requested booking, or cancel a confirmed one, with a reason code (provider_unavailable, outside_service_area, customer_unreachable, emergency or other). Cancelling a confirmed booking lowers the provider's reliability score.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.CancelBookingRequest true "Cancellation reason"
+// @Success 200 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/cancel [post]
+func (h *BookingHandler) CancelAsProvider(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+	var req dto.CancelBookingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.CancelAsProvider(c.Request.Context(), c.GetString("user_id"), param.ID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ProposeRescheduleAsCustomer proposes a new date for one of the authenticated customer's bookings
+// @Summary Propose new booking date
+// @Description Propose a new start date for a requested or confirmed booking. The provider must be available then and free of other confirmed bookings. The provider accepts, rejects or counters it.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.ProposeRescheduleRequest true "Proposed date"
+// @Success 201 {object} dto.RescheduleNegotiationResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/bookings/{id}/reschedules [post]
+func (h *BookingHandler) ProposeRescheduleAsCustomer(c *gin.Context) {
+	h.proposeReschedule(c, domain.PartyCustomer)
+}
+
+// RespondToRescheduleAsCustomer answers the provider's pending reschedule proposal
+// @Summary Answer reschedule proposal
+// @Description Accept, reject or counter (with a proposed_date of your own) the provider's pending reschedule proposal. Accepting moves the booking to the proposed date.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.RespondRescheduleRequest true "Answer"
+// @Success 200 {object} dto.RescheduleNegotiationResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/bookings/{id}/reschedules/respond [post]
+func (h *BookingHandler) RespondToRescheduleAsCustomer(c *gin.Context) {
+	h.respondToReschedule(c, domain.PartyCustomer)
+}
+
+// ListCustomerReschedules returns the reschedule negotiation history of one of the authenticated customer's bookings
+// @Summary List reschedule proposals
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} dto.RescheduleNegotiationResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/bookings/{id}/reschedules [get]
+func (h *BookingHandler) ListCustomerReschedules(c *gin.Context) {
+	h.listReschedules(c, domain.PartyCustomer)
+}
+
+// ProposeRescheduleAsProvider proposes a new date for one of the authenticated provider's bookings
+// @Summary Propose new booking date
+// @Description Propose a new start date for a requested or confirmed booking. The provider must be available then and free of other confirmed bookings. The customer accepts, rejects or counters it.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.ProposeRescheduleRequest true "Proposed date"
+// @Success 201 {object} dto.RescheduleNegotiationResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/reschedules [post]
+func (h *BookingHandler) ProposeRescheduleAsProvider(c *gin.Context) {
+	h.proposeReschedule(c, domain.PartyProvider)
+}
+
+// RespondToRescheduleAsProvider answers the customer's pending reschedule proposal
+// @Summary Answer reschedule proposal
+// @Description Accept, reject or counter (with a proposed_date of your own) the customer's pending reschedule proposal. Accepting moves the booking to the proposed date.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.RespondRescheduleRequest true "Answer"
+// @Success 200 {object} dto.RescheduleNegotiationResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/reschedules/respond [post]
+func (h *BookingHandler) RespondToRescheduleAsProvider(c *gin.Context) {
+	h.respondToReschedule(c, domain.PartyProvider)
+}
+
+// ListProviderReschedules returns the reschedule negotiation history of one of the authenticated provider's bookings
+// @Summary List reschedule proposals
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {object} dto.RescheduleNegotiationResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/reschedules [get]
+func (h *BookingHandler) ListProviderReschedules(c *gin.Context) {
+	h.listReschedules(c, domain.PartyProvider)
+}
+
+func (h *BookingHandler) proposeReschedule(c *gin.Context, party domain.BookingParty) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+	var req dto.ProposeRescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.ProposeReschedule(c.Request.Context(), party, c.GetString("user_id"), param.ID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+func (h *BookingHandler) respondToReschedule(c *gin.Context, party domain.BookingParty) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+	var req dto.RespondRescheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.RespondToReschedule(c.Request.Context(), party, c.GetString("user_id"), param.ID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BookingHandler) listReschedules(c *gin.Context, party domain.BookingParty) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.ListReschedules(c.Request.Context(), party, c.GetString("user_id"), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetCustomerTimeline returns the history of one of the authenticated customer's bookings
+// @Summary Get booking timeline
+// @Description Every status change, reschedule proposal and answer, note and price change on the booking, oldest first
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {array} dto.BookingEventResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/bookings/{id}/timeline [get]
+func (h *BookingHandler) GetCustomerTimeline(c *gin.Context) {
+	h.getTimeline(c, domain.PartyCustomer)
+}
+
+// AddCustomerNote adds a note to the timeline of one of the authenticated customer's bookings
+// @Summary Add booking note
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.AddNoteRequest true "Note"
+// @Success 201 {object} dto.BookingEventResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/bookings/{id}/notes [post]
+func (h *BookingHandler) AddCustomerNote(c *gin.Context) {
+	h.addNote(c, domain.PartyCustomer)
+}
+
+// GetProviderTimeline returns the history of one of the authenticated provider's bookings
+// @Summary Get booking timeline
+// @Description Every status change, reschedule proposal and answer, note and price change on the booking, oldest first
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Success 200 {array} dto.BookingEventResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/timeline [get]
+func (h *BookingHandler) GetProviderTimeline(c *gin.Context) {
+	h.getTimeline(c, domain.PartyProvider)
+}
+
+// AddProviderNote adds a note to the timeline of one of the authenticated provider's bookings
+// @Summary Add booking note
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.AddNoteRequest true "Note"
+// @Success 201 {object} dto.BookingEventResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/notes [post]
+func (h *BookingHandler) AddProviderNote(c *gin.Context) {
+	h.addNote(c, domain.PartyProvider)
+}
+
+// ChangePrice changes the quoted price of a booking the authenticated provider has received
+// @Summary Change booking price
+// @Description Change the quoted price of a booking that has not been confirmed yet. The change is recorded on the booking's timeline.
+// @Tags bookings
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Booking ID"
+// @Param request body dto.ChangePriceRequest true "New price"
+// @Success 200 {object} dto.BookingResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/provider/bookings/{id}/price [put]
+func (h *BookingHandler) ChangePrice(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+	var req dto.ChangePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.ChangePrice(c.Request.Context(), c.GetString("user_id"), param.ID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetProviderAnalytics summarizes the bookings the authenticated provider received in a period
+// @Summary Get own booking analytics
+// @Description Counts of what happened to the bookings requested between two dates (inclusive, in the booking timezone; the last 30 days by default), the acceptance rate and the average time to answer a request
+// @Tags bookings
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} dto.BookingAnalyticsResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/analytics [get]
+func (h *BookingHandler) GetProviderAnalytics(c *gin.Context) {
+	var query dto.AnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.ProviderAnalytics(c.Request.Context(), c.GetString("user_id"), &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAnalytics summarizes the bookings made in a period, across all providers or for one
+// @Summary Get booking analytics
+// @Description Admin only. Counts of what happened to the bookings requested between two dates (inclusive, in the booking timezone; the last 30 days by default).
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param provider_id query string false "Only this provider's bookings"
+// @Success 200 {object} dto.BookingAnalyticsResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /admin/analytics/bookings [get]
+func (h *BookingHandler) GetAnalytics(c *gin.Context) {
+	var query dto.AnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.Analytics(c.Request.Context(), &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BookingHandler) getTimeline(c *gin.Context, party domain.BookingParty) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.Timeline(c.Request.Context(), party, c.GetString("user_id"), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *BookingHandler) addNote(c *gin.Context, party domain.BookingParty) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+	var req dto.AddNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.bookingService.AddNote(c.Request.Context(), party, c.GetString("user_id"), param.ID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+
+	"karigar-backend/internal/domain"
+)
+
+// BookingNotifier is told about changes to a booking that neither party made
+// themselves in the app: requests the system expired, bookings flagged as
+// overdue and reported no-shows. Implementations deliver the notification
+// (email, SMS, push) to both the customer and the provider.
+type BookingNotifier interface {
+	NotifyParties(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error
+}
+
+// LogBookingNotifier writes booking notifications to the application log
+type LogBookingNotifier struct{}
+
+// NotifyParties logs the change instead of notifying the parties
+func (LogBookingNotifier) NotifyParties(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error {
+	slog.InfoContext(ctx, "booking change for both parties",
+		"booking_id", request.ID,
+		"customer_id", request.CustomerID,
+		"provider_id", request.ProviderID,
+		"event", event.Type,
+		"status", request.Status,
+	)
+	return nil
+}
@@ -0,0 +1,1046 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/booking/dto"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/jobs"
+	"karigar-backend/pkg/metrics"
+)
+
+var (
+	ErrBookingNotFound         = apperror.New(apperror.NotFound, "booking_not_found", "booking not found")
+	ErrServiceNotFound         = apperror.New(apperror.NotFound, "service_not_found", "service not found")
+	ErrServiceUnavailable      = apperror.New(apperror.Conflict, "service_unavailable", "this service is not currently offered")
+	ErrProviderUnavailable     = apperror.New(apperror.Conflict, "provider_unavailable", "this provider is not accepting bookings")
+	ErrProviderProfileNotFound = apperror.New(apperror.NotFound, "provider_profile_not_found", "create your provider profile first")
+	ErrBookingTooSoon          = apperror.New(apperror.Invalid, "booking_too_soon", "requested date is too soon")
+	ErrInvalidTransition       = apperror.New(apperror.Conflict, "invalid_booking_transition", "the booking cannot be changed in its current status")
+	ErrInvalidCancelReason     = apperror.New(apperror.Invalid, "invalid_cancellation_reason", "this cancellation reason is not recognized")
+	ErrOutsideAvailability     = apperror.New(apperror.Conflict, "outside_availability", "the provider is not available at this time")
+	ErrScheduleConflict        = apperror.New(apperror.Conflict, "schedule_conflict", "the provider has another booking at this time")
+	ErrReschedulePending       = apperror.New(apperror.Conflict, "reschedule_pending", "this booking already has a pending reschedule proposal")
+	ErrNoPendingReschedule     = apperror.New(apperror.NotFound, "no_pending_reschedule", "this booking has no pending reschedule proposal")
+	ErrOwnReschedule           = apperror.New(apperror.Conflict, "own_reschedule_proposal", "the other party must answer your reschedule proposal")
+	ErrRescheduleChanged       = apperror.New(apperror.Conflict, "reschedule_changed", "the booking or proposal has changed; reload it and try again")
+	ErrInvalidPeriod           = apperror.New(apperror.Invalid, "invalid_period", "the period must end on or after its start and span at most a year")
+	ErrNoShowTooEarly          = apperror.New(apperror.Conflict, "no_show_too_early", "a no-show can only be reported once the booking is past its start")
+)
+
+const (
+	defaultAnalyticsDays = 30
+	maxAnalyticsDays     = 366
+	sweepBatchSize       = 100
+)
+
+// sweepJob runs SweepStaleBookings
+var sweepJob = jobs.NewKind[struct{}]("bookings.sweep")
+
+// BookingService lets customers book providers' services, and providers
+// confirm and complete the bookings they receive
+type BookingService struct {
+	requestRepo      repository.ServiceRequestRepository
+	customerRepo     repository.CustomerRepository
+	providerRepo     repository.ServiceProviderRepository
+	serviceRepo      repository.ServiceRepository
+	policyRepo       repository.CancellationPolicyRepository
+	availabilityRepo repository.AvailabilityRepository
+	rescheduleRepo   repository.RescheduleProposalRepository
+	eventRepo        repository.ServiceRequestEventRepository
+	notifier         BookingNotifier
+	config           config.BookingConfig
+	location         *time.Location // Providers' availability is in this zone
+}
+
+// NewBookingService creates a new booking service
+func NewBookingService(requestRepo repository.ServiceRequestRepository, customerRepo repository.CustomerRepository, providerRepo repository.ServiceProviderRepository, serviceRepo repository.ServiceRepository, policyRepo repository.CancellationPolicyRepository, availabilityRepo repository.AvailabilityRepository, rescheduleRepo repository.RescheduleProposalRepository, eventRepo repository.ServiceRequestEventRepository, notifier BookingNotifier, cfg *config.Config) (*BookingService, error) {
+	location, err := time.LoadLocation(cfg.Booking.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid booking timezone %q: %w", cfg.Booking.Timezone, err)
+	}
+
+	return &BookingService{
+		requestRepo:      requestRepo,
+		customerRepo:     customerRepo,
+		providerRepo:     providerRepo,
+		serviceRepo:      serviceRepo,
+		policyRepo:       policyRepo,
+		availabilityRepo: availabilityRepo,
+		rescheduleRepo:   rescheduleRepo,
+		eventRepo:        eventRepo,
+		notifier:         notifier,
+		config:           cfg.Booking,
+		location:         location,
+	}, nil
+}
+
+// Create books a service for the authenticated customer. The service's name,
+// price and duration, and the provider's cancellation policy, are copied onto
+// the booking, so it keeps the terms the customer agreed to if the provider
+// later changes them.
+func (s *BookingService) Create(ctx context.Context, userID string, req *dto.CreateBookingRequest) (*dto.BookingResponse, error) {
+	if req.RequestedDate.Before(time.Now().Add(s.config.MinLeadTime)) {
+		return nil, ErrBookingTooSoon
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, req.ServiceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+	if !service.IsActive {
+		return nil, ErrServiceUnavailable
+	}
+
+	provider, err := s.providerRepo.GetByID(ctx, service.ProviderID.String())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+	if !provider.IsActive || !provider.IsVerified {
+		return nil, ErrProviderUnavailable
+	}
+
+	policy, err := s.policyRepo.GetByProviderID(ctx, provider.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	customer, err := s.getOrCreateCustomer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &domain.ServiceRequest{
+		ID:                 uuid.New(),
+		CustomerID:         customer.ID,
+		ProviderID:         provider.ID,
+		ServiceID:          service.ID,
+		Status:             domain.StatusRequested,
+		RequestedDate:      req.RequestedDate,
+		Address:            req.Address,
+		Notes:              req.Notes,
+		ServiceName:        service.Name,
+		QuotedPrice:        service.Price,
+		Currency:           s.config.Currency,
+		DurationMinutes:    service.Duration,
+		CancellationPolicy: policy,
+	}
+	request.UpdateEstimatedEnd()
+
+	event := domain.NewServiceRequestEvent(request.ID, domain.EventStatusChanged, domain.PartyCustomer, customer.UserID)
+	event.ToStatus = domain.StatusRequested
+	event.Metadata["requested_date"] = request.RequestedDate
+	event.Metadata["quoted_price"] = request.QuotedPrice
+	if err := s.requestRepo.Create(ctx, request, event); err != nil {
+		return nil, err
+	}
+	metrics.BookingTransitions.WithLabelValues(metrics.BookingCreated, string(domain.StatusRequested)).Inc()
+
+	return newBookingResponse(request), nil
+}
+
+// ListCustomerBookings lists the authenticated customer's bookings, latest first
+func (s *BookingService) ListCustomerBookings(ctx context.Context, userID string) ([]*dto.BookingResponse, error) {
+	customer, err := s.customerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return []*dto.BookingResponse{}, nil
+		}
+		return nil, err
+	}
+
+	requests, err := s.requestRepo.GetByCustomerID(ctx, customer.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return newBookingResponses(requests), nil
+}
+
+// GetCustomerBooking returns one of the authenticated customer's bookings
+func (s *BookingService) GetCustomerBooking(ctx context.Context, userID, bookingID string) (*dto.BookingResponse, error) {
+	request, err := s.getCustomerRequest(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBookingResponse(request), nil
+}
+
+// ListProviderBookings lists the bookings the authenticated provider has received, latest first
+func (s *BookingService) ListProviderBookings(ctx context.Context, userID string) ([]*dto.BookingResponse, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := s.requestRepo.GetByProviderID(ctx, provider.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return newBookingResponses(requests), nil
+}
+
+// GetProviderBooking returns one of the bookings the authenticated provider has received
+func (s *BookingService) GetProviderBooking(ctx context.Context, userID, bookingID string) (*dto.BookingResponse, error) {
+	request, err := s.getProviderRequest(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBookingResponse(request), nil
+}
+
+// Confirm accepts a requested booking at the quoted price, scheduling it for
+// the requested date unless the parties have already agreed on a new one. It
+// fails if the provider has another confirmed booking at that time.
+func (s *BookingService) Confirm(ctx context.Context, userID, bookingID string) (*dto.BookingResponse, error) {
+	request, err := s.getProviderRequest(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	from := request.Status
+	if !canTransition(from, domain.StatusConfirmed) {
+		return nil, ErrInvalidTransition
+	}
+	event, err := newEvent(request, domain.EventStatusChanged, domain.PartyProvider, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.ScheduledDate == nil {
+		scheduled := request.RequestedDate
+		request.ScheduledDate = &scheduled
+	}
+	request.UpdateEstimatedEnd()
+	event.FromStatus = from
+	event.ToStatus = domain.StatusConfirmed
+	event.Metadata["scheduled_date"] = request.StartDate()
+	if err := s.requestRepo.Confirm(ctx, request, event); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOverlapping):
+			return nil, ErrScheduleConflict
+		case errors.Is(err, repository.ErrConflict):
+			return nil, ErrInvalidTransition
+		}
+		return nil, err
+	}
+	metrics.BookingTransitions.WithLabelValues(string(from), string(domain.StatusConfirmed)).Inc()
+
+	return newBookingResponse(request), nil
+}
+
+// Complete marks a confirmed booking as done
+func (s *BookingService) Complete(ctx context.Context, userID, bookingID string) (*dto.BookingResponse, error) {
+	request, err := s.getProviderRequest(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	event, err := newEvent(request, domain.EventStatusChanged, domain.PartyProvider, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.transition(ctx, request, domain.StatusCompleted, event); err != nil {
+		return nil, err
+	}
+
+	return newBookingResponse(request), nil
+}
+
+// CancelAsCustomer cancels one of the authenticated customer's bookings. A fee
+// is charged if the booking's cancellation policy calls for one.
+func (s *BookingService) CancelAsCustomer(ctx context.Context, userID, bookingID string, req *dto.CancelBookingRequest) (*dto.BookingResponse, error) {
+	request, err := s.getCustomerRequest(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cancel(ctx, request, domain.CancelledByCustomer, userID, req); err != nil {
+		return nil, err
+	}
+
+	return newBookingResponse(request), nil
+}
+
+// CancelAsProvider cancels or declines one of the bookings the authenticated
+// provider has received. Cancelling a confirmed booking lowers the provider's
+// reliability score.
+func (s *BookingService) CancelAsProvider(ctx context.Context, userID, bookingID string, req *dto.CancelBookingRequest) (*dto.BookingResponse, error) {
+	request, err := s.getProviderRequest(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cancel(ctx, request, domain.CancelledByProvider, userID, req); err != nil {
+		return nil, err
+	}
+
+	return newBookingResponse(request), nil
+}
+
+// ReportNoShow cancels a confirmed booking of the authenticated customer whose
+// provider did not turn up. It can be reported from the no-show grace period
+// after the start, costs the customer nothing, and counts against the
+// provider's reliability score like a cancellation.
+func (s *BookingService) ReportNoShow(ctx context.Context, userID, bookingID string, req *dto.ReportNoShowRequest) (*dto.BookingResponse, error) {
+	request, err := s.getCustomerRequest(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != domain.StatusConfirmed {
+		return nil, ErrInvalidTransition
+	}
+	if time.Now().Before(request.StartDate().Add(s.config.NoShowGrace)) {
+		return nil, ErrNoShowTooEarly
+	}
+	customerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cancellation := &domain.Cancellation{
+		At:       time.Now(),
+		By:       domain.CancelledByCustomer,
+		ByUserID: customerID,
+		From:     request.Status,
+		Reason:   domain.ReasonNoShow,
+		Note:     req.Note,
+	}
+	event, err := s.applyCancellation(ctx, request, cancellation)
+	if err != nil {
+		return nil, err
+	}
+	s.notify(ctx, request, event)
+
+	return newBookingResponse(request), nil
+}
+
+// SweepStaleBookings expires requests the provider did not confirm within the
+// request SLA or before they were due to start, and flags confirmed bookings
+// still not completed long after their end. Both parties are notified of each.
+// It is safe to run on several instances at once: a booking is only changed if
+// it is still as it was listed, so one another instance got to first is skipped.
+func (s *BookingService) SweepStaleBookings(ctx context.Context) (expired, flagged int, err error) {
+	now := time.Now()
+
+	requests, err := s.requestRepo.ListExpiring(ctx, now.Add(-s.config.RequestSLA), now, sweepBatchSize)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, request := range requests {
+		if err := s.expire(ctx, request); err != nil {
+			if !errors.Is(err, ErrInvalidTransition) {
+				slog.ErrorContext(ctx, "failed to expire booking", "booking_id", request.ID, "error", err)
+			}
+			continue
+		}
+		expired++
+	}
+
+	requests, err = s.requestRepo.ListOverdue(ctx, now.Add(-s.config.OverdueAfter), sweepBatchSize)
+	if err != nil {
+		return expired, 0, err
+	}
+	for _, request := range requests {
+		if err := s.flagOverdue(ctx, request); err != nil {
+			if !errors.Is(err, ErrInvalidTransition) {
+				slog.ErrorContext(ctx, "failed to flag overdue booking", "booking_id", request.ID, "error", err)
+			}
+			continue
+		}
+		flagged++
+	}
+
+	return expired, flagged, nil
+}
+
+// RegisterJobs schedules SweepStaleBookings on queue every sweep interval
+func (s *BookingService) RegisterJobs(queue *jobs.Queue) error {
+	jobs.Handle(queue, sweepJob, func(ctx context.Context, _ struct{}) error {
+		expired, flagged, err := s.SweepStaleBookings(ctx)
+		if expired > 0 || flagged > 0 {
+			slog.InfoContext(ctx, "swept stale bookings", "expired", expired, "flagged_overdue", flagged)
+		}
+		return err
+	})
+	return jobs.Repeat(queue, sweepJob, jobs.Every(s.config.SweepInterval), struct{}{})
+}
+
+// expire cancels a request on the system's behalf because the provider never answered it
+func (s *BookingService) expire(ctx context.Context, request *domain.ServiceRequest) error {
+	cancellation := &domain.Cancellation{
+		At:     time.Now(),
+		By:     domain.CancelledBySystem,
+		From:   request.Status,
+		Reason: domain.ReasonExpired,
+	}
+	event, err := s.applyCancellation(ctx, request, cancellation)
+	if err != nil {
+		return err
+	}
+	s.notify(ctx, request, event)
+
+	return nil
+}
+
+// flagOverdue marks a confirmed booking that was never completed as overdue.
+// It stays confirmed, so the provider can still complete it or the customer
+// report a no-show.
+func (s *BookingService) flagOverdue(ctx context.Context, request *domain.ServiceRequest) error {
+	now := time.Now()
+	request.OverdueAt = &now
+	event := domain.NewServiceRequestEvent(request.ID, domain.EventFlaggedOverdue, domain.PartySystem, uuid.Nil)
+	event.Metadata["scheduled_date"] = request.StartDate()
+	event.Metadata["estimated_end_date"] = request.EstimatedEndDate
+
+	if err := s.requestRepo.FlagOverdue(ctx, request, event); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrInvalidTransition
+		}
+		return err
+	}
+	s.notify(ctx, request, event)
+
+	return nil
+}
+
+// notify tells both parties about event. The change is already made, so a
+// failed delivery is only logged.
+func (s *BookingService) notify(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) {
+	if err := s.notifier.NotifyParties(ctx, request, event); err != nil {
+		slog.ErrorContext(ctx, "failed to notify booking parties", "booking_id", request.ID, "event", event.Type, "error", err)
+	}
+}
+
+func (s *BookingService) cancel(ctx context.Context, request *domain.ServiceRequest, actor domain.CancellationActor, userID string, req *dto.CancelBookingRequest) error {
+	reason := domain.CancellationReason(req.Reason)
+	// No-shows are reported through ReportNoShow, which checks the booking has started
+	if !reason.IsValidFor(actor) || reason == domain.ReasonNoShow {
+		return ErrInvalidCancelReason
+	}
+	actorID, err := uuid.Parse(userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cancellation := &domain.Cancellation{
+		At:       now,
+		By:       actor,
+		ByUserID: actorID,
+		From:     request.Status,
+		Reason:   reason,
+		Note:     req.Note,
+	}
+	// Only customers pay for cancelling, and only once the provider has
+	// confirmed; providers' cancellations count against their reliability
+	// instead. Cancelling over the price is free, as the provider may have
+	// raised it since the booking was made.
+	if actor == domain.CancelledByCustomer && cancellation.From == domain.StatusConfirmed && reason != domain.ReasonPriceTooHigh {
+		cancellation.Fee = request.CancellationPolicy.Fee(request.QuotedPrice, request.StartDate().Sub(now))
+	}
+
+	_, err = s.applyCancellation(ctx, request, cancellation)
+	return err
+}
+
+// applyCancellation cancels request as recorded in cancellation, if no one
+// else has changed it in the meantime, and returns the event recorded for it
+func (s *BookingService) applyCancellation(ctx context.Context, request *domain.ServiceRequest, cancellation *domain.Cancellation) (*domain.ServiceRequestEvent, error) {
+	if !canTransition(request.Status, domain.StatusCancelled) {
+		return nil, ErrInvalidTransition
+	}
+
+	event := domain.NewServiceRequestEvent(request.ID, domain.EventStatusChanged, domain.BookingParty(cancellation.By), cancellation.ByUserID)
+	event.FromStatus = cancellation.From
+	event.ToStatus = domain.StatusCancelled
+	event.Note = cancellation.Note
+	event.Metadata["reason"] = cancellation.Reason
+	event.Metadata["fee"] = cancellation.Fee
+
+	request.Cancellation = cancellation
+	if err := s.requestRepo.Cancel(ctx, request, event); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrInvalidTransition
+		}
+		return nil, err
+	}
+	metrics.BookingTransitions.WithLabelValues(string(cancellation.From), string(domain.StatusCancelled)).Inc()
+
+	return event, nil
+}
+
+// ProposeReschedule proposes a new start date for a requested or confirmed
+// booking, which the other party then accepts, rejects or counters. The
+// provider must be available then and free of other confirmed bookings.
+func (s *BookingService) ProposeReschedule(ctx context.Context, party domain.BookingParty, userID, bookingID string, req *dto.ProposeRescheduleRequest) (*dto.RescheduleNegotiationResponse, error) {
+	request, err := s.getPartyRequest(ctx, party, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReschedule(request.Status) {
+		return nil, ErrInvalidTransition
+	}
+	proposerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkSchedule(ctx, request, req.ProposedDate); err != nil {
+		return nil, err
+	}
+
+	proposal := newRescheduleProposal(request, party, proposerID, req.ProposedDate, req.Note)
+	event := domain.NewServiceRequestEvent(request.ID, domain.EventRescheduleProposed, party, proposerID)
+	event.Note = req.Note
+	event.Metadata["proposal_id"] = proposal.ID
+	event.Metadata["proposed_date"] = proposal.ProposedDate
+	if err := s.rescheduleRepo.Create(ctx, proposal, event); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrReschedulePending
+		}
+		return nil, err
+	}
+
+	return s.negotiation(ctx, request)
+}
+
+// RespondToReschedule accepts, rejects or counters the other party's pending
+// reschedule proposal. Accepting moves the booking to the proposed date, once
+// it is checked against the provider's schedule again.
+func (s *BookingService) RespondToReschedule(ctx context.Context, party domain.BookingParty, userID, bookingID string, req *dto.RespondRescheduleRequest) (*dto.RescheduleNegotiationResponse, error) {
+	request, err := s.getPartyRequest(ctx, party, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if !canReschedule(request.Status) {
+		return nil, ErrInvalidTransition
+	}
+	responderID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal, err := s.rescheduleRepo.GetPending(ctx, request.ID.String())
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrNoPendingReschedule
+		}
+		return nil, err
+	}
+	if proposal.ProposedBy == party {
+		return nil, ErrOwnReschedule
+	}
+
+	now := time.Now()
+	proposal.RespondedAt = &now
+	proposal.RespondedByUserID = responderID
+	event := domain.NewServiceRequestEvent(request.ID, "", party, responderID)
+	event.Note = req.Note
+	event.Metadata["proposal_id"] = proposal.ID
+
+	switch req.Action {
+	case "accept":
+		if err := s.checkSchedule(ctx, request, proposal.ProposedDate); err != nil {
+			return nil, err
+		}
+		proposal.Status = domain.RescheduleAccepted
+		event.Type = domain.EventRescheduleAccepted
+		event.Metadata["previous_date"] = request.StartDate()
+		event.Metadata["scheduled_date"] = proposal.ProposedDate
+		scheduled := proposal.ProposedDate
+		request.ScheduledDate = &scheduled
+		request.UpdateEstimatedEnd()
+		err = s.rescheduleRepo.Accept(ctx, proposal, request, event)
+	case "reject":
+		proposal.Status = domain.RescheduleRejected
+		event.Type = domain.EventRescheduleRejected
+		err = s.rescheduleRepo.Reject(ctx, proposal, event)
+	case "counter":
+		if err := s.checkSchedule(ctx, request, *req.ProposedDate); err != nil {
+			return nil, err
+		}
+		proposal.Status = domain.RescheduleCountered
+		counter := newRescheduleProposal(request, party, responderID, *req.ProposedDate, req.Note)
+		counter.CounterToID = &proposal.ID
+		event.Type = domain.EventRescheduleCountered
+		event.Metadata["counter_proposal_id"] = counter.ID
+		event.Metadata["proposed_date"] = counter.ProposedDate
+		err = s.rescheduleRepo.Counter(ctx, proposal, counter, event)
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrOverlapping):
+			return nil, ErrScheduleConflict
+		case errors.Is(err, repository.ErrConflict):
+			return nil, ErrRescheduleChanged
+		}
+		return nil, err
+	}
+
+	return s.negotiation(ctx, request)
+}
+
+// ListReschedules returns a booking with its full reschedule negotiation history
+func (s *BookingService) ListReschedules(ctx context.Context, party domain.BookingParty, userID, bookingID string) (*dto.RescheduleNegotiationResponse, error) {
+	request, err := s.getPartyRequest(ctx, party, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.negotiation(ctx, request)
+}
+
+// Timeline returns a booking's history, oldest first
+func (s *BookingService) Timeline(ctx context.Context, party domain.BookingParty, userID, bookingID string) ([]*dto.BookingEventResponse, error) {
+	request, err := s.getPartyRequest(ctx, party, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.eventRepo.ListByRequestID(ctx, request.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.BookingEventResponse, 0, len(events))
+	for _, event := range events {
+		responses = append(responses, newBookingEventResponse(event))
+	}
+	return responses, nil
+}
+
+// AddNote adds a note to a booking's timeline, visible to both parties
+func (s *BookingService) AddNote(ctx context.Context, party domain.BookingParty, userID, bookingID string, req *dto.AddNoteRequest) (*dto.BookingEventResponse, error) {
+	request, err := s.getPartyRequest(ctx, party, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := newEvent(request, domain.EventNoteAdded, party, userID)
+	if err != nil {
+		return nil, err
+	}
+	event.Note = req.Note
+	if err := s.eventRepo.Create(ctx, event); err != nil {
+		return nil, err
+	}
+
+	return newBookingEventResponse(event), nil
+}
+
+// ChangePrice changes the quoted price of a booking the provider has not yet
+// confirmed. A customer who does not agree can cancel it, giving price_too_high
+// as the reason.
+func (s *BookingService) ChangePrice(ctx context.Context, userID, bookingID string, req *dto.ChangePriceRequest) (*dto.BookingResponse, error) {
+	request, err := s.getProviderRequest(ctx, userID, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if request.Status != domain.StatusRequested {
+		return nil, ErrInvalidTransition
+	}
+	if req.QuotedPrice == request.QuotedPrice {
+		return newBookingResponse(request), nil
+	}
+
+	event, err := newEvent(request, domain.EventPriceChanged, domain.PartyProvider, userID)
+	if err != nil {
+		return nil, err
+	}
+	event.Note = req.Note
+	event.Metadata["from"] = request.QuotedPrice
+	event.Metadata["to"] = req.QuotedPrice
+	event.Metadata["currency"] = request.Currency
+
+	request.QuotedPrice = req.QuotedPrice
+	if err := s.requestRepo.ChangePrice(ctx, request, event); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrInvalidTransition
+		}
+		return nil, err
+	}
+
+	return newBookingResponse(request), nil
+}
+
+// ProviderAnalytics summarizes the bookings the authenticated provider received in a period
+func (s *BookingService) ProviderAnalytics(ctx context.Context, userID string, query *dto.AnalyticsQuery) (*dto.BookingAnalyticsResponse, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.analytics(ctx, provider.ID.String(), query)
+}
+
+// Analytics summarizes the bookings made in a period, of one provider or of all
+func (s *BookingService) Analytics(ctx context.Context, query *dto.AnalyticsQuery) (*dto.BookingAnalyticsResponse, error) {
+	return s.analytics(ctx, query.ProviderID, query)
+}
+
+func (s *BookingService) analytics(ctx context.Context, providerID string, query *dto.AnalyticsQuery) (*dto.BookingAnalyticsResponse, error) {
+	from, to, err := s.analyticsPeriod(query)
+	if err != nil {
+		return nil, err
+	}
+
+	analytics, err := s.eventRepo.GetAnalytics(ctx, providerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.BookingAnalyticsResponse{
+		From:                   from.Format(time.DateOnly),
+		To:                     to.AddDate(0, 0, -1).Format(time.DateOnly),
+		Requested:              analytics.Requested,
+		Confirmed:              analytics.Confirmed,
+		Declined:               analytics.Declined,
+		Completed:              analytics.Completed,
+		CancelledByCustomer:    analytics.CancelledByCustomer,
+		CancelledByProvider:    analytics.CancelledByProvider,
+		CancelledBySystem:      analytics.CancelledBySystem,
+		NoShows:                analytics.NoShows,
+		FlaggedOverdue:         analytics.FlaggedOverdue,
+		AcceptanceRate:         analytics.AcceptanceRate(),
+		AverageResponseMinutes: analytics.AverageResponseMinutes,
+		RescheduleProposals:    analytics.RescheduleProposals,
+		ReschedulesAccepted:    analytics.ReschedulesAccepted,
+		PriceChanges:           analytics.PriceChanges,
+		CancellationFees:       analytics.CancellationFees,
+	}, nil
+}
+
+// analyticsPeriod returns the start of the query's first day and the end of
+// its last, in the booking timezone
+func (s *BookingService) analyticsPeriod(query *dto.AnalyticsQuery) (from, to time.Time, err error) {
+	year, month, day := time.Now().In(s.location).Date()
+	to = time.Date(year, month, day, 0, 0, 0, 0, s.location).AddDate(0, 0, 1)
+	if query.To != "" {
+		if to, err = time.ParseInLocation(time.DateOnly, query.To, s.location); err != nil {
+			return from, to, ErrInvalidPeriod
+		}
+		to = to.AddDate(0, 0, 1)
+	}
+
+	from = to.AddDate(0, 0, -defaultAnalyticsDays)
+	if query.From != "" {
+		if from, err = time.ParseInLocation(time.DateOnly, query.From, s.location); err != nil {
+			return from, to, ErrInvalidPeriod
+		}
+	}
+
+	if !from.Before(to) || to.After(from.AddDate(0, 0, maxAnalyticsDays)) {
+		return from, to, ErrInvalidPeriod
+	}
+	return from, to, nil
+}
+
+// checkSchedule checks that a booking could start at start: far enough ahead,
+// within one of the provider's availability slots (if the provider has set
+// any), and not overlapping the provider's other confirmed bookings
+func (s *BookingService) checkSchedule(ctx context.Context, request *domain.ServiceRequest, start time.Time) error {
+	if start.Before(time.Now().Add(s.config.MinLeadTime)) {
+		return ErrBookingTooSoon
+	}
+	end := start.Add(time.Duration(request.DurationMinutes) * time.Minute)
+	providerID := request.ProviderID.String()
+
+	slots, err := s.availabilityRepo.GetByProviderID(ctx, providerID)
+	if err != nil {
+		return err
+	}
+	if len(slots) > 0 {
+		localStart, localEnd := start.In(s.location), end.In(s.location)
+		available := false
+		for _, slot := range slots {
+			if slot.Covers(localStart, localEnd) {
+				available = true
+				break
+			}
+		}
+		if !available {
+			return ErrOutsideAvailability
+		}
+	}
+
+	overlapping, err := s.requestRepo.HasOverlapping(ctx, providerID, request.ID.String(), start, end)
+	if err != nil {
+		return err
+	}
+	if overlapping {
+		return ErrScheduleConflict
+	}
+
+	return nil
+}
+
+func (s *BookingService) negotiation(ctx context.Context, request *domain.ServiceRequest) (*dto.RescheduleNegotiationResponse, error) {
+	proposals, err := s.rescheduleRepo.ListByRequestID(ctx, request.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.RescheduleNegotiationResponse{
+		Booking:   newBookingResponse(request),
+		Proposals: make([]*dto.RescheduleProposalResponse, 0, len(proposals)),
+	}
+	for _, proposal := range proposals {
+		response.Proposals = append(response.Proposals, newRescheduleProposalResponse(proposal))
+	}
+
+	return response, nil
+}
+
+func newRescheduleProposal(request *domain.ServiceRequest, party domain.BookingParty, userID uuid.UUID, date time.Time, note string) *domain.RescheduleProposal {
+	return &domain.RescheduleProposal{
+		ID:               uuid.New(),
+		RequestID:        request.ID,
+		ProposedBy:       party,
+		ProposedByUserID: userID,
+		ProposedDate:     date,
+		Note:             note,
+		Status:           domain.ReschedulePending,
+	}
+}
+
+// canReschedule reports whether a booking in status may be moved to a new date
+func canReschedule(status domain.RequestStatus) bool {
+	return status == domain.StatusRequested || status == domain.StatusConfirmed
+}
+
+// transition moves a request to status to, if that is allowed from its current
+// status and no one else has changed it in the meantime, recording event
+func (s *BookingService) transition(ctx context.Context, request *domain.ServiceRequest, to domain.RequestStatus, event *domain.ServiceRequestEvent) error {
+	from := request.Status
+	if !canTransition(from, to) {
+		return ErrInvalidTransition
+	}
+
+	event.FromStatus = from
+	event.ToStatus = to
+	if err := s.requestRepo.UpdateStatus(ctx, request.ID.String(), from, to, event); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return ErrInvalidTransition
+		}
+		return err
+	}
+	request.Status = to
+	request.UpdatedAt = time.Now()
+	metrics.BookingTransitions.WithLabelValues(string(from), string(to)).Inc()
+
+	return nil
+}
+
+// newEvent starts an event on request by userID acting as party
+func newEvent(request *domain.ServiceRequest, eventType domain.ServiceRequestEventType, party domain.BookingParty, userID string) (*domain.ServiceRequestEvent, error) {
+	actorID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewServiceRequestEvent(request.ID, eventType, party, actorID), nil
+}
+
+// canTransition reports whether a booking may move between two statuses
+func canTransition(from, to domain.RequestStatus) bool {
+	switch from {
+	case domain.StatusRequested:
+		return to == domain.StatusConfirmed || to == domain.StatusCancelled
+	case domain.StatusConfirmed:
+		return to == domain.StatusCompleted || to == domain.StatusCancelled
+	}
+	return false
+}
+
+// getOrCreateCustomer returns the customer profile of a user, creating an
+// empty one on their first booking
+func (s *BookingService) getOrCreateCustomer(ctx context.Context, userID string) (*domain.Customer, error) {
+	customer, err := s.customerRepo.GetByUserID(ctx, userID)
+	if err == nil || !errors.Is(err, repository.ErrNotFound) {
+		return customer, err
+	}
+
+	ownerID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	customer = &domain.Customer{ID: uuid.New(), UserID: ownerID}
+	if err := s.customerRepo.Create(ctx, customer); err != nil {
+		// Created by a concurrent first booking
+		if errors.Is(err, repository.ErrConflict) {
+			return s.customerRepo.GetByUserID(ctx, userID)
+		}
+		return nil, err
+	}
+
+	return customer, nil
+}
+
+// getPartyRequest returns a booking the user takes part in as party
+func (s *BookingService) getPartyRequest(ctx context.Context, party domain.BookingParty, userID, bookingID string) (*domain.ServiceRequest, error) {
+	if party == domain.PartyProvider {
+		return s.getProviderRequest(ctx, userID, bookingID)
+	}
+	return s.getCustomerRequest(ctx, userID, bookingID)
+}
+
+func (s *BookingService) getCustomerRequest(ctx context.Context, userID, bookingID string) (*domain.ServiceRequest, error) {
+	customer, err := s.customerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookingNotFound
+		}
+		return nil, err
+	}
+
+	request, err := s.getRequest(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if request.CustomerID != customer.ID {
+		return nil, ErrBookingNotFound
+	}
+
+	return request, nil
+}
+
+func (s *BookingService) getProviderRequest(ctx context.Context, userID, bookingID string) (*domain.ServiceRequest, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := s.getRequest(ctx, bookingID)
+	if err != nil {
+		return nil, err
+	}
+	if request.ProviderID != provider.ID {
+		return nil, ErrBookingNotFound
+	}
+
+	return request, nil
+}
+
+func (s *BookingService) getRequest(ctx context.Context, bookingID string) (*domain.ServiceRequest, error) {
+	request, err := s.requestRepo.GetByID(ctx, bookingID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookingNotFound
+		}
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func (s *BookingService) getOwnProvider(ctx context.Context, userID string) (*domain.ServiceProvider, error) {
+	provider, err := s.providerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProviderProfileNotFound
+		}
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+func newBookingResponses(requests []*domain.ServiceRequest) []*dto.BookingResponse {
+	responses := make([]*dto.BookingResponse, 0, len(requests))
+	for _, request := range requests {
+		responses = append(responses, newBookingResponse(request))
+	}
+	return responses
+}
+
+func newBookingResponse(request *domain.ServiceRequest) *dto.BookingResponse {
+	response := &dto.BookingResponse{
+		ID:               request.ID.String(),
+		Status:           string(request.Status),
+		CustomerID:       idString(request.CustomerID),
+		ProviderID:       idString(request.ProviderID),
+		ServiceID:        idString(request.ServiceID),
+		ServiceName:      request.ServiceName,
+		QuotedPrice:      request.QuotedPrice,
+		Currency:         request.Currency,
+		DurationMinutes:  request.DurationMinutes,
+		RequestedDate:    request.RequestedDate,
+		ScheduledDate:    request.ScheduledDate,
+		EstimatedEndDate: request.EstimatedEndDate,
+		Address:          request.Address,
+		Notes:            request.Notes,
+		OverdueAt:        request.OverdueAt,
+		CreatedAt:        request.CreatedAt,
+		UpdatedAt:        request.UpdatedAt,
+
+		CancellationPolicy: make([]dto.CancellationWindowResponse, 0, len(request.CancellationPolicy)),
+	}
+	for _, window := range request.CancellationPolicy {
+		response.CancellationPolicy = append(response.CancellationPolicy, dto.CancellationWindowResponse{
+			HoursBefore: window.HoursBefore,
+			FeePercent:  window.FeePercent,
+		})
+	}
+	if c := request.Cancellation; c != nil {
+		response.Cancellation = &dto.CancellationResponse{
+			At:     c.At,
+			By:     string(c.By),
+			From:   string(c.From),
+			Reason: string(c.Reason),
+			Note:   c.Note,
+			Fee:    c.Fee,
+		}
+	}
+
+	return response
+}
+
+func newRescheduleProposalResponse(proposal *domain.RescheduleProposal) *dto.RescheduleProposalResponse {
+	response := &dto.RescheduleProposalResponse{
+		ID:           proposal.ID.String(),
+		ProposedBy:   string(proposal.ProposedBy),
+		ProposedDate: proposal.ProposedDate,
+		Note:         proposal.Note,
+		Status:       string(proposal.Status),
+		RespondedAt:  proposal.RespondedAt,
+		CreatedAt:    proposal.CreatedAt,
+	}
+	if proposal.CounterToID != nil {
+		response.CounterToID = proposal.CounterToID.String()
+	}
+
+	return response
+}
+
+func newBookingEventResponse(event *domain.ServiceRequestEvent) *dto.BookingEventResponse {
+	return &dto.BookingEventResponse{
+		ID:         event.ID.String(),
+		Type:       string(event.Type),
+		Actor:      string(event.Actor),
+		FromStatus: string(event.FromStatus),
+		ToStatus:   string(event.ToStatus),
+		Note:       event.Note,
+		Metadata:   event.Metadata,
+		CreatedAt:  event.CreatedAt,
+	}
+}
+
+// idString formats an ID, leaving references to deleted records empty
+func idString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
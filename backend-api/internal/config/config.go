@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds all configuration for the application
@@ -12,13 +15,44 @@ type Config struct {
 	Redis     RedisConfig
 	JWT       JWTConfig
 	Supabase  SupabaseConfig
+	OTP       OTPConfig
+	SMS       SMSConfig
+	MFA       MFAConfig
+	Password  PasswordConfig
+	Account   AccountConfig
+	Export    ExportConfig
+	Log       LogConfig
+	Tracing   TracingConfig
+	Health    HealthConfig
+	Cache     CacheConfig
+	Session   SessionConfig
+	GeoIP     GeoIPConfig
+	CORS      CORSConfig
+	Security  SecurityConfig
+	Booking   BookingConfig
+	Jobs      JobsConfig
+	RateLimit RateLimitConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port         string
-	Host         string
-	Environment  string
+	Port        string
+	Host        string
+	Environment string
+	DrainDelay  time.Duration // Time /readyz reports draining before shutdown starts
+
+	ReadHeaderTimeout time.Duration // Time allowed to read request headers
+	ReadTimeout       time.Duration // Time allowed to read a whole request, including the body
+	WriteTimeout      time.Duration // Time allowed from the end of the request headers to the end of the response
+	IdleTimeout       time.Duration // How long keep-alive connections wait for the next request
+
+	// TrustedProxies lists the addresses or CIDR ranges of the reverse proxies
+	// whose X-Forwarded-For and X-Real-IP headers are believed when resolving
+	// client IPs. When empty, the connection's remote address is always used.
+	TrustedProxies []string
+	// TrustedPlatform names a header set by the hosting platform that holds the
+	// client IP, e.g. CF-Connecting-IP; it takes precedence over TrustedProxies
+	TrustedPlatform string
 }
 
 // DatabaseConfig holds database configuration
@@ -34,32 +68,204 @@ type DatabaseConfig struct {
 
 // JWTConfig holds JWT configuration
 type JWTConfig struct {
-	SecretKey     string
+	SecretKey       string
 	ExpirationHours int
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Enabled   bool
+	Host      string
+	Port      string
+	Password  string
+	DB        int
+	Namespace string // Prefix of all keys and channels, so environments can share one Redis
 }
 
 // SupabaseConfig holds Supabase-specific configuration
 type SupabaseConfig struct {
-	URL              string
-	AnonKey          string
-	ServiceRoleKey   string
+	URL            string
+	AnonKey        string
+	ServiceRoleKey string
+}
+
+// OTPConfig holds one-time password configuration for phone verification and login
+type OTPConfig struct {
+	Length             int
+	TTL                time.Duration
+	MaxAttempts        int
+	ResendCooldown     time.Duration
+	DefaultCountryCode string // Used to normalize national numbers, e.g. "92" for Pakistan
+}
+
+// SMSConfig holds SMS delivery configuration
+type SMSConfig struct {
+	Provider string // "log" or "file"
+	FilePath string // Used by the "file" provider
+}
+
+// MFAConfig holds TOTP two-factor authentication configuration
+type MFAConfig struct {
+	Issuer        string // Shown in authenticator apps
+	EncryptionKey string // Encrypts TOTP secrets at rest
+	MaxAttempts   int    // Failed second-factor attempts allowed per lockout window
+	LockoutWindow time.Duration
+}
+
+// PasswordConfig holds password policy and hashing configuration
+type PasswordConfig struct {
+	MinLength         int
+	MaxLength         int // In bytes; bcrypt ignores anything past 72
+	RequireUpper      bool
+	RequireLower      bool
+	RequireNumber     bool
+	BreachedHashFile  string // Optional SHA-1 breached password list sorted by hash, e.g. a Have I Been Pwned export
+	BreachedMinCount  int    // Ignore hashes seen fewer times than this
+	HistorySize       int    // Reject reuse of the last N passwords
+	Algorithm         string // "bcrypt" or "argon2id"
+	BcryptCost        int
+	Argon2Memory      int // KiB
+	Argon2Iterations  int
+	Argon2Parallelism int
+}
+
+// AccountConfig holds account lifecycle configuration
+type AccountConfig struct {
+	DeletionGracePeriod   time.Duration // Time before a requested deletion is carried out
+	DeletionSweepInterval time.Duration // How often due deletions are processed
+}
+
+// ExportConfig holds personal data export configuration
+type ExportConfig struct {
+	LinkTTL       time.Duration // How long a finished export can be downloaded
+	SweepInterval time.Duration // How often expired archives are removed
+	SigningKey    string        // Signs download links
+}
+
+// LogConfig holds logging configuration
+type LogConfig struct {
+	Level string // "debug", "info", "warn" or "error"
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration. The OTLP endpoint,
+// headers and timeout are read by the exporter from the standard
+// OTEL_EXPORTER_OTLP_* environment variables.
+type TracingConfig struct {
+	Exporter    string // "none", "otlp" or "stdout"
+	ServiceName string
+	SampleRatio float64 // Fraction of new traces to sample; sampled parents are always followed
+}
+
+// HealthConfig holds readiness check configuration
+type HealthConfig struct {
+	CheckTimeout time.Duration // Per-dependency timeout for readiness checks
+}
+
+// CacheConfig holds read-through cache configuration for provider data
+type CacheConfig struct {
+	ProviderTTL      time.Duration // Lifetime of cached provider profiles, services and availability
+	SearchTTL        time.Duration // Lifetime of cached search results
+	LockTTL          time.Duration // How long a cache rebuild lock is held at most
+	LockWait         time.Duration // How long a request waits for another to rebuild an entry
+	GeohashPrecision int           // Geohash length of the cells search results are cached by
+}
+
+// SessionConfig holds cookie session configuration, the alternative to bearer
+// tokens for browser clients
+type SessionConfig struct {
+	CookieName     string
+	CSRFCookieName string        // Readable by scripts, which echo it in the X-CSRF-Token header
+	CookieDomain   string        // Empty for a host-only cookie
+	CookieSecure   bool          // Only send cookies over HTTPS
+	SameSite       string        // "lax", "strict" or "none" (requires CookieSecure)
+	IdleTimeout    time.Duration // Sessions expire after this long without requests
+	MaxAge         time.Duration // Sessions expire this long after login regardless of activity
+}
+
+// GeoIPConfig holds configuration for resolving the approximate location of logins
+type GeoIPConfig struct {
+	Provider string // "none" or "file"
+	FilePath string // Used by the "file" provider: CSV of network,location lines
+}
+
+// CORSConfig holds the default cross-origin policy of the API
+type CORSConfig struct {
+	AllowedOrigins   []string // Exact origins, "https://*.example.com" for subdomains, or "*"
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool // Cookies and Authorization headers; never for "*"
+	MaxAge           time.Duration
+}
+
+// SecurityConfig holds the security response headers and request limits
+type SecurityConfig struct {
+	HSTSMaxAge            time.Duration // Strict-Transport-Security max-age; 0 omits the header
+	HSTSIncludeSubdomains bool
+	ReferrerPolicy        string
+	FrameOptions          string // X-Frame-Options, e.g. "DENY"
+	ContentSecurityPolicy string // Empty omits the header
+	MaxBodyBytes          int64  // Default request body limit; routes may set their own
+}
+
+// BookingConfig holds configuration for customer bookings
+type BookingConfig struct {
+	Currency    string        // ISO 4217 currency of service prices
+	MinLeadTime time.Duration // How far ahead of its start a booking must be made
+	Timezone    string        // IANA zone providers' weekly availability is in, e.g. "Asia/Karachi"
+
+	RequestSLA    time.Duration // Requests not confirmed within this time expire
+	OverdueAfter  time.Duration // Confirmed bookings not completed this long after their end are flagged
+	NoShowGrace   time.Duration // How long after the start a customer may report a no-show
+	SweepInterval time.Duration // How often stale bookings are expired and flagged
+}
+
+// JobsConfig holds background job queue configuration
+type JobsConfig struct {
+	Backend      string        // "postgres" or "redis" (requires Redis to be enabled)
+	Concurrency  int           // Jobs run at once by each instance
+	PollInterval time.Duration // How often idle workers look for due jobs
+	Timeout      time.Duration // How long a run may take before it is cancelled and counted as failed
+	MaxAttempts  int           // Attempts of a job before it becomes a dead letter
+	BackoffBase  time.Duration // Delay before the first retry, doubled for each further one
+	BackoffMax   time.Duration
+	DrainTimeout time.Duration // How long shutdown waits for running jobs
+	Retention    time.Duration // How long completed jobs are kept
+
+	RatingRefreshSchedule string // Cron schedule (UTC) of the recomputation of providers' ratings
+}
+
+// RateLimitConfig holds per-client-IP request limits; a limit of 0 disables it
+type RateLimitConfig struct {
+	Window time.Duration // Length of the fixed window the limits count requests in
+	Auth   int           // Requests per window to the public auth routes and cookie session logins
+	Public int           // Requests per window to provider search and profiles
 }
 
 // LoadConfig loads configuration from environment variables
 func LoadConfig() *Config {
+	jwtSecret := getEnv("JWT_SECRET", "your-secret-key-change-in-production")
+	environment := getEnv("ENVIRONMENT", "development")
+
+	// Browsers remember HSTS, so it is off for local development over plain HTTP
+	hstsMaxAge := 365 * 24 * time.Hour
+	if environment == "development" {
+		hstsMaxAge = 0
+	}
+
 	return &Config{
 		Server: ServerConfig{
 			Port:        getEnv("SERVER_PORT", "8080"),
 			Host:        getEnv("SERVER_HOST", "localhost"),
-			Environment: getEnv("ENVIRONMENT", "development"),
+			Environment: environment,
+			DrainDelay:  getEnvDuration("SERVER_DRAIN_DELAY", 5*time.Second),
+
+			ReadHeaderTimeout: getEnvDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second),
+			ReadTimeout:       getEnvDuration("SERVER_READ_TIMEOUT", 15*time.Second),
+			WriteTimeout:      getEnvDuration("SERVER_WRITE_TIMEOUT", 60*time.Second),
+			IdleTimeout:       getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+			TrustedProxies:    getEnvList("TRUSTED_PROXIES", nil),
+			TrustedPlatform:   getEnv("TRUSTED_PLATFORM_HEADER", ""),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -71,13 +277,15 @@ func LoadConfig() *Config {
 			Driver:   getEnv("DB_DRIVER", "postgres"),
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       0,
+			Enabled:   getEnvBool("REDIS_ENABLED", true),
+			Host:      getEnv("REDIS_HOST", "localhost"),
+			Port:      getEnv("REDIS_PORT", "6379"),
+			Password:  getEnv("REDIS_PASSWORD", ""),
+			DB:        0,
+			Namespace: getEnv("REDIS_NAMESPACE", "karigar:"+environment),
 		},
 		JWT: JWTConfig{
-			SecretKey:       getEnv("JWT_SECRET", "your-secret-key-change-in-production"),
+			SecretKey:       jwtSecret,
 			ExpirationHours: 24,
 		},
 		Supabase: SupabaseConfig{
@@ -85,6 +293,122 @@ func LoadConfig() *Config {
 			AnonKey:        getEnv("SUPABASE_ANON_KEY", ""),
 			ServiceRoleKey: getEnv("SUPABASE_SERVICE_ROLE_KEY", ""),
 		},
+		OTP: OTPConfig{
+			Length:             getEnvInt("OTP_LENGTH", 6),
+			TTL:                getEnvDuration("OTP_TTL", 5*time.Minute),
+			MaxAttempts:        getEnvInt("OTP_MAX_ATTEMPTS", 5),
+			ResendCooldown:     getEnvDuration("OTP_RESEND_COOLDOWN", 60*time.Second),
+			DefaultCountryCode: getEnv("PHONE_DEFAULT_COUNTRY_CODE", "92"),
+		},
+		SMS: SMSConfig{
+			Provider: getEnv("SMS_PROVIDER", "log"),
+			FilePath: getEnv("SMS_FILE_PATH", "sms_outbox.log"),
+		},
+		MFA: MFAConfig{
+			Issuer:        getEnv("MFA_ISSUER", "Karigar"),
+			EncryptionKey: getEnv("MFA_ENCRYPTION_KEY", jwtSecret),
+			MaxAttempts:   getEnvInt("MFA_MAX_ATTEMPTS", 5),
+			LockoutWindow: getEnvDuration("MFA_LOCKOUT_WINDOW", 5*time.Minute),
+		},
+		Password: PasswordConfig{
+			MinLength:         getEnvInt("PASSWORD_MIN_LENGTH", 8),
+			MaxLength:         getEnvInt("PASSWORD_MAX_LENGTH", 72),
+			RequireUpper:      getEnvBool("PASSWORD_REQUIRE_UPPER", true),
+			RequireLower:      getEnvBool("PASSWORD_REQUIRE_LOWER", true),
+			RequireNumber:     getEnvBool("PASSWORD_REQUIRE_NUMBER", true),
+			BreachedHashFile:  getEnv("PASSWORD_BREACHED_HASH_FILE", ""),
+			BreachedMinCount:  getEnvInt("PASSWORD_BREACHED_MIN_COUNT", 1),
+			HistorySize:       getEnvInt("PASSWORD_HISTORY_SIZE", 5),
+			Algorithm:         getEnv("PASSWORD_HASH_ALGORITHM", "bcrypt"),
+			BcryptCost:        getEnvInt("PASSWORD_BCRYPT_COST", 10),
+			Argon2Memory:      getEnvInt("PASSWORD_ARGON2_MEMORY_KIB", 19*1024),
+			Argon2Iterations:  getEnvInt("PASSWORD_ARGON2_ITERATIONS", 2),
+			Argon2Parallelism: getEnvInt("PASSWORD_ARGON2_PARALLELISM", 1),
+		},
+		Account: AccountConfig{
+			DeletionGracePeriod:   getEnvDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+			DeletionSweepInterval: getEnvDuration("ACCOUNT_DELETION_SWEEP_INTERVAL", time.Hour),
+		},
+		Export: ExportConfig{
+			LinkTTL:       getEnvDuration("EXPORT_LINK_TTL", 7*24*time.Hour),
+			SweepInterval: getEnvDuration("EXPORT_SWEEP_INTERVAL", time.Hour),
+			SigningKey:    getEnv("EXPORT_SIGNING_KEY", jwtSecret),
+		},
+		Log: LogConfig{
+			Level: getEnv("LOG_LEVEL", "info"),
+		},
+		Tracing: TracingConfig{
+			Exporter:    getEnv("TRACING_EXPORTER", "none"),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "karigar-api"),
+			SampleRatio: getEnvFloat("TRACING_SAMPLE_RATIO", 1),
+		},
+		Health: HealthConfig{
+			CheckTimeout: getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		},
+		Cache: CacheConfig{
+			ProviderTTL:      getEnvDuration("CACHE_PROVIDER_TTL", 10*time.Minute),
+			SearchTTL:        getEnvDuration("CACHE_SEARCH_TTL", 2*time.Minute),
+			LockTTL:          getEnvDuration("CACHE_LOCK_TTL", 5*time.Second),
+			LockWait:         getEnvDuration("CACHE_LOCK_WAIT", 500*time.Millisecond),
+			GeohashPrecision: getEnvInt("CACHE_SEARCH_GEOHASH_PRECISION", 6),
+		},
+		Session: SessionConfig{
+			CookieName:     getEnv("SESSION_COOKIE_NAME", "karigar_session"),
+			CSRFCookieName: getEnv("SESSION_CSRF_COOKIE_NAME", "karigar_csrf"),
+			CookieDomain:   getEnv("SESSION_COOKIE_DOMAIN", ""),
+			CookieSecure:   getEnvBool("SESSION_COOKIE_SECURE", environment != "development"),
+			SameSite:       getEnv("SESSION_SAME_SITE", "lax"),
+			IdleTimeout:    getEnvDuration("SESSION_IDLE_TIMEOUT", 2*time.Hour),
+			MaxAge:         getEnvDuration("SESSION_MAX_AGE", 30*24*time.Hour),
+		},
+		GeoIP: GeoIPConfig{
+			Provider: getEnv("GEOIP_PROVIDER", "none"),
+			FilePath: getEnv("GEOIP_FILE", ""),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   getEnvList("CORS_ALLOWED_ORIGINS", []string{"http://localhost:3000"}),
+			AllowedMethods:   getEnvList("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE"}),
+			AllowedHeaders:   getEnvList("CORS_ALLOWED_HEADERS", []string{"Authorization", "Content-Type", "X-CSRF-Token", "X-Request-ID", "traceparent", "tracestate"}),
+			ExposedHeaders:   getEnvList("CORS_EXPOSED_HEADERS", []string{"X-Request-ID", "X-RateLimit-Limit", "X-RateLimit-Remaining", "X-RateLimit-Reset", "Retry-After"}),
+			AllowCredentials: getEnvBool("CORS_ALLOW_CREDENTIALS", true),
+			MaxAge:           getEnvDuration("CORS_MAX_AGE", 10*time.Minute),
+		},
+		Security: SecurityConfig{
+			HSTSMaxAge:            getEnvDuration("SECURITY_HSTS_MAX_AGE", hstsMaxAge),
+			HSTSIncludeSubdomains: getEnvBool("SECURITY_HSTS_INCLUDE_SUBDOMAINS", true),
+			ReferrerPolicy:        getEnv("SECURITY_REFERRER_POLICY", "no-referrer"),
+			FrameOptions:          getEnv("SECURITY_FRAME_OPTIONS", "DENY"),
+			ContentSecurityPolicy: getEnv("SECURITY_CONTENT_SECURITY_POLICY", "default-src 'none'; frame-ancestors 'none'"),
+			MaxBodyBytes:          int64(getEnvInt("MAX_BODY_BYTES", 1<<20)),
+		},
+		Booking: BookingConfig{
+			Currency:    getEnv("BOOKING_CURRENCY", "PKR"),
+			MinLeadTime: getEnvDuration("BOOKING_MIN_LEAD_TIME", time.Hour),
+			Timezone:    getEnv("BOOKING_TIMEZONE", "Asia/Karachi"),
+
+			RequestSLA:    getEnvDuration("BOOKING_REQUEST_SLA", 24*time.Hour),
+			OverdueAfter:  getEnvDuration("BOOKING_OVERDUE_AFTER", 12*time.Hour),
+			NoShowGrace:   getEnvDuration("BOOKING_NO_SHOW_GRACE", 30*time.Minute),
+			SweepInterval: getEnvDuration("BOOKING_SWEEP_INTERVAL", 5*time.Minute),
+		},
+		Jobs: JobsConfig{
+			Backend:      getEnv("JOBS_BACKEND", "postgres"),
+			Concurrency:  getEnvInt("JOBS_CONCURRENCY", 4),
+			PollInterval: getEnvDuration("JOBS_POLL_INTERVAL", time.Second),
+			Timeout:      getEnvDuration("JOBS_TIMEOUT", 10*time.Minute),
+			MaxAttempts:  getEnvInt("JOBS_MAX_ATTEMPTS", 5),
+			BackoffBase:  getEnvDuration("JOBS_BACKOFF_BASE", 30*time.Second),
+			BackoffMax:   getEnvDuration("JOBS_BACKOFF_MAX", time.Hour),
+			DrainTimeout: getEnvDuration("JOBS_DRAIN_TIMEOUT", 30*time.Second),
+			Retention:    getEnvDuration("JOBS_RETENTION", 7*24*time.Hour),
+
+			RatingRefreshSchedule: getEnv("JOBS_RATING_REFRESH_SCHEDULE", "30 21 * * *"),
+		},
+		RateLimit: RateLimitConfig{
+			Window: getEnvDuration("RATE_LIMIT_WINDOW", time.Minute),
+			Auth:   getEnvInt("RATE_LIMIT_AUTH", 20),
+			Public: getEnvInt("RATE_LIMIT_PUBLIC", 300),
+		},
 	}
 }
 
@@ -109,3 +433,54 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvList reads a comma-separated list
+func getEnvList(key string, defaultValue []string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var list []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions
+const (
+	AuditLogin                  = "auth.login"
+	AuditLoginFailed            = "auth.login_failed"
+	AuditNewDeviceLogin         = "auth.new_device_login"
+	AuditSessionRevoked         = "auth.session_revoked"
+	AuditPasswordChanged        = "auth.password_changed"
+	AuditPasswordReset          = "auth.password_reset"
+	AuditPhoneVerified          = "auth.phone_verified"
+	AuditMFAEnabled             = "auth.mfa_enabled"
+	AuditMFADisabled            = "auth.mfa_disabled"
+	AuditMFARecoveryCodesReset  = "auth.mfa_recovery_codes_regenerated"
+	AuditAccountDeletionRequest = "account.deletion_requested"
+	AuditAccountDeletionCancel  = "account.deletion_cancelled"
+	AuditDataExportRequested    = "account.data_export_requested"
+	AuditDataExportDownloaded   = "account.data_export_downloaded"
+)
+
+// AuditEvent records a security-relevant action on a user's account
+type AuditEvent struct {
+	ID        uuid.UUID              `json:"id" db:"id"`
+	UserID    *uuid.UUID             `json:"user_id" db:"user_id"`   // Nullable
+	ActorID   *uuid.UUID             `json:"actor_id" db:"actor_id"` // Nullable
+	Action    string                 `json:"action" db:"action"`
+	IPAddress string                 `json:"ip_address,omitempty" db:"ip_address"`
+	UserAgent string                 `json:"user_agent,omitempty" db:"user_agent"`
+	Metadata  map[string]interface{} `json:"metadata" db:"metadata"`
+	CreatedAt time.Time              `json:"created_at" db:"created_at"`
+}
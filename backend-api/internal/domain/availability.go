@@ -31,3 +31,22 @@ type Availability struct {
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
 
+// Covers reports whether the slot contains the whole period from start to end,
+// read as clock times in their own location. Periods that cross midnight are
+// never covered.
+func (a *Availability) Covers(start, end time.Time) bool {
+	if !a.IsAvailable || DayOfWeek(start.Weekday()) != a.DayOfWeek {
+		return false
+	}
+	if sy, sm, sd := start.Date(); !sameDate(end, sy, sm, sd) {
+		return false
+	}
+
+	// HH:MM strings compare in time order
+	return start.Format("15:04") >= a.StartTime && end.Format("15:04") <= a.EndTime
+}
+
+func sameDate(t time.Time, year int, month time.Month, day int) bool {
+	y, m, d := t.Date()
+	return y == year && m == month && d == day
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CancellationActor is the party that cancelled a booking
+type CancellationActor string
+
+const (
+	CancelledByCustomer CancellationActor = "customer"
+	CancelledByProvider CancellationActor = "provider"
+	CancelledBySystem   CancellationActor = "system"
+)
+
+// CancellationReason is a machine-readable reason for cancelling a booking
+type CancellationReason string
+
+const (
+	// Customer reasons
+	ReasonScheduleConflict CancellationReason = "schedule_conflict"
+	ReasonFoundAlternative CancellationReason = "found_alternative"
+	ReasonNoLongerNeeded   CancellationReason = "no_longer_needed"
+	ReasonPriceTooHigh     CancellationReason = "price_too_high"
+
+	// Provider reasons
+	ReasonProviderUnavailable CancellationReason = "provider_unavailable"
+	ReasonOutsideServiceArea  CancellationReason = "outside_service_area"
+	ReasonCustomerUnreachable CancellationReason = "customer_unreachable"
+
+	// Either party
+	ReasonEmergency CancellationReason = "emergency"
+	ReasonOther     CancellationReason = "other"
+
+	// The provider did not turn up; reported by the customer
+	ReasonNoShow CancellationReason = "no_show"
+	// Not confirmed in time; set by the system
+	ReasonExpired CancellationReason = "expired"
+)
+
+// IsValidFor reports whether actor may give reason r when cancelling
+func (r CancellationReason) IsValidFor(actor CancellationActor) bool {
+	switch r {
+	case ReasonEmergency, ReasonOther:
+		return actor == CancelledByCustomer || actor == CancelledByProvider
+	case ReasonScheduleConflict, ReasonFoundAlternative, ReasonNoLongerNeeded, ReasonPriceTooHigh:
+		return actor == CancelledByCustomer
+	case ReasonProviderUnavailable, ReasonOutsideServiceArea, ReasonCustomerUnreachable:
+		return actor == CancelledByProvider
+	case ReasonNoShow:
+		return actor == CancelledByCustomer
+	case ReasonExpired:
+		return actor == CancelledBySystem
+	}
+	return false
+}
+
+// Cancellation records who cancelled a booking, when and why
+type Cancellation struct {
+	At       time.Time          `json:"at"`
+	By       CancellationActor  `json:"by"`
+	ByUserID uuid.UUID          `json:"by_user_id"` // uuid.Nil for the system or once the account is deleted
+	From     RequestStatus      `json:"from"`       // Status the booking was cancelled from
+	Reason   CancellationReason `json:"reason"`
+	Note     string             `json:"note"`
+	Fee      float64            `json:"fee"` // Charged to the customer
+}
+
+// CancellationWindow charges a customer who cancels less than HoursBefore hours
+// before a booking starts FeePercent of its price
+type CancellationWindow struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	ProviderID  uuid.UUID `json:"provider_id" db:"provider_id"`
+	HoursBefore int       `json:"hours_before" db:"hours_before"`
+	FeePercent  float64   `json:"fee_percent" db:"fee_percent"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
+
+// CancellationPolicy is a provider's set of cancellation windows. Cancelling
+// outside all of them is free.
+type CancellationPolicy []CancellationWindow
+
+// FeePercent returns the percentage of the price owed for cancelling with the
+// given notice before the start; the narrowest window containing it applies
+func (p CancellationPolicy) FeePercent(notice time.Duration) float64 {
+	windows := make(CancellationPolicy, len(p))
+	copy(windows, p)
+	sort.Slice(windows, func(i, j int) bool { return windows[i].HoursBefore < windows[j].HoursBefore })
+
+	for _, window := range windows {
+		if notice < time.Duration(window.HoursBefore)*time.Hour {
+			return window.FeePercent
+		}
+	}
+	return 0
+}
+
+// Fee returns the fee, rounded to two decimals, for cancelling a booking of
+// the given price with the given notice
+func (p CancellationPolicy) Fee(price float64, notice time.Duration) float64 {
+	return math.Round(price*p.FeePercent(notice)) / 100
+}
@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ServiceRequestEventType is the kind of change a service request event records
+type ServiceRequestEventType string
+
+const (
+	EventStatusChanged       ServiceRequestEventType = "status_changed"
+	EventRescheduleProposed  ServiceRequestEventType = "reschedule_proposed"
+	EventRescheduleAccepted  ServiceRequestEventType = "reschedule_accepted"
+	EventRescheduleRejected  ServiceRequestEventType = "reschedule_rejected"
+	EventRescheduleCountered ServiceRequestEventType = "reschedule_countered"
+	EventRescheduleExpired   ServiceRequestEventType = "reschedule_expired"
+	EventNoteAdded           ServiceRequestEventType = "note_added"
+	EventPriceChanged        ServiceRequestEventType = "price_changed"
+	EventFlaggedOverdue      ServiceRequestEventType = "flagged_overdue"
+)
+
+// ServiceRequestEvent is an entry in a booking's append-only history
+type ServiceRequestEvent struct {
+	ID          uuid.UUID               `json:"id" db:"id"`
+	RequestID   uuid.UUID               `json:"request_id" db:"request_id"`
+	Type        ServiceRequestEventType `json:"type" db:"type"`
+	Actor       BookingParty            `json:"actor" db:"actor"`
+	ActorUserID uuid.UUID               `json:"actor_user_id" db:"actor_user_id"` // uuid.Nil for the system or once the account is deleted
+	FromStatus  RequestStatus           `json:"from_status" db:"from_status"`     // status_changed only; empty on creation
+	ToStatus    RequestStatus           `json:"to_status" db:"to_status"`         // status_changed only
+	Note        string                  `json:"note" db:"note"`
+	Metadata    map[string]interface{}  `json:"metadata" db:"metadata"`
+	CreatedAt   time.Time               `json:"created_at" db:"created_at"`
+}
+
+// NewServiceRequestEvent creates an event of the given type on a request
+func NewServiceRequestEvent(requestID uuid.UUID, eventType ServiceRequestEventType, actor BookingParty, actorUserID uuid.UUID) *ServiceRequestEvent {
+	return &ServiceRequestEvent{
+		ID:          uuid.New(),
+		RequestID:   requestID,
+		Type:        eventType,
+		Actor:       actor,
+		ActorUserID: actorUserID,
+		Metadata:    map[string]interface{}{},
+		CreatedAt:   time.Now(),
+	}
+}
+
+// BookingAnalytics summarizes what happened to the bookings requested in a
+// period, computed from their events
+type BookingAnalytics struct {
+	From                   time.Time
+	To                     time.Time
+	Requested              int
+	Confirmed              int
+	Declined               int // Cancelled by the provider before confirming
+	Completed              int
+	CancelledByCustomer    int
+	CancelledByProvider    int // After confirming
+	CancelledBySystem      int // Expired before the provider answered
+	NoShows                int // Reported by customers; included in CancelledByCustomer
+	FlaggedOverdue         int
+	RescheduleProposals    int // Including counter-proposals
+	ReschedulesAccepted    int
+	PriceChanges           int
+	CancellationFees       float64
+	AverageResponseMinutes *float64 // From request to the provider's confirmation or decline; nil if none were answered
+}
+
+// AcceptanceRate returns the percentage of answered requests the provider
+// confirmed, or nil if none were answered
+func (a *BookingAnalytics) AcceptanceRate() *float64 {
+	answered := a.Confirmed + a.Declined
+	if answered == 0 {
+		return nil
+	}
+	rate := 100 * float64(a.Confirmed) / float64(answered)
+	return &rate
+}
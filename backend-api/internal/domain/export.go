@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportStatus represents the status of a personal data export
+type ExportStatus string
+
+const (
+	ExportStatusPending    ExportStatus = "pending"
+	ExportStatusProcessing ExportStatus = "processing"
+	ExportStatusCompleted  ExportStatus = "completed"
+	ExportStatusFailed     ExportStatus = "failed"
+	ExportStatusExpired    ExportStatus = "expired"
+)
+
+// DataExport represents a personal data export job and its resulting archive
+type DataExport struct {
+	ID          uuid.UUID    `json:"id" db:"id"`
+	UserID      uuid.UUID    `json:"user_id" db:"user_id"`
+	RequestedBy *uuid.UUID   `json:"requested_by,omitempty" db:"requested_by"` // Admin, nullable
+	Status      ExportStatus `json:"status" db:"status"`
+	FileSize    int64        `json:"file_size,omitempty" db:"file_size"`
+	Error       string       `json:"error,omitempty" db:"error"`
+	CreatedAt   time.Time    `json:"created_at" db:"created_at"`
+	StartedAt   *time.Time   `json:"started_at,omitempty" db:"started_at"`
+	CompletedAt *time.Time   `json:"completed_at,omitempty" db:"completed_at"`
+	ExpiresAt   *time.Time   `json:"expires_at,omitempty" db:"expires_at"`
+	UpdatedAt   time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// PersonalData is everything stored about a user, grouped by section. Rows are
+// exported generically (column name to value) so new columns are included automatically.
+type PersonalData struct {
+	User            map[string]interface{}   `json:"user"`
+	CustomerProfile map[string]interface{}   `json:"customer_profile,omitempty"`
+	ProviderProfile map[string]interface{}   `json:"provider_profile,omitempty"`
+	Services        []map[string]interface{} `json:"services"`
+	Bookings        []map[string]interface{} `json:"bookings"`
+	BookingEvents   []map[string]interface{} `json:"booking_events"` // Changes the user made to bookings
+	ReviewsWritten  []map[string]interface{} `json:"reviews_written"`
+	ReviewsReceived []map[string]interface{} `json:"reviews_received"`
+	Availability    []map[string]interface{} `json:"availability"`
+	AuditEvents     []map[string]interface{} `json:"audit_events"`
+	Sessions        []map[string]interface{} `json:"sessions"`
+}
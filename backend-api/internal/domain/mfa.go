@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFARolePolicy controls whether users with a given role must use two-factor authentication
+type MFARolePolicy struct {
+	Role        UserRole   `json:"role" db:"role"`
+	MFARequired bool       `json:"mfa_required" db:"mfa_required"`
+	UpdatedBy   *uuid.UUID `json:"updated_by,omitempty" db:"updated_by"` // Nullable
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at" db:"updated_at"`
+}
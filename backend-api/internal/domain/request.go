@@ -19,19 +19,46 @@ const (
 // ServiceRequest represents a service request from a customer to a service provider
 type ServiceRequest struct {
 	ID            uuid.UUID     `json:"id" db:"id"`
-	CustomerID    uuid.UUID     `json:"customer_id" db:"customer_id"`
-	ProviderID    uuid.UUID     `json:"provider_id" db:"provider_id"`
-	ServiceID     uuid.UUID     `json:"service_id" db:"service_id"`
+	CustomerID    uuid.UUID     `json:"customer_id" db:"customer_id"` // uuid.Nil once the customer account is deleted
+	ProviderID    uuid.UUID     `json:"provider_id" db:"provider_id"` // uuid.Nil once the provider account is deleted
+	ServiceID     uuid.UUID     `json:"service_id" db:"service_id"`   // uuid.Nil once the service is deleted
 	Status        RequestStatus `json:"status" db:"status"`
 	RequestedDate time.Time     `json:"requested_date" db:"requested_date"`
 	ScheduledDate *time.Time    `json:"scheduled_date" db:"scheduled_date"` // Nullable
 	Address       string        `json:"address" db:"address"`
 	Notes         string        `json:"notes" db:"notes"`
-	CreatedAt     time.Time     `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time     `json:"updated_at" db:"updated_at"`
+
+	// Terms of the service when it was booked, unaffected by later changes to it
+	ServiceName      string     `json:"service_name" db:"service_name"`
+	QuotedPrice      float64    `json:"quoted_price" db:"quoted_price"`
+	Currency         string     `json:"currency" db:"currency"` // ISO 4217, e.g. "PKR"
+	DurationMinutes  int        `json:"duration_minutes" db:"duration_minutes"`
+	EstimatedEndDate *time.Time `json:"estimated_end_date" db:"estimated_end_date"` // Nullable for bookings made before snapshots
+
+	CancellationPolicy CancellationPolicy `json:"cancellation_policy" db:"cancellation_policy"` // The provider's policy when booked
+	Cancellation       *Cancellation      `json:"cancellation,omitempty"`                       // Set once cancelled
+	OverdueAt          *time.Time         `json:"overdue_at,omitempty" db:"overdue_at"`         // When flagged as not completed long after its end
+
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 
 	// Joined data (optional)
 	Customer *Customer        `json:"customer,omitempty"`
 	Provider *ServiceProvider `json:"provider,omitempty"`
 	Service  *Service         `json:"service,omitempty"`
 }
+
+// StartDate returns when the service is due to start: the scheduled date once
+// confirmed, otherwise the date the customer asked for
+func (r *ServiceRequest) StartDate() time.Time {
+	if r.ScheduledDate != nil {
+		return *r.ScheduledDate
+	}
+	return r.RequestedDate
+}
+
+// UpdateEstimatedEnd recomputes EstimatedEndDate from the start date and the booked duration
+func (r *ServiceRequest) UpdateEstimatedEnd() {
+	end := r.StartDate().Add(time.Duration(r.DurationMinutes) * time.Minute)
+	r.EstimatedEndDate = &end
+}
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BookingParty is one of the two sides of a booking, or the system acting on it
+type BookingParty string
+
+const (
+	PartyCustomer BookingParty = "customer"
+	PartyProvider BookingParty = "provider"
+	PartySystem   BookingParty = "system"
+)
+
+// RescheduleStatus represents the status of a reschedule proposal
+type RescheduleStatus string
+
+const (
+	ReschedulePending   RescheduleStatus = "pending"
+	RescheduleAccepted  RescheduleStatus = "accepted"
+	RescheduleRejected  RescheduleStatus = "rejected"
+	RescheduleCountered RescheduleStatus = "countered"
+	RescheduleExpired   RescheduleStatus = "expired" // The booking was completed or cancelled first
+)
+
+// RescheduleProposal is one party's proposal to move a booking to a new date.
+// A booking has at most one pending proposal, which only the other party can
+// answer.
+type RescheduleProposal struct {
+	ID                uuid.UUID        `json:"id" db:"id"`
+	RequestID         uuid.UUID        `json:"request_id" db:"request_id"`
+	ProposedBy        BookingParty     `json:"proposed_by" db:"proposed_by"`
+	ProposedByUserID  uuid.UUID        `json:"proposed_by_user_id" db:"proposed_by_user_id"` // uuid.Nil once the account is deleted
+	ProposedDate      time.Time        `json:"proposed_date" db:"proposed_date"`
+	Note              string           `json:"note" db:"note"`
+	Status            RescheduleStatus `json:"status" db:"status"`
+	CounterToID       *uuid.UUID       `json:"counter_to_id" db:"counter_to_id"` // The proposal this one answers, if any
+	RespondedAt       *time.Time       `json:"responded_at" db:"responded_at"`
+	RespondedByUserID uuid.UUID        `json:"responded_by_user_id" db:"responded_by_user_id"`
+	CreatedAt         time.Time        `json:"created_at" db:"created_at"`
+}
@@ -10,8 +10,8 @@ import (
 type Review struct {
 	ID         uuid.UUID `json:"id" db:"id"`
 	RequestID  uuid.UUID `json:"request_id" db:"request_id"`
-	CustomerID uuid.UUID `json:"customer_id" db:"customer_id"`
-	ProviderID uuid.UUID `json:"provider_id" db:"provider_id"`
+	CustomerID uuid.UUID `json:"customer_id" db:"customer_id"` // uuid.Nil once the customer account is deleted
+	ProviderID uuid.UUID `json:"provider_id" db:"provider_id"` // uuid.Nil once the provider account is deleted
 	Rating     int       `json:"rating" db:"rating"` // 1-5 scale
 	Comment    string    `json:"comment" db:"comment"`
 	CreatedAt  time.Time `json:"created_at" db:"created_at"`
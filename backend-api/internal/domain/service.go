@@ -18,6 +18,15 @@ const (
 	CategoryOther         ServiceCategory = "other"
 )
 
+// IsValid reports whether c is a known category
+func (c ServiceCategory) IsValid() bool {
+	switch c {
+	case CategoryPlumbing, CategoryElectrical, CategoryCleaning, CategoryTutoring, CategoryRepair, CategoryOther:
+		return true
+	}
+	return false
+}
+
 // Service represents a service offered by a service provider
 type Service struct {
 	ID          uuid.UUID      `json:"id" db:"id"`
@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a server-side login session, identified by an opaque ID held in a
+// cookie. The ID itself is never stored; sessions are keyed by its hash. Each is
+// backed by a UserSession, which lists it among the user's devices and revokes it.
+type Session struct {
+	ID            string    `json:"-"`
+	UserSessionID uuid.UUID `json:"user_session_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	Email         string    `json:"email"`
+	Role          UserRole  `json:"role"`
+	CSRFToken     string    `json:"csrf_token"`
+	IPAddress     string    `json:"ip_address,omitempty"`
+	UserAgent     string    `json:"user_agent,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastSeenAt    time.Time `json:"last_seen_at"`
+	ExpiresAt     time.Time `json:"expires_at"` // Absolute expiry; idle expiry is the cache TTL
+}
+
+// UserSession is a login, i.e. the chain of refresh tokens issued from it, along
+// with the device it was made from
+type UserSession struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	UserID         uuid.UUID  `json:"user_id" db:"user_id"`
+	RefreshTokenID uuid.UUID  `json:"-" db:"refresh_token_id"` // Only the newest refresh token is accepted
+	UserAgent      string     `json:"user_agent,omitempty" db:"user_agent"`
+	IPAddress      string     `json:"ip_address,omitempty" db:"ip_address"`
+	Location       string     `json:"location,omitempty" db:"location"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	LastSeenAt     time.Time  `json:"last_seen_at" db:"last_seen_at"`
+	ExpiresAt      time.Time  `json:"expires_at" db:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+}
+
+// IsActive reports whether refresh tokens may still be used with the session
+func (s *UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
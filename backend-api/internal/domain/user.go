@@ -15,6 +15,15 @@ const (
 	RoleAdmin          UserRole = "admin"
 )
 
+// IsValid reports whether r is a known role
+func (r UserRole) IsValid() bool {
+	switch r {
+	case RoleCustomer, RoleServiceProvider, RoleAdmin:
+		return true
+	}
+	return false
+}
+
 // User represents a base user in the system
 type User struct {
 	ID                uuid.UUID  `json:"id" db:"id"`
@@ -26,6 +35,16 @@ type User struct {
 	EmailVerifyExpiry *time.Time `json:"-" db:"email_verify_expiry"` // Nullable
 	PasswordResetToken *string   `json:"-" db:"password_reset_token"` // Nullable
 	PasswordResetExpiry *time.Time `json:"-" db:"password_reset_expiry"` // Nullable
+	Phone             *string    `json:"phone,omitempty" db:"phone"` // E.164, nullable
+	IsPhoneVerified   bool       `json:"is_phone_verified" db:"is_phone_verified"`
+	PhoneVerifiedAt   *time.Time `json:"-" db:"phone_verified_at"` // Nullable
+	MFAEnabled        bool       `json:"mfa_enabled" db:"mfa_enabled"`
+	MFASecret         *string    `json:"-" db:"mfa_secret"` // Encrypted, nullable
+	MFAEnrolledAt     *time.Time `json:"-" db:"mfa_enrolled_at"` // Nullable
+	MFALastUsedStep   *int64     `json:"-" db:"mfa_last_used_step"` // Nullable
+	TokenVersion      int        `json:"-" db:"token_version"` // Incremented to revoke refresh tokens
+	DeletionRequestedAt *time.Time `json:"deletion_requested_at,omitempty" db:"deletion_requested_at"` // Nullable
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" db:"deletion_scheduled_at"` // Nullable
 	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
 }
@@ -56,6 +75,8 @@ type ServiceProvider struct {
 	IsActive    bool      `json:"is_active" db:"is_active"`
 	Rating      float64   `json:"rating" db:"rating"`
 	TotalReviews int      `json:"total_reviews" db:"total_reviews"`
+	ReliabilityScore float64 `json:"reliability_score" db:"reliability_score"` // Percentage of confirmed bookings the provider neither cancelled nor missed
+	ProviderCancellations int `json:"provider_cancellations" db:"provider_cancellations"`
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 	User        *User     `json:"user,omitempty"` // For joined queries
@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/health/dto"
+	"karigar-backend/internal/health/service"
+)
+
+type HealthHandler struct {
+	healthService *service.HealthService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(healthService *service.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
+// Livez reports whether the process is up. It does not check dependencies, so an
+// outage of Postgres or Redis does not get the server restarted.
+// @Summary Liveness probe
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Router /livez [get]
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, &dto.HealthResponse{Status: dto.StatusOK})
+}
+
+// Readyz reports whether the server can take traffic: the database responds, the
+// schema is migrated and Redis (when enabled) responds. It fails while draining.
+// @Summary Readiness probe
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthResponse
+// @Failure 503 {object} dto.HealthResponse
+// @Router /readyz [get]
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	if h.healthService.Draining() {
+		c.JSON(http.StatusServiceUnavailable, &dto.HealthResponse{Status: dto.StatusDraining})
+		return
+	}
+
+	report := h.healthService.Check(c.Request.Context())
+	c.JSON(statusCode(report), &dto.HealthResponse{Status: report.Status})
+}
+
+// Debug runs the readiness checks and reports each dependency with its latency
+// @Summary Dependency health details
+// @Description Admin only
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.HealthResponse
+// @Failure 503 {object} dto.HealthResponse
+// @Router /admin/health [get]
+func (h *HealthHandler) Debug(c *gin.Context) {
+	report := h.healthService.Check(c.Request.Context())
+	c.JSON(statusCode(report), report)
+}
+
+func statusCode(report *dto.HealthResponse) int {
+	if report.Status != dto.StatusOK {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/health/dto"
+	"karigar-backend/pkg/database"
+	"karigar-backend/pkg/redis"
+)
+
+// Check is a named readiness check for one dependency
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// HealthService runs readiness checks and tracks whether the server is draining
+type HealthService struct {
+	checks   []Check
+	timeout  time.Duration
+	redact   bool
+	draining atomic.Bool
+}
+
+// NewHealthService creates a health service running the given checks
+func NewHealthService(cfg *config.Config, checks ...Check) *HealthService {
+	return &HealthService{
+		checks:  checks,
+		timeout: cfg.Health.CheckTimeout,
+		redact:  cfg.Server.Environment == "production",
+	}
+}
+
+// Drain makes readiness fail so load balancers stop sending new requests
+// before the server shuts down
+func (s *HealthService) Drain() {
+	s.draining.Store(true)
+}
+
+// Draining reports whether Drain has been called
+func (s *HealthService) Draining() bool {
+	return s.draining.Load()
+}
+
+// Check runs all checks concurrently, each with its own timeout
+func (s *HealthService) Check(ctx context.Context) *dto.HealthResponse {
+	response := &dto.HealthResponse{
+		Status: dto.StatusOK,
+		Checks: make(map[string]*dto.CheckResult, len(s.checks)),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range s.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			result := s.run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			response.Checks[check.Name] = result
+			if result.Status != dto.StatusOK {
+				response.Status = dto.StatusUnavailable
+			}
+		}(check)
+	}
+	wg.Wait()
+
+	if s.Draining() {
+		response.Status = dto.StatusDraining
+	}
+
+	return response
+}
+
+// run executes one check and measures its latency
+func (s *HealthService) run(ctx context.Context, check Check) *dto.CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Run(ctx)
+	result := &dto.CheckResult{
+		Status:    dto.StatusOK,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		slog.WarnContext(ctx, "health check failed", "check", check.Name, "error", err)
+		result.Status = dto.StatusUnavailable
+		if !s.redact {
+			result.Error = err.Error()
+		}
+	}
+
+	return result
+}
+
+// DatabaseCheck pings the database
+func DatabaseCheck(db *sql.DB) Check {
+	return Check{Name: "database", Run: db.PingContext}
+}
+
+// MigrationCheck verifies the database schema is at the version the code expects
+// (the newest migration file). An empty expected version only checks that the
+// schema version can be read.
+func MigrationCheck(db *sql.DB, expected string) Check {
+	return Check{Name: "migrations", Run: func(ctx context.Context) error {
+		current, err := database.CurrentMigration(ctx, db)
+		if err != nil {
+			return err
+		}
+		if current < expected {
+			return fmt.Errorf("schema is at %q, expected %q", current, expected)
+		}
+		return nil
+	}}
+}
+
+// RedisCheck pings Redis
+func RedisCheck(store redis.Store) Check {
+	return Check{Name: "redis", Run: store.Ping}
+}
@@ -0,0 +1,9 @@
+package dto
+
+// ListJobsQuery filters the job listing. Defaults to the 50 most recently
+// updated jobs of any status and kind.
+type ListJobsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=queued running completed dead"`
+	Kind   string `form:"kind" binding:"omitempty,max=100"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/jobs/dto"
+	"karigar-backend/internal/jobs/service"
+	"karigar-backend/pkg/apperror"
+)
+
+type JobHandler struct {
+	jobService *service.JobService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(jobService *service.JobService) *JobHandler {
+	return &JobHandler{
+		jobService: jobService,
+	}
+}
+
+// ListJobs lists background jobs, most recently updated first
+// @Summary List background jobs
+// @Description Admin only. Dead jobs failed on every attempt (or permanently) and are kept until deleted; completed jobs are pruned after the retention period.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Only jobs with this status" Enums(queued, running, completed, dead)
+// @Param kind query string false "Only jobs of this kind, e.g. exports.build"
+// @Param limit query int false "Maximum number of jobs (default 50, at most 500)"
+// @Success 200 {array} dto.JobResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /admin/jobs [get]
+func (h *JobHandler) ListJobs(c *gin.Context) {
+	var query dto.ListJobsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.jobService.ListJobs(c.Request.Context(), &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
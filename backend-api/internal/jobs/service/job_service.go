@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"karigar-backend/internal/jobs/dto"
+	"karigar-backend/pkg/jobs"
+)
+
+const defaultListLimit = 50
+
+// JobService lets admins inspect the background job queue, e.g. to find dead
+// jobs and why they failed
+type JobService struct {
+	queue *jobs.Queue
+}
+
+// NewJobService creates a new job service
+func NewJobService(queue *jobs.Queue) *JobService {
+	return &JobService{queue: queue}
+}
+
+// ListJobs lists jobs, most recently updated first
+func (s *JobService) ListJobs(ctx context.Context, query *dto.ListJobsQuery) ([]*dto.JobResponse, error) {
+	filter := jobs.ListFilter{
+		Status: jobs.Status(query.Status),
+		Kind:   query.Kind,
+		Limit:  query.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultListLimit
+	}
+
+	list, err := s.queue.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.JobResponse, 0, len(list))
+	for _, job := range list {
+		responses = append(responses, newJobResponse(job))
+	}
+
+	return responses, nil
+}
+
+func newJobResponse(job *jobs.Job) *dto.JobResponse {
+	return &dto.JobResponse{
+		ID:          job.ID,
+		Kind:        job.Kind,
+		Status:      string(job.Status),
+		Payload:     job.Payload,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		RunAt:       job.RunAt,
+		LockedUntil: job.LockedUntil,
+		LastError:   job.LastError,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+		FinishedAt:  job.FinishedAt,
+	}
+}
@@ -1,14 +1,22 @@
 package middleware
 
 import (
-	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"karigar-backend/internal/config"
+	"karigar-backend/pkg/apperror"
 	"karigar-backend/pkg/auth"
 )
 
+var (
+	ErrAuthHeaderRequired      = apperror.New(apperror.Unauthorized, "authorization_required", "authorization header required")
+	ErrInvalidAuthHeader       = apperror.New(apperror.Unauthorized, "invalid_authorization_header", "invalid authorization header format")
+	ErrInvalidAccessToken      = apperror.New(apperror.Unauthorized, "invalid_token", "invalid or expired token")
+	ErrUnauthorized            = apperror.New(apperror.Unauthorized, "unauthorized", "unauthorized")
+	ErrInsufficientPermissions = apperror.New(apperror.Forbidden, "insufficient_permissions", "insufficient permissions")
+)
+
 // AuthMiddleware validates JWT tokens and injects user info into context
 func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	jwtMgr := auth.NewJWTManager(&cfg.JWT)
@@ -16,7 +24,7 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "authorization header required"})
+			c.Error(ErrAuthHeaderRequired)
 			c.Abort()
 			return
 		}
@@ -24,15 +32,15 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		// Extract token from "Bearer <token>"
 		parts := strings.Split(authHeader, " ")
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid authorization header format"})
+			c.Error(ErrInvalidAuthHeader)
 			c.Abort()
 			return
 		}
 
 		token := parts[1]
-		claims, err := jwtMgr.ValidateToken(token)
+		claims, err := jwtMgr.ValidateTokenType(token, auth.TokenTypeAccess)
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
+			c.Error(ErrInvalidAccessToken)
 			c.Abort()
 			return
 		}
@@ -40,7 +48,8 @@ func AuthMiddleware(cfg *config.Config) gin.HandlerFunc {
 		// Store user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("user_email", claims.Email)
-		c.Set("user_role", claims.Role)
+		c.Set("user_role", string(claims.Role))
+		c.Set("session_id", claims.SessionID)
 
 		c.Next()
 	}
@@ -51,13 +60,13 @@ func RequireRole(requiredRole string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("user_role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Error(ErrUnauthorized)
 			c.Abort()
 			return
 		}
 
 		if userRole != requiredRole {
-			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			c.Error(ErrInsufficientPermissions)
 			c.Abort()
 			return
 		}
@@ -71,7 +80,7 @@ func RequireAnyRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("user_role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			c.Error(ErrUnauthorized)
 			c.Abort()
 			return
 		}
@@ -84,7 +93,7 @@ func RequireAnyRole(roles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+		c.Error(ErrInsufficientPermissions)
 		c.Abort()
 	}
 }
@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/config"
+)
+
+// CORSPolicy describes which cross-origin requests are allowed
+type CORSPolicy struct {
+	// AllowedOrigins lists origins such as "https://app.karigar.pk". An entry
+	// like "https://*.karigar.pk" matches any subdomain (but not karigar.pk
+	// itself), and "*" matches every origin.
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string // Response headers scripts may read
+	AllowCredentials bool     // Never sent for origins matched by "*"
+	MaxAge           time.Duration
+}
+
+// CORSRoute overrides the policy for paths starting with PathPrefix
+type CORSRoute struct {
+	PathPrefix string
+	Policy     CORSPolicy
+}
+
+// NewCORSPolicy builds the default policy from configuration
+func NewCORSPolicy(cfg *config.CORSConfig) CORSPolicy {
+	return CORSPolicy{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		ExposedHeaders:   cfg.ExposedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           cfg.MaxAge,
+	}
+}
+
+// CORS applies policy to cross-origin requests, or the policy of the longest
+// matching route override. Preflight requests are answered here (204 when
+// allowed, 403 when not); other requests from disallowed origins are served
+// without CORS headers, so browsers keep the response from the calling script.
+// It must be installed on the engine rather than on a group, so that it also
+// sees preflight requests, which match no route.
+func CORS(policy CORSPolicy, routes ...CORSRoute) gin.HandlerFunc {
+	defaultPolicy := newCORSMatcher(policy)
+	overrides := make([]corsRoute, len(routes))
+	for i, route := range routes {
+		overrides[i] = corsRoute{prefix: route.PathPrefix, policy: newCORSMatcher(route.Policy)}
+	}
+
+	return func(c *gin.Context) {
+		policy := defaultPolicy
+		longest := -1
+		for _, route := range overrides {
+			if strings.HasPrefix(c.Request.URL.Path, route.prefix) && len(route.prefix) > longest {
+				policy = route.policy
+				longest = len(route.prefix)
+			}
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+
+		origin := c.GetHeader("Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		allowOrigin, credentials := policy.allowOrigin(origin)
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+			if allowOrigin == "" ||
+				!policy.methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] ||
+				!policy.allowHeaders(c.GetHeader("Access-Control-Request-Headers")) {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+
+			header.Set("Access-Control-Allow-Origin", allowOrigin)
+			if credentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+			header.Set("Access-Control-Allow-Methods", policy.allowedMethods)
+			if policy.allowedHeaders != "" {
+				header.Set("Access-Control-Allow-Headers", policy.allowedHeaders)
+			}
+			if policy.maxAge != "" {
+				header.Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		if allowOrigin != "" {
+			header.Set("Access-Control-Allow-Origin", allowOrigin)
+			if credentials {
+				header.Set("Access-Control-Allow-Credentials", "true")
+			}
+			if policy.exposedHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", policy.exposedHeaders)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+type corsRoute struct {
+	prefix string
+	policy *corsMatcher
+}
+
+// corsMatcher is a CORSPolicy prepared for matching
+type corsMatcher struct {
+	anyOrigin      bool
+	origins        map[string]bool
+	suffixes       []corsWildcard
+	credentials    bool
+	methods        map[string]bool
+	headers        map[string]bool
+	allowedMethods string
+	allowedHeaders string
+	exposedHeaders string
+	maxAge         string
+}
+
+// corsWildcard matches the subdomains of a "scheme://*.domain" origin
+type corsWildcard struct {
+	scheme string // Including "://"
+	suffix string // Including the leading "."
+}
+
+func newCORSMatcher(policy CORSPolicy) *corsMatcher {
+	m := &corsMatcher{
+		origins:        make(map[string]bool),
+		credentials:    policy.AllowCredentials,
+		methods:        make(map[string]bool),
+		headers:        make(map[string]bool),
+		allowedHeaders: strings.Join(policy.AllowedHeaders, ", "),
+		exposedHeaders: strings.Join(policy.ExposedHeaders, ", "),
+	}
+
+	for _, origin := range policy.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "*":
+			m.anyOrigin = true
+		case strings.Contains(origin, "://*."):
+			scheme, host, _ := strings.Cut(origin, "://*")
+			m.suffixes = append(m.suffixes, corsWildcard{scheme: scheme + "://", suffix: host})
+		case origin != "":
+			m.origins[origin] = true
+		}
+	}
+
+	methods := policy.AllowedMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodHead, http.MethodPost}
+	}
+	normalized := make([]string, len(methods))
+	for i, method := range methods {
+		normalized[i] = strings.ToUpper(strings.TrimSpace(method))
+		m.methods[normalized[i]] = true
+	}
+	m.allowedMethods = strings.Join(normalized, ", ")
+
+	for _, header := range policy.AllowedHeaders {
+		m.headers[strings.ToLower(strings.TrimSpace(header))] = true
+	}
+
+	if policy.MaxAge > 0 {
+		m.maxAge = strconv.Itoa(int(policy.MaxAge.Seconds()))
+	}
+
+	return m
+}
+
+// allowOrigin returns the Access-Control-Allow-Origin value for origin ("" if
+// it is not allowed) and whether credentials may be sent. Listed origins are
+// echoed back; "*" is answered with "*", which browsers never combine with
+// credentials.
+func (m *corsMatcher) allowOrigin(origin string) (string, bool) {
+	normalized := strings.ToLower(origin)
+	if m.origins[normalized] {
+		return origin, m.credentials
+	}
+	for _, wildcard := range m.suffixes {
+		if rest, ok := strings.CutPrefix(normalized, wildcard.scheme); ok &&
+			len(rest) > len(wildcard.suffix) && strings.HasSuffix(rest, wildcard.suffix) {
+			return origin, m.credentials
+		}
+	}
+	if m.anyOrigin {
+		return "*", false
+	}
+	return "", false
+}
+
+// allowHeaders reports whether every header in a preflight's
+// Access-Control-Request-Headers list is allowed
+func (m *corsMatcher) allowHeaders(requested string) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header != "" && !m.headers[header] {
+			return false
+		}
+	}
+	return true
+}
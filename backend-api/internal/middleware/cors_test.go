@@ -0,0 +1,170 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(CORS(
+		CORSPolicy{
+			AllowedOrigins:   []string{"https://app.karigar.pk", "https://*.karigar.dev"},
+			AllowedMethods:   []string{"GET", "POST", "DELETE"},
+			AllowedHeaders:   []string{"Authorization", "Content-Type", "X-CSRF-Token"},
+			ExposedHeaders:   []string{"X-Request-ID", "X-RateLimit-Remaining"},
+			AllowCredentials: true,
+			MaxAge:           10 * time.Minute,
+		},
+		CORSRoute{
+			PathPrefix: "/public",
+			Policy: CORSPolicy{
+				AllowedOrigins:   []string{"*"},
+				AllowedMethods:   []string{"GET"},
+				AllowCredentials: true,
+			},
+		},
+	))
+	router.POST("/bookings", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	router.GET("/public/providers", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	return router
+}
+
+func preflight(router *gin.Engine, path, origin, method, headers string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, path, nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", method)
+	if headers != "" {
+		req.Header.Set("Access-Control-Request-Headers", headers)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSPreflightAllowed(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+	}{
+		{"exact origin", "https://app.karigar.pk"},
+		{"wildcard subdomain", "https://staging.karigar.dev"},
+		{"nested wildcard subdomain", "https://pr-12.preview.karigar.dev"},
+	}
+
+	router := newCORSRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := preflight(router, "/bookings", tt.origin, "POST", "content-type, x-csrf-token")
+
+			if w.Code != http.StatusNoContent {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+			want := map[string]string{
+				"Access-Control-Allow-Origin":      tt.origin,
+				"Access-Control-Allow-Credentials": "true",
+				"Access-Control-Allow-Methods":     "GET, POST, DELETE",
+				"Access-Control-Allow-Headers":     "Authorization, Content-Type, X-CSRF-Token",
+				"Access-Control-Max-Age":           "600",
+			}
+			for header, value := range want {
+				if got := w.Header().Get(header); got != value {
+					t.Errorf("%s = %q, want %q", header, got, value)
+				}
+			}
+			if vary := w.Header().Values("Vary"); len(vary) == 0 || vary[0] != "Origin" {
+				t.Errorf("Vary = %q, want it to start with Origin", vary)
+			}
+		})
+	}
+}
+
+func TestCORSPreflightRejected(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		method  string
+		headers string
+	}{
+		{"unknown origin", "https://evil.example", "POST", ""},
+		{"wildcard does not match apex", "https://karigar.dev", "POST", ""},
+		{"wildcard does not match lookalike", "https://evilkarigar.dev", "POST", ""},
+		{"wildcard scheme must match", "http://staging.karigar.dev", "POST", ""},
+		{"method not allowed", "https://app.karigar.pk", "PATCH", ""},
+		{"header not allowed", "https://app.karigar.pk", "POST", "Content-Type, X-Debug"},
+	}
+
+	router := newCORSRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := preflight(router, "/bookings", tt.origin, tt.method, tt.headers)
+
+			if w.Code != http.StatusForbidden {
+				t.Fatalf("status = %d, want %d", w.Code, http.StatusForbidden)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+			}
+		})
+	}
+}
+
+func TestCORSPreflightRouteOverride(t *testing.T) {
+	router := newCORSRouter()
+
+	w := preflight(router, "/public/providers", "https://anyone.example", "GET", "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want *", got)
+	}
+	// A wildcard origin never allows credentials, even if the policy asks for them
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Access-Control-Allow-Credentials = %q, want none", got)
+	}
+
+	if w := preflight(router, "/public/providers", "https://anyone.example", "POST", ""); w.Code != http.StatusForbidden {
+		t.Errorf("POST preflight status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
+
+func TestCORSActualRequest(t *testing.T) {
+	router := newCORSRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/bookings", nil)
+	req.Header.Set("Origin", "https://app.karigar.pk")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://app.karigar.pk" {
+		t.Errorf("Access-Control-Allow-Origin = %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Expose-Headers"); got != "X-Request-ID, X-RateLimit-Remaining" {
+		t.Errorf("Access-Control-Expose-Headers = %q", got)
+	}
+
+	// Disallowed origins are still served, but without CORS headers
+	req = httptest.NewRequest(http.MethodPost, "/bookings", nil)
+	req.Header.Set("Origin", "https://evil.example")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusCreated)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Access-Control-Allow-Origin = %q, want none", got)
+	}
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/auth"
+	"karigar-backend/pkg/validator"
+)
+
+// ErrorHandler renders the last error attached with c.Error as an RFC 7807
+// problem details response. In production the underlying cause of an error is
+// never sent to the client.
+func ErrorHandler(cfg *config.Config) gin.HandlerFunc {
+	redact := cfg.Server.Environment == "production"
+
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		WriteProblem(c, c.Errors.Last().Err, redact)
+	}
+}
+
+// Recovery turns panics into 500 problem details responses
+func Recovery(cfg *config.Config) gin.HandlerFunc {
+	redact := cfg.Server.Environment == "production"
+
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
+		slog.ErrorContext(c.Request.Context(), "panic recovered", "panic", fmt.Sprint(recovered), "stack", string(debug.Stack()))
+		WriteProblem(c, fmt.Errorf("panic: %v", recovered), redact)
+		c.Abort()
+	})
+}
+
+// WriteProblem maps an error to an application error and writes it as problem details
+func WriteProblem(c *gin.Context, err error, redact bool) {
+	lang := validator.Language(c.GetHeader("Accept-Language"))
+	appErr := toAppError(err, lang)
+	status := appErr.Kind.HTTPStatus()
+
+	if status >= 500 {
+		slog.ErrorContext(c.Request.Context(), "request failed", "error", err)
+	}
+
+	problem := apperror.NewProblem(appErr, c.Request.URL.Path, redact)
+	problem.RequestID = c.GetString("request_id")
+
+	c.Header("Content-Type", apperror.ContentType)
+	if len(appErr.Fields) > 0 {
+		c.Header("Content-Language", lang)
+	}
+	c.JSON(status, problem)
+}
+
+// toAppError maps any error to an application error using errors.Is/As, so
+// wrapped errors are classified by their cause. Request binding errors become
+// field errors in the requested language.
+func toAppError(err error, lang string) *apperror.Error {
+	if fields, ok := validator.Translate(err, lang); ok {
+		return &apperror.Error{
+			Kind:    apperror.Invalid,
+			Code:    "validation_failed",
+			Message: validator.Message(lang, "validation_failed"),
+			Err:     err,
+			Fields:  fields,
+		}
+	}
+
+	// Checked first, as handlers usually wrap body read errors in InvalidRequest
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return ErrRequestTooLarge.WithCause(err)
+	}
+
+	if appErr, ok := apperror.As(err); ok {
+		return appErr
+	}
+
+	switch {
+	case auth.IsPasswordPolicyError(err):
+		// Policy messages are written for end users
+		return apperror.New(apperror.Invalid, "password_policy", err.Error())
+	case errors.Is(err, repository.ErrNotFound):
+		return apperror.Wrap(err, apperror.NotFound, "not_found", "resource not found")
+	case errors.Is(err, repository.ErrConflict):
+		return apperror.Wrap(err, apperror.Conflict, "conflict", "resource already exists")
+	default:
+		return apperror.Wrap(err, apperror.Internal, "internal_error", "an unexpected error occurred")
+	}
+}
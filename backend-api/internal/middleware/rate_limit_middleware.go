@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/redis"
+)
+
+var ErrRateLimited = apperror.New(apperror.TooManyRequests, "rate_limited", "too many requests, try again later")
+
+// RateLimiter counts requests against a limit per window
+type RateLimiter interface {
+	RateLimit(ctx context.Context, key string, limit int, window time.Duration) (redis.RateLimitResult, error)
+}
+
+// RateLimit allows each client IP limit requests per window to the routes it
+// guards, counted under name, and rejects further ones with 429 and
+// Retry-After. Every response carries X-RateLimit-Limit, X-RateLimit-Remaining
+// and X-RateLimit-Reset (Unix seconds). A limit of 0 disables it. When the
+// limiter's store is unreachable, requests are let through rather than failed.
+func RateLimit(name string, limiter RateLimiter, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		result, err := limiter.RateLimit(c.Request.Context(), redis.Key(name, c.ClientIP()), limit, window)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "rate limit unavailable; allowing request", "limit", name, "error", err)
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(result.Reset.Unix(), 10))
+
+		if !result.Allowed {
+			retryAfter := math.Ceil(time.Until(result.Reset).Seconds())
+			header.Set("Retry-After", strconv.Itoa(int(math.Max(retryAfter, 1))))
+			c.Error(ErrRateLimited)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
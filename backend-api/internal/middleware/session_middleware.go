@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/pkg/apperror"
+)
+
+// CSRFHeader is the header in which cookie-authenticated clients echo the CSRF cookie
+const CSRFHeader = "X-CSRF-Token"
+
+var (
+	ErrSessionRequired  = apperror.New(apperror.Unauthorized, "session_required", "session cookie required")
+	ErrInvalidCSRFToken = apperror.New(apperror.Forbidden, "invalid_csrf_token", "missing or invalid CSRF token")
+)
+
+// SessionStore loads sessions for SessionMiddleware
+type SessionStore interface {
+	Touch(ctx context.Context, sessionID string) (*domain.Session, error)
+}
+
+// SessionMiddleware authenticates requests by session cookie and injects the same
+// user info into context as AuthMiddleware (including "session_id", the login
+// session), plus the *domain.Session under "session". Requests with unsafe methods must also pass
+// the double-submit CSRF check: the X-CSRF-Token header has to match both the
+// CSRF cookie and the session's token.
+func SessionMiddleware(cfg *config.Config, sessions SessionStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sessionID, err := c.Cookie(cfg.Session.CookieName)
+		if err != nil || sessionID == "" {
+			c.Error(ErrSessionRequired)
+			c.Abort()
+			return
+		}
+
+		session, err := sessions.Touch(c.Request.Context(), sessionID)
+		if err != nil {
+			c.Error(err)
+			c.Abort()
+			return
+		}
+
+		if !isSafeMethod(c.Request.Method) && !validCSRFToken(c, cfg, session) {
+			c.Error(ErrInvalidCSRFToken)
+			c.Abort()
+			return
+		}
+
+		c.Set("user_id", session.UserID.String())
+		c.Set("user_email", session.Email)
+		c.Set("user_role", string(session.Role))
+		c.Set("session_id", session.UserSessionID.String())
+		c.Set("session", session)
+
+		c.Next()
+	}
+}
+
+// Authenticate accepts either a bearer token (see AuthMiddleware) or a session
+// cookie (see SessionMiddleware). A request carrying an Authorization header is
+// always treated as a bearer request.
+func Authenticate(cfg *config.Config, sessions SessionStore) gin.HandlerFunc {
+	bearer := AuthMiddleware(cfg)
+	session := SessionMiddleware(cfg, sessions)
+
+	return func(c *gin.Context) {
+		if _, err := c.Cookie(cfg.Session.CookieName); err != nil || c.GetHeader("Authorization") != "" {
+			bearer(c)
+			return
+		}
+		session(c)
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+func validCSRFToken(c *gin.Context, cfg *config.Config, session *domain.Session) bool {
+	header := c.GetHeader(CSRFHeader)
+	cookie, err := c.Cookie(cfg.Session.CSRFCookieName)
+	if header == "" || err != nil {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(header), []byte(cookie)) == 1 &&
+		subtle.ConstantTimeCompare([]byte(header), []byte(session.CSRFToken)) == 1
+}
@@ -0,0 +1,58 @@
+package dto
+
+import "karigar-backend/internal/domain"
+
+// SearchProvidersQuery represents the query parameters of a nearby provider search
+type SearchProvidersQuery struct {
+	Lat      *float64               `form:"lat" binding:"required,lat"`
+	Lng      *float64               `form:"lng" binding:"required,lng"`
+	RadiusKm int                    `form:"radius_km" binding:"omitempty,min=1,max=50"` // Defaults to 10
+	Category domain.ServiceCategory `form:"category" binding:"omitempty,service_category"`
+}
+
+// IDParam binds the id path parameter of provider, service and availability routes
+type IDParam struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// UpdateProfileRequest represents the request body for creating or updating the
+// authenticated provider's profile
+type UpdateProfileRequest struct {
+	BusinessName string   `json:"business_name" binding:"required,max=255"`
+	Phone        string   `json:"phone" binding:"omitempty,phone"`
+	Address      string   `json:"address" binding:"omitempty,max=1000"`
+	Latitude     *float64 `json:"latitude" binding:"required,lat"`
+	Longitude    *float64 `json:"longitude" binding:"required,lng"`
+	IsActive     *bool    `json:"is_active"` // Defaults to true
+}
+
+// ServiceRequest represents the request body for creating or updating a service
+type ServiceRequest struct {
+	Category    domain.ServiceCategory `json:"category" binding:"required,service_category"`
+	Name        string                 `json:"name" binding:"required,max=255"`
+	Description string                 `json:"description" binding:"omitempty,max=2000"`
+	Price       *float64               `json:"price" binding:"required,min=0"`
+	Duration    int                    `json:"duration" binding:"required,min=1"` // Minutes
+	IsActive    *bool                  `json:"is_active"`                         // Defaults to true
+}
+
+// AvailabilityRequest represents the request body for adding a weekly availability slot
+type AvailabilityRequest struct {
+	DayOfWeek *int   `json:"day_of_week" binding:"required,min=0,max=6"` // 0=Sunday
+	StartTime string `json:"start_time" binding:"required,hhmm"`
+	EndTime   string `json:"end_time" binding:"required,hhmm"`
+}
+
+// CancellationPolicyRequest represents the request body for replacing the
+// authenticated provider's cancellation policy. An empty list makes
+// cancellation always free.
+type CancellationPolicyRequest struct {
+	Windows []CancellationWindowRequest `json:"windows" binding:"max=10,dive"`
+}
+
+// CancellationWindowRequest charges customers who cancel less than HoursBefore
+// hours before the start FeePercent of the price
+type CancellationWindowRequest struct {
+	HoursBefore int      `json:"hours_before" binding:"required,min=1,max=720"`
+	FeePercent  *float64 `json:"fee_percent" binding:"required,min=0,max=100"`
+}
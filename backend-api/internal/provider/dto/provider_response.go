@@ -0,0 +1,62 @@
+package dto
+
+import "time"
+
+// ProviderResponse describes a service provider's public profile
+type ProviderResponse struct {
+	ID                    string   `json:"id"`
+	BusinessName          string   `json:"business_name"`
+	Phone                 string   `json:"phone,omitempty"`
+	Address               string   `json:"address,omitempty"`
+	Latitude              float64  `json:"latitude"`
+	Longitude             float64  `json:"longitude"`
+	IsVerified            bool     `json:"is_verified"`
+	IsActive              bool     `json:"is_active"`
+	Rating                float64  `json:"rating"`
+	TotalReviews          int      `json:"total_reviews"`
+	ReliabilityScore      float64  `json:"reliability_score"` // Percentage of confirmed bookings the provider neither cancelled nor missed
+	ProviderCancellations int      `json:"provider_cancellations"`
+	DistanceKm            *float64 `json:"distance_km,omitempty"` // Only set in search results
+}
+
+// ProviderDetailResponse describes a provider with its active services and weekly availability
+type ProviderDetailResponse struct {
+	ProviderResponse
+	Services     []*ServiceResponse      `json:"services"`
+	Availability []*AvailabilityResponse `json:"availability"`
+}
+
+// ServiceResponse describes a service offered by a provider
+type ServiceResponse struct {
+	ID          string    `json:"id"`
+	ProviderID  string    `json:"provider_id"`
+	Category    string    `json:"category"`
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Price       float64   `json:"price"`
+	Duration    int       `json:"duration"` // Minutes
+	IsActive    bool      `json:"is_active"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// AvailabilityResponse describes a weekly availability slot
+type AvailabilityResponse struct {
+	ID          string `json:"id"`
+	DayOfWeek   int    `json:"day_of_week"` // 0=Sunday
+	StartTime   string `json:"start_time"`
+	EndTime     string `json:"end_time"`
+	IsAvailable bool   `json:"is_available"`
+}
+
+// CancellationPolicyResponse describes a provider's cancellation windows,
+// narrowest first. Cancelling outside all of them is free.
+type CancellationPolicyResponse struct {
+	Windows []CancellationWindowResponse `json:"windows"`
+}
+
+// CancellationWindowResponse describes the fee for cancelling less than
+// HoursBefore hours before the start
+type CancellationWindowResponse struct {
+	HoursBefore int     `json:"hours_before"`
+	FeePercent  float64 `json:"fee_percent"`
+}
@@ -0,0 +1,402 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"karigar-backend/internal/provider/dto"
+	"karigar-backend/internal/provider/service"
+	"karigar-backend/pkg/apperror"
+)
+
+type ProviderHandler struct {
+	providerService *service.ProviderService
+}
+
+// NewProviderHandler creates a new provider handler
+func NewProviderHandler(providerService *service.ProviderService) *ProviderHandler {
+	return &ProviderHandler{
+		providerService: providerService,
+	}
+}
+
+// Search finds service providers near a location
+// @Summary Search providers
+// @Description Find active providers within radius_km (default 10, at most 50) of a location, nearest first, optionally offering a category
+// @Tags providers
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Param radius_km query int false "Search radius in kilometres"
+// @Param category query string false "Service category"
+// @Success 200 {array} dto.ProviderResponse
+// @Failure 400 {object} apperror.Problem
+// @Router /providers [get]
+func (h *ProviderHandler) Search(c *gin.Context) {
+	var query dto.SearchProvidersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.Search(c.Request.Context(), &query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetProvider returns a provider's profile with its services and availability
+// @Summary Get provider
+// @Tags providers
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Success 200 {object} dto.ProviderDetailResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /providers/{id} [get]
+func (h *ProviderHandler) GetProvider(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.GetProvider(c.Request.Context(), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListServices lists a provider's active services
+// @Summary List provider services
+// @Tags providers
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Success 200 {array} dto.ServiceResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /providers/{id}/services [get]
+func (h *ProviderHandler) ListServices(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.ListServices(c.Request.Context(), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetProfile returns the authenticated provider's profile
+// @Summary Get own provider profile
+// @Tags providers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.ProviderResponse
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider [get]
+func (h *ProviderHandler) GetProfile(c *gin.Context) {
+	response, err := h.providerService.GetProfile(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateProfile creates or updates the authenticated provider's profile
+// @Summary Update own provider profile
+// @Tags providers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UpdateProfileRequest true "Provider profile"
+// @Success 200 {object} dto.ProviderResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Router /me/provider [put]
+func (h *ProviderHandler) UpdateProfile(c *gin.Context) {
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.UpdateProfile(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListOwnServices lists the authenticated provider's services, including inactive ones
+// @Summary List own services
+// @Tags providers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.ServiceResponse
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/services [get]
+func (h *ProviderHandler) ListOwnServices(c *gin.Context) {
+	response, err := h.providerService.ListOwnServices(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateService adds a service to the authenticated provider's offering
+// @Summary Create service
+// @Tags providers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ServiceRequest true "Service"
+// @Success 201 {object} dto.ServiceResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/services [post]
+func (h *ProviderHandler) CreateService(c *gin.Context) {
+	var req dto.ServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.CreateService(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// UpdateService updates one of the authenticated provider's services
+// @Summary Update service
+// @Tags providers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Service ID"
+// @Param request body dto.ServiceRequest true "Service"
+// @Success 200 {object} dto.ServiceResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/services/{id} [put]
+func (h *ProviderHandler) UpdateService(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+	var req dto.ServiceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.UpdateService(c.Request.Context(), c.GetString("user_id"), param.ID, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteService deletes one of the authenticated provider's services
+// @Summary Delete service
+// @Tags providers
+// @Security BearerAuth
+// @Param id path string true "Service ID"
+// @Success 204
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/services/{id} [delete]
+func (h *ProviderHandler) DeleteService(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	if err := h.providerService.DeleteService(c.Request.Context(), c.GetString("user_id"), param.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListAvailability lists the authenticated provider's weekly availability
+// @Summary List own availability
+// @Tags providers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} dto.AvailabilityResponse
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/availability [get]
+func (h *ProviderHandler) ListAvailability(c *gin.Context) {
+	response, err := h.providerService.ListAvailability(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// AddAvailability adds a weekly availability slot for the authenticated provider
+// @Summary Add availability
+// @Tags providers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AvailabilityRequest true "Availability slot"
+// @Success 201 {object} dto.AvailabilityResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Failure 409 {object} apperror.Problem
+// @Router /me/provider/availability [post]
+func (h *ProviderHandler) AddAvailability(c *gin.Context) {
+	var req dto.AvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.AddAvailability(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// DeleteAvailability removes one of the authenticated provider's availability slots
+// @Summary Delete availability
+// @Tags providers
+// @Security BearerAuth
+// @Param id path string true "Availability ID"
+// @Success 204
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/availability/{id} [delete]
+func (h *ProviderHandler) DeleteAvailability(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	if err := h.providerService.DeleteAvailability(c.Request.Context(), c.GetString("user_id"), param.ID); err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCancellationPolicy returns a provider's cancellation policy
+// @Summary Get provider cancellation policy
+// @Description Cancellation windows that apply to bookings made now. A customer cancelling less than hours_before the start pays fee_percent of the price; cancelling outside all windows is free.
+// @Tags providers
+// @Produce json
+// @Param id path string true "Provider ID"
+// @Success 200 {object} dto.CancellationPolicyResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /providers/{id}/cancellation-policy [get]
+func (h *ProviderHandler) GetCancellationPolicy(c *gin.Context) {
+	var param dto.IDParam
+	if err := c.ShouldBindUri(&param); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.GetCancellationPolicy(c.Request.Context(), param.ID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetOwnCancellationPolicy returns the authenticated provider's cancellation policy
+// @Summary Get own cancellation policy
+// @Tags providers
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.CancellationPolicyResponse
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/cancellation-policy [get]
+func (h *ProviderHandler) GetOwnCancellationPolicy(c *gin.Context) {
+	response, err := h.providerService.GetOwnCancellationPolicy(c.Request.Context(), c.GetString("user_id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ReplaceCancellationPolicy replaces the authenticated provider's cancellation policy
+// @Summary Replace own cancellation policy
+// @Description Replace all cancellation windows (at most 10). Existing bookings keep the policy they were made under.
+// @Tags providers
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CancellationPolicyRequest true "Cancellation windows"
+// @Success 200 {object} dto.CancellationPolicyResponse
+// @Failure 400 {object} apperror.Problem
+// @Failure 401 {object} apperror.Problem
+// @Failure 403 {object} apperror.Problem
+// @Failure 404 {object} apperror.Problem
+// @Router /me/provider/cancellation-policy [put]
+func (h *ProviderHandler) ReplaceCancellationPolicy(c *gin.Context) {
+	var req dto.CancellationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperror.InvalidRequest(err))
+		return
+	}
+
+	response, err := h.providerService.ReplaceCancellationPolicy(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,508 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/config"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/provider/dto"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/apperror"
+	"karigar-backend/pkg/geohash"
+	"karigar-backend/pkg/jobs"
+	"karigar-backend/pkg/phone"
+)
+
+var (
+	ErrProviderNotFound        = apperror.New(apperror.NotFound, "provider_not_found", "service provider not found")
+	ErrProfileNotFound         = apperror.New(apperror.NotFound, "provider_profile_not_found", "create your provider profile first")
+	ErrServiceNotFound         = apperror.New(apperror.NotFound, "service_not_found", "service not found")
+	ErrAvailabilityNotFound    = apperror.New(apperror.NotFound, "availability_not_found", "availability slot not found")
+	ErrInvalidTimeRange        = apperror.New(apperror.Invalid, "invalid_time_range", "end time must be after start time")
+	ErrAvailabilityDayConflict = apperror.New(apperror.Conflict, "availability_day_conflict", "an availability slot already exists for this day")
+	ErrDuplicateWindow         = apperror.New(apperror.Invalid, "duplicate_cancellation_window", "each cancellation window must have different hours_before")
+)
+
+// defaultSearchRadiusKm is used when a search does not specify a radius
+const defaultSearchRadiusKm = 10
+
+// refreshStatsJob recomputes every provider's rating and reliability score
+var refreshStatsJob = jobs.NewKind[struct{}]("providers.refresh_stats")
+
+// ProviderService serves provider profiles, services and availability, and
+// lets providers manage their own
+type ProviderService struct {
+	providerRepo     repository.ServiceProviderRepository
+	serviceRepo      repository.ServiceRepository
+	availabilityRepo repository.AvailabilityRepository
+	policyRepo       repository.CancellationPolicyRepository
+	countryCode      string // Default country code of national phone numbers
+	refreshSchedule  string // Cron schedule of RefreshStats
+}
+
+// NewProviderService creates a new provider service
+func NewProviderService(providerRepo repository.ServiceProviderRepository, serviceRepo repository.ServiceRepository, availabilityRepo repository.AvailabilityRepository, policyRepo repository.CancellationPolicyRepository, cfg *config.Config) *ProviderService {
+	return &ProviderService{
+		providerRepo:     providerRepo,
+		serviceRepo:      serviceRepo,
+		availabilityRepo: availabilityRepo,
+		policyRepo:       policyRepo,
+		countryCode:      cfg.OTP.DefaultCountryCode,
+		refreshSchedule:  cfg.Jobs.RatingRefreshSchedule,
+	}
+}
+
+// Search finds active providers near a point, nearest first
+func (s *ProviderService) Search(ctx context.Context, query *dto.SearchProvidersQuery) ([]*dto.ProviderResponse, error) {
+	radiusKm := query.RadiusKm
+	if radiusKm == 0 {
+		radiusKm = defaultSearchRadiusKm
+	}
+	var category *domain.ServiceCategory
+	if query.Category != "" {
+		category = &query.Category
+	}
+
+	providers, err := s.providerRepo.Search(ctx, *query.Lat, *query.Lng, float64(radiusKm), category)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ProviderResponse, 0, len(providers))
+	for _, provider := range providers {
+		response := newProviderResponse(provider)
+		distance := geohash.Distance(*query.Lat, *query.Lng, provider.Latitude, provider.Longitude)
+		response.DistanceKm = &distance
+		responses = append(responses, response)
+	}
+
+	return responses, nil
+}
+
+// GetProvider returns an active provider's profile with its active services and availability
+func (s *ProviderService) GetProvider(ctx context.Context, providerID string) (*dto.ProviderDetailResponse, error) {
+	provider, err := s.getActiveProvider(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	services, err := s.listServices(ctx, providerID, true)
+	if err != nil {
+		return nil, err
+	}
+	slots, err := s.availabilityRepo.GetByProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	availability := make([]*dto.AvailabilityResponse, 0, len(slots))
+	for _, slot := range slots {
+		if slot.IsAvailable {
+			availability = append(availability, newAvailabilityResponse(slot))
+		}
+	}
+
+	return &dto.ProviderDetailResponse{
+		ProviderResponse: *newProviderResponse(provider),
+		Services:         services,
+		Availability:     availability,
+	}, nil
+}
+
+// ListServices lists an active provider's active services
+func (s *ProviderService) ListServices(ctx context.Context, providerID string) ([]*dto.ServiceResponse, error) {
+	if _, err := s.getActiveProvider(ctx, providerID); err != nil {
+		return nil, err
+	}
+
+	return s.listServices(ctx, providerID, true)
+}
+
+// GetProfile returns the authenticated provider's own profile
+func (s *ProviderService) GetProfile(ctx context.Context, userID string) (*dto.ProviderResponse, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return newProviderResponse(provider), nil
+}
+
+// UpdateProfile creates the authenticated provider's profile, or updates it
+func (s *ProviderService) UpdateProfile(ctx context.Context, userID string, req *dto.UpdateProfileRequest) (*dto.ProviderResponse, error) {
+	phoneNumber := req.Phone
+	if phoneNumber != "" {
+		var err error
+		if phoneNumber, err = phone.NormalizeE164(phoneNumber, s.countryCode); err != nil {
+			return nil, apperror.Wrap(err, apperror.Invalid, "invalid_phone", "phone number is invalid")
+		}
+	}
+
+	provider, err := s.providerRepo.GetByUserID(ctx, userID)
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	create := provider == nil
+	if create {
+		ownerID, err := uuid.Parse(userID)
+		if err != nil {
+			return nil, err
+		}
+		provider = &domain.ServiceProvider{ID: uuid.New(), UserID: ownerID}
+	}
+
+	provider.BusinessName = req.BusinessName
+	provider.Phone = phoneNumber
+	provider.Address = req.Address
+	provider.Latitude = *req.Latitude
+	provider.Longitude = *req.Longitude
+	provider.IsActive = req.IsActive == nil || *req.IsActive
+
+	if create {
+		err = s.providerRepo.Create(ctx, provider)
+	} else {
+		err = s.providerRepo.Update(ctx, provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newProviderResponse(provider), nil
+}
+
+// ListOwnServices lists all of the authenticated provider's services, including inactive ones
+func (s *ProviderService) ListOwnServices(ctx context.Context, userID string) ([]*dto.ServiceResponse, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.listServices(ctx, provider.ID.String(), false)
+}
+
+// CreateService adds a service to the authenticated provider's offering
+func (s *ProviderService) CreateService(ctx context.Context, userID string, req *dto.ServiceRequest) (*dto.ServiceResponse, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	service := &domain.Service{ID: uuid.New(), ProviderID: provider.ID}
+	applyServiceRequest(service, req)
+	if err := s.serviceRepo.Create(ctx, service); err != nil {
+		return nil, err
+	}
+
+	return newServiceResponse(service), nil
+}
+
+// UpdateService updates one of the authenticated provider's services
+func (s *ProviderService) UpdateService(ctx context.Context, userID, serviceID string, req *dto.ServiceRequest) (*dto.ServiceResponse, error) {
+	service, err := s.getOwnService(ctx, userID, serviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	applyServiceRequest(service, req)
+	if err := s.serviceRepo.Update(ctx, service); err != nil {
+		return nil, err
+	}
+
+	return newServiceResponse(service), nil
+}
+
+// DeleteService deletes one of the authenticated provider's services. Bookings
+// of the service are kept (see migration 011).
+func (s *ProviderService) DeleteService(ctx context.Context, userID, serviceID string) error {
+	if _, err := s.getOwnService(ctx, userID, serviceID); err != nil {
+		return err
+	}
+
+	return s.serviceRepo.Delete(ctx, serviceID)
+}
+
+// ListAvailability lists the authenticated provider's weekly availability
+func (s *ProviderService) ListAvailability(ctx context.Context, userID string) ([]*dto.AvailabilityResponse, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slots, err := s.availabilityRepo.GetByProviderID(ctx, provider.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.AvailabilityResponse, 0, len(slots))
+	for _, slot := range slots {
+		responses = append(responses, newAvailabilityResponse(slot))
+	}
+
+	return responses, nil
+}
+
+// AddAvailability adds a weekly availability slot. A provider has at most one slot per day.
+func (s *ProviderService) AddAvailability(ctx context.Context, userID string, req *dto.AvailabilityRequest) (*dto.AvailabilityResponse, error) {
+	// HH:MM strings compare in time order
+	if req.EndTime <= req.StartTime {
+		return nil, ErrInvalidTimeRange
+	}
+
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	slot := &domain.Availability{
+		ID:          uuid.New(),
+		ProviderID:  provider.ID,
+		DayOfWeek:   domain.DayOfWeek(*req.DayOfWeek),
+		StartTime:   req.StartTime,
+		EndTime:     req.EndTime,
+		IsAvailable: true,
+	}
+	if err := s.availabilityRepo.Create(ctx, slot); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return nil, ErrAvailabilityDayConflict
+		}
+		return nil, err
+	}
+
+	return newAvailabilityResponse(slot), nil
+}
+
+// DeleteAvailability removes one of the authenticated provider's availability slots
+func (s *ProviderService) DeleteAvailability(ctx context.Context, userID, availabilityID string) error {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	slot, err := s.availabilityRepo.GetByID(ctx, availabilityID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrAvailabilityNotFound
+		}
+		return err
+	}
+	if slot.ProviderID != provider.ID {
+		return ErrAvailabilityNotFound
+	}
+
+	return s.availabilityRepo.Delete(ctx, availabilityID)
+}
+
+// GetCancellationPolicy returns an active provider's cancellation policy, which
+// applies to bookings made now
+func (s *ProviderService) GetCancellationPolicy(ctx context.Context, providerID string) (*dto.CancellationPolicyResponse, error) {
+	if _, err := s.getActiveProvider(ctx, providerID); err != nil {
+		return nil, err
+	}
+
+	return s.cancellationPolicy(ctx, providerID)
+}
+
+// GetOwnCancellationPolicy returns the authenticated provider's cancellation policy
+func (s *ProviderService) GetOwnCancellationPolicy(ctx context.Context, userID string) (*dto.CancellationPolicyResponse, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.cancellationPolicy(ctx, provider.ID.String())
+}
+
+// ReplaceCancellationPolicy replaces the authenticated provider's cancellation
+// policy. Existing bookings keep the policy they were made under.
+func (s *ProviderService) ReplaceCancellationPolicy(ctx context.Context, userID string, req *dto.CancellationPolicyRequest) (*dto.CancellationPolicyResponse, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy := make(domain.CancellationPolicy, 0, len(req.Windows))
+	seen := make(map[int]bool, len(req.Windows))
+	for _, window := range req.Windows {
+		if seen[window.HoursBefore] {
+			return nil, ErrDuplicateWindow
+		}
+		seen[window.HoursBefore] = true
+		policy = append(policy, domain.CancellationWindow{
+			ProviderID:  provider.ID,
+			HoursBefore: window.HoursBefore,
+			FeePercent:  *window.FeePercent,
+		})
+	}
+
+	if err := s.policyRepo.Replace(ctx, provider.ID.String(), policy); err != nil {
+		return nil, err
+	}
+
+	return s.cancellationPolicy(ctx, provider.ID.String())
+}
+
+// RefreshStats recomputes every provider's rating, review count and
+// reliability score. They are kept up to date as reviews and bookings change;
+// this repairs any that have drifted, e.g. after data was fixed by hand. It
+// returns the number of providers corrected.
+func (s *ProviderService) RefreshStats(ctx context.Context) (int, error) {
+	ids, err := s.providerRepo.RefreshStats(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ids), nil
+}
+
+// RegisterJobs schedules RefreshStats on queue
+func (s *ProviderService) RegisterJobs(queue *jobs.Queue) error {
+	schedule, err := jobs.ParseSchedule(s.refreshSchedule)
+	if err != nil {
+		return fmt.Errorf("invalid rating refresh schedule: %w", err)
+	}
+
+	jobs.Handle(queue, refreshStatsJob, func(ctx context.Context, _ struct{}) error {
+		corrected, err := s.RefreshStats(ctx)
+		if corrected > 0 {
+			slog.InfoContext(ctx, "corrected provider ratings", "count", corrected)
+		}
+		return err
+	})
+	return jobs.Repeat(queue, refreshStatsJob, schedule, struct{}{})
+}
+
+func (s *ProviderService) cancellationPolicy(ctx context.Context, providerID string) (*dto.CancellationPolicyResponse, error) {
+	policy, err := s.policyRepo.GetByProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.CancellationPolicyResponse{Windows: make([]dto.CancellationWindowResponse, 0, len(policy))}
+	for _, window := range policy {
+		response.Windows = append(response.Windows, dto.CancellationWindowResponse{
+			HoursBefore: window.HoursBefore,
+			FeePercent:  window.FeePercent,
+		})
+	}
+
+	return response, nil
+}
+
+func (s *ProviderService) getActiveProvider(ctx context.Context, providerID string) (*domain.ServiceProvider, error) {
+	provider, err := s.providerRepo.GetByID(ctx, providerID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProviderNotFound
+		}
+		return nil, err
+	}
+	if !provider.IsActive {
+		return nil, ErrProviderNotFound
+	}
+
+	return provider, nil
+}
+
+func (s *ProviderService) getOwnProvider(ctx context.Context, userID string) (*domain.ServiceProvider, error) {
+	provider, err := s.providerRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrProfileNotFound
+		}
+		return nil, err
+	}
+
+	return provider, nil
+}
+
+func (s *ProviderService) getOwnService(ctx context.Context, userID, serviceID string) (*domain.Service, error) {
+	provider, err := s.getOwnProvider(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	service, err := s.serviceRepo.GetByID(ctx, serviceID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, err
+	}
+	if service.ProviderID != provider.ID {
+		return nil, ErrServiceNotFound
+	}
+
+	return service, nil
+}
+
+func (s *ProviderService) listServices(ctx context.Context, providerID string, activeOnly bool) ([]*dto.ServiceResponse, error) {
+	services, err := s.serviceRepo.GetByProviderID(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]*dto.ServiceResponse, 0, len(services))
+	for _, service := range services {
+		if activeOnly && !service.IsActive {
+			continue
+		}
+		responses = append(responses, newServiceResponse(service))
+	}
+
+	return responses, nil
+}
+
+func applyServiceRequest(service *domain.Service, req *dto.ServiceRequest) {
+	service.Category = req.Category
+	service.Name = req.Name
+	service.Description = req.Description
+	service.Price = *req.Price
+	service.Duration = req.Duration
+	service.IsActive = req.IsActive == nil || *req.IsActive
+}
+
+func newProviderResponse(provider *domain.ServiceProvider) *dto.ProviderResponse {
+	return &dto.ProviderResponse{
+		ID:                    provider.ID.String(),
+		BusinessName:          provider.BusinessName,
+		Phone:                 provider.Phone,
+		Address:               provider.Address,
+		Latitude:              provider.Latitude,
+		Longitude:             provider.Longitude,
+		IsVerified:            provider.IsVerified,
+		IsActive:              provider.IsActive,
+		Rating:                provider.Rating,
+		TotalReviews:          provider.TotalReviews,
+		ReliabilityScore:      provider.ReliabilityScore,
+		ProviderCancellations: provider.ProviderCancellations,
+	}
+}
+
+func newServiceResponse(service *domain.Service) *dto.ServiceResponse {
+	return &dto.ServiceResponse{
+		ID:          service.ID.String(),
+		ProviderID:  service.ProviderID.String(),
+		Category:    string(service.Category),
+		Name:        service.Name,
+		Description: service.Description,
+		Price:       service.Price,
+		Duration:    service.Duration,
+		IsActive:    service.IsActive,
+		UpdatedAt:   service.UpdatedAt,
+	}
+}
+
+func newAvailabilityResponse(slot *domain.Availability) *dto.AvailabilityResponse {
+	return &dto.AvailabilityResponse{
+		ID:          slot.ID.String(),
+		DayOfWeek:   int(slot.DayOfWeek),
+		StartTime:   slot.StartTime,
+		EndTime:     slot.EndTime,
+		IsAvailable: slot.IsAvailable,
+	}
+}
@@ -0,0 +1,59 @@
+package cached
+
+import (
+	"context"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+type availabilityRepository struct {
+	repository.AvailabilityRepository
+	cache *Cache
+}
+
+// NewAvailabilityRepository caches the weekly schedule of each provider
+func NewAvailabilityRepository(next repository.AvailabilityRepository, cache *Cache) repository.AvailabilityRepository {
+	return &availabilityRepository{
+		AvailabilityRepository: next,
+		cache:                  cache,
+	}
+}
+
+func (r *availabilityRepository) GetByProviderID(ctx context.Context, providerID string) ([]*domain.Availability, error) {
+	return readThrough(ctx, r.cache, "availability", providerAvailabilityKey(providerID), r.cache.cfg.ProviderTTL, nil,
+		func(ctx context.Context) ([]*domain.Availability, error) {
+			return r.AvailabilityRepository.GetByProviderID(ctx, providerID)
+		})
+}
+
+func (r *availabilityRepository) Create(ctx context.Context, availability *domain.Availability) error {
+	if err := r.AvailabilityRepository.Create(ctx, availability); err != nil {
+		return err
+	}
+
+	r.cache.invalidate(ctx, providerAvailabilityKey(availability.ProviderID.String()))
+	return nil
+}
+
+func (r *availabilityRepository) Update(ctx context.Context, availability *domain.Availability) error {
+	if err := r.AvailabilityRepository.Update(ctx, availability); err != nil {
+		return err
+	}
+
+	r.cache.invalidate(ctx, providerAvailabilityKey(availability.ProviderID.String()))
+	return nil
+}
+
+func (r *availabilityRepository) Delete(ctx context.Context, id string) error {
+	availability, err := r.AvailabilityRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.AvailabilityRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	r.cache.invalidate(ctx, providerAvailabilityKey(availability.ProviderID.String()))
+	return nil
+}
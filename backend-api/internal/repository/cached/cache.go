@@ -0,0 +1,155 @@
+// Package cached decorates repositories with a read-through cache on top of
+// redis.CacheService. Reads are served from the cache when possible; writes go
+// to the wrapped repository and then invalidate the entries they affect.
+//
+// Invalidation is best effort: an entry filled by a reader that loaded just
+// before a write may survive until its TTL, so TTLs bound staleness.
+package cached
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"karigar-backend/internal/config"
+	"karigar-backend/pkg/metrics"
+	"karigar-backend/pkg/redis"
+)
+
+// lockPollInterval is how often a request waiting for another to rebuild an
+// entry checks the cache
+const lockPollInterval = 25 * time.Millisecond
+
+// searchTag groups all cached search results, so they can be invalidated together
+const searchTag = "search"
+
+// Cache is the cache shared by the cached repositories
+type Cache struct {
+	store redis.Store
+	json  *redis.CacheService
+	cfg   config.CacheConfig
+}
+
+// NewCache creates a cache on top of store
+func NewCache(store redis.Store, cfg *config.Config) *Cache {
+	return &Cache{
+		store: store,
+		json:  redis.NewCacheService(store),
+		cfg:   cfg.Cache,
+	}
+}
+
+// readThrough returns the value cached under key, or loads it and caches it for
+// ttl under the given tags. Only one request per key loads at a time (a SetNX
+// lock); the others wait briefly for it to fill the cache before falling back
+// to loading themselves. When the cache is unavailable, values are loaded
+// without caching.
+func readThrough[T any](ctx context.Context, c *Cache, name, key string, ttl time.Duration, tags []string, load func(context.Context) (T, error)) (T, error) {
+	var value T
+	err := c.json.GetJSON(ctx, key, &value)
+	if err == nil {
+		metrics.CacheRequests.WithLabelValues(name, metrics.CacheHit).Inc()
+		return value, nil
+	}
+	if !errors.Is(err, redis.Nil) {
+		return loadUncached(ctx, name, key, err, load)
+	}
+
+	lock, err := redis.AcquireLock(ctx, c.store, redis.Key("cache", key), c.cfg.LockTTL)
+	if errors.Is(err, redis.ErrLockHeld) {
+		if c.waitFor(ctx, key, &value) {
+			metrics.CacheRequests.WithLabelValues(name, metrics.CacheHit).Inc()
+			return value, nil
+		}
+		// The rebuild is taking too long; load without caching rather than
+		// make the request wait any longer
+		metrics.CacheRequests.WithLabelValues(name, metrics.CacheMiss).Inc()
+		return load(ctx)
+	}
+	if err != nil {
+		return loadUncached(ctx, name, key, err, load)
+	}
+	defer func() {
+		if err := lock.Release(context.WithoutCancel(ctx)); err != nil {
+			slog.WarnContext(ctx, "failed to release cache lock", "key", key, "error", err)
+		}
+	}()
+
+	// Another request may have filled the entry between our read and the lock
+	if err := c.json.GetJSON(ctx, key, &value); err == nil {
+		metrics.CacheRequests.WithLabelValues(name, metrics.CacheHit).Inc()
+		return value, nil
+	}
+
+	metrics.CacheRequests.WithLabelValues(name, metrics.CacheMiss).Inc()
+	value, err = load(ctx)
+	if err != nil {
+		return value, err
+	}
+	if err := c.json.SetJSON(ctx, key, value, ttl, tags...); err != nil {
+		slog.WarnContext(ctx, "failed to cache value", "key", key, "error", err)
+	}
+
+	return value, nil
+}
+
+// loadUncached loads a value when the cache could not be used
+func loadUncached[T any](ctx context.Context, name, key string, cacheErr error, load func(context.Context) (T, error)) (T, error) {
+	metrics.CacheRequests.WithLabelValues(name, metrics.CacheError).Inc()
+	slog.DebugContext(ctx, "cache unavailable, loading from the database", "key", key, "error", cacheErr)
+	return load(ctx)
+}
+
+// waitFor polls the cache for key until it is filled or the lock wait elapses
+func (c *Cache) waitFor(ctx context.Context, key string, dest interface{}) bool {
+	deadline := time.NewTimer(c.cfg.LockWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(lockPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-deadline.C:
+			return false
+		case <-ticker.C:
+			if err := c.json.GetJSON(ctx, key, dest); err == nil {
+				return true
+			}
+		}
+	}
+}
+
+// invalidate deletes cache entries. Failures are logged rather than returned:
+// the write they follow has already succeeded, and the entries expire anyway.
+func (c *Cache) invalidate(ctx context.Context, keys ...string) {
+	if err := c.store.Delete(ctx, keys...); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cache entries", "keys", keys, "error", err)
+	}
+}
+
+// invalidateSearches deletes all cached search results
+func (c *Cache) invalidateSearches(ctx context.Context) {
+	if err := c.json.InvalidateTags(ctx, searchTag); err != nil {
+		slog.WarnContext(ctx, "failed to invalidate cached searches", "error", err)
+	}
+}
+
+func providerKey(providerID string) string {
+	return redis.Key("provider", providerID)
+}
+
+func providerServicesKey(providerID string) string {
+	return redis.Key("provider", providerID, "services")
+}
+
+func providerAvailabilityKey(providerID string) string {
+	return redis.Key("provider", providerID, "availability")
+}
+
+func searchKey(cell, category string, radiusKm float64) string {
+	return redis.Key("search", cell, category, strconv.FormatFloat(radiusKm, 'f', -1, 64))
+}
@@ -0,0 +1,53 @@
+package cached
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/metrics"
+)
+
+type reviewRepository struct {
+	repository.ReviewRepository
+	cache *Cache
+}
+
+// NewReviewRepository invalidates cached provider data when reviews change a
+// provider's rating, and counts submitted reviews. Reviews themselves are not cached.
+func NewReviewRepository(next repository.ReviewRepository, cache *Cache) repository.ReviewRepository {
+	return &reviewRepository{
+		ReviewRepository: next,
+		cache:            cache,
+	}
+}
+
+func (r *reviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	if err := r.ReviewRepository.Create(ctx, review); err != nil {
+		return err
+	}
+	metrics.ObserveReview(review.Rating)
+
+	r.invalidate(ctx, review.ProviderID)
+	return nil
+}
+
+func (r *reviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	if err := r.ReviewRepository.Update(ctx, review); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, review.ProviderID)
+	return nil
+}
+
+// invalidate drops the provider's profile and all searches, which include its rating
+func (r *reviewRepository) invalidate(ctx context.Context, providerID uuid.UUID) {
+	if providerID == uuid.Nil {
+		return
+	}
+
+	r.cache.invalidate(ctx, providerKey(providerID.String()))
+	r.cache.invalidateSearches(ctx)
+}
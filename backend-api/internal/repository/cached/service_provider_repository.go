@@ -0,0 +1,112 @@
+package cached
+
+import (
+	"context"
+	"sort"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+	"karigar-backend/pkg/geohash"
+)
+
+type serviceProviderRepository struct {
+	repository.ServiceProviderRepository
+	cache *Cache
+}
+
+// NewServiceProviderRepository caches provider profiles by id and search results
+// by geohash cell, category and radius
+func NewServiceProviderRepository(next repository.ServiceProviderRepository, cache *Cache) repository.ServiceProviderRepository {
+	return &serviceProviderRepository{
+		ServiceProviderRepository: next,
+		cache:                     cache,
+	}
+}
+
+func (r *serviceProviderRepository) GetByID(ctx context.Context, id string) (*domain.ServiceProvider, error) {
+	return readThrough(ctx, r.cache, "provider", providerKey(id), r.cache.cfg.ProviderTTL, nil,
+		func(ctx context.Context) (*domain.ServiceProvider, error) {
+			return r.ServiceProviderRepository.GetByID(ctx, id)
+		})
+}
+
+func (r *serviceProviderRepository) Create(ctx context.Context, provider *domain.ServiceProvider) error {
+	if err := r.ServiceProviderRepository.Create(ctx, provider); err != nil {
+		return err
+	}
+
+	r.cache.invalidateSearches(ctx)
+	return nil
+}
+
+func (r *serviceProviderRepository) Update(ctx context.Context, provider *domain.ServiceProvider) error {
+	if err := r.ServiceProviderRepository.Update(ctx, provider); err != nil {
+		return err
+	}
+
+	r.cache.invalidate(ctx, providerKey(provider.ID.String()))
+	r.cache.invalidateSearches(ctx)
+	return nil
+}
+
+func (r *serviceProviderRepository) RefreshStats(ctx context.Context) ([]string, error) {
+	ids, err := r.ServiceProviderRepository.RefreshStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		r.cache.invalidate(ctx, providerKey(id))
+	}
+	if len(ids) > 0 {
+		r.cache.invalidateSearches(ctx)
+	}
+	return ids, nil
+}
+
+// Search serves nearby searches from results cached per geohash cell, so that
+// all searches starting in the same cell share one entry. The cached results
+// cover the whole cell (the search around the cell centre is widened by the
+// cell's radius); they are then narrowed to the requested point and radius.
+// Where the widened search hit the result limit, providers near the requested
+// point may have been cut off, so the search goes to the database instead.
+func (r *serviceProviderRepository) Search(ctx context.Context, lat, lng float64, radiusKm float64, category *domain.ServiceCategory) ([]*domain.ServiceProvider, error) {
+	cell := geohash.Encode(lat, lng, r.cache.cfg.GeohashPrecision)
+	categoryKey := "all"
+	if category != nil {
+		categoryKey = string(*category)
+	}
+
+	providers, err := readThrough(ctx, r.cache, "search", searchKey(cell, categoryKey, radiusKm), r.cache.cfg.SearchTTL, []string{searchTag},
+		func(ctx context.Context) ([]*domain.ServiceProvider, error) {
+			bounds := geohash.Decode(cell)
+			centerLat, centerLng := bounds.Center()
+			return r.ServiceProviderRepository.Search(ctx, centerLat, centerLng, radiusKm+bounds.RadiusKm(), category)
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(providers) >= repository.SearchLimit {
+		return r.ServiceProviderRepository.Search(ctx, lat, lng, radiusKm, category)
+	}
+
+	return nearest(providers, lat, lng, radiusKm), nil
+}
+
+// nearest keeps the providers within radiusKm of a point, nearest first
+func nearest(providers []*domain.ServiceProvider, lat, lng, radiusKm float64) []*domain.ServiceProvider {
+	distances := make(map[*domain.ServiceProvider]float64, len(providers))
+	result := make([]*domain.ServiceProvider, 0, len(providers))
+	for _, provider := range providers {
+		distance := geohash.Distance(lat, lng, provider.Latitude, provider.Longitude)
+		if distance <= radiusKm {
+			distances[provider] = distance
+			result = append(result, provider)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return distances[result[i]] < distances[result[j]]
+	})
+	return result
+}
@@ -0,0 +1,66 @@
+package cached
+
+import (
+	"context"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+type serviceRepository struct {
+	repository.ServiceRepository
+	cache *Cache
+}
+
+// NewServiceRepository caches the list of services of each provider
+func NewServiceRepository(next repository.ServiceRepository, cache *Cache) repository.ServiceRepository {
+	return &serviceRepository{
+		ServiceRepository: next,
+		cache:             cache,
+	}
+}
+
+func (r *serviceRepository) GetByProviderID(ctx context.Context, providerID string) ([]*domain.Service, error) {
+	return readThrough(ctx, r.cache, "services", providerServicesKey(providerID), r.cache.cfg.ProviderTTL, nil,
+		func(ctx context.Context) ([]*domain.Service, error) {
+			return r.ServiceRepository.GetByProviderID(ctx, providerID)
+		})
+}
+
+func (r *serviceRepository) Create(ctx context.Context, service *domain.Service) error {
+	if err := r.ServiceRepository.Create(ctx, service); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, service)
+	return nil
+}
+
+func (r *serviceRepository) Update(ctx context.Context, service *domain.Service) error {
+	if err := r.ServiceRepository.Update(ctx, service); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, service)
+	return nil
+}
+
+func (r *serviceRepository) Delete(ctx context.Context, id string) error {
+	service, err := r.ServiceRepository.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.ServiceRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, service)
+	return nil
+}
+
+// invalidate drops the provider's service list and all searches, since category
+// searches depend on which services a provider offers
+func (r *serviceRepository) invalidate(ctx context.Context, service *domain.Service) {
+	r.cache.invalidate(ctx, providerServicesKey(service.ProviderID.String()))
+	r.cache.invalidateSearches(ctx)
+}
@@ -0,0 +1,55 @@
+package cached
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+type serviceRequestRepository struct {
+	repository.ServiceRequestRepository
+	cache *Cache
+}
+
+// NewServiceRequestRepository invalidates cached provider data when bookings
+// change a provider's reliability score. Bookings themselves are not cached.
+func NewServiceRequestRepository(next repository.ServiceRequestRepository, cache *Cache) repository.ServiceRequestRepository {
+	return &serviceRequestRepository{
+		ServiceRequestRepository: next,
+		cache:                    cache,
+	}
+}
+
+func (r *serviceRequestRepository) UpdateStatus(ctx context.Context, id string, from, to domain.RequestStatus, event *domain.ServiceRequestEvent) error {
+	if err := r.ServiceRequestRepository.UpdateStatus(ctx, id, from, to, event); err != nil {
+		return err
+	}
+
+	if to == domain.StatusCompleted {
+		if request, err := r.ServiceRequestRepository.GetByID(ctx, id); err == nil {
+			r.invalidate(ctx, request.ProviderID)
+		}
+	}
+	return nil
+}
+
+func (r *serviceRequestRepository) Cancel(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error {
+	if err := r.ServiceRequestRepository.Cancel(ctx, request, event); err != nil {
+		return err
+	}
+
+	r.invalidate(ctx, request.ProviderID)
+	return nil
+}
+
+// invalidate drops the provider's profile and all searches, which include its reliability score
+func (r *serviceRequestRepository) invalidate(ctx context.Context, providerID uuid.UUID) {
+	if providerID == uuid.Nil {
+		return
+	}
+
+	r.cache.invalidate(ctx, providerKey(providerID.String()))
+	r.cache.invalidateSearches(ctx)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "errors"
+
+// Errors returned by repository implementations. Implementations wrap them with
+// more specific messages, so compare with errors.Is.
+var (
+	ErrNotFound = errors.New("not found")
+	ErrConflict = errors.New("conflict")
+	// ErrOverlapping is returned when a booking would overlap another of the provider's
+	ErrOverlapping = errors.New("overlapping booking")
+)
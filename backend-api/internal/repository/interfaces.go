@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"karigar-backend/internal/domain"
 )
@@ -11,12 +12,86 @@ type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	GetByID(ctx context.Context, id string) (*domain.User, error)
 	GetByEmail(ctx context.Context, email string) (*domain.User, error)
+	GetByVerifiedPhone(ctx context.Context, phone string) (*domain.User, error)
 	Update(ctx context.Context, user *domain.User) error
+	SetVerifiedPhone(ctx context.Context, userID, phone string) error
+	UpdateMFA(ctx context.Context, user *domain.User) error
+	RecordMFAStep(ctx context.Context, userID string, step int64) (bool, error)
+	ScheduleDeletion(ctx context.Context, id string, requestedAt, scheduledAt time.Time) error
+	CancelDeletion(ctx context.Context, id string) error
+	ListDueForDeletion(ctx context.Context, before time.Time, limit int) ([]*domain.User, error)
 	Delete(ctx context.Context, id string) error
 	GetByEmailVerifyToken(ctx context.Context, token string) (*domain.User, error)
 	GetByPasswordResetToken(ctx context.Context, token string) (*domain.User, error)
 }
 
+// UserSessionRepository defines the interface for login sessions
+type UserSessionRepository interface {
+	Create(ctx context.Context, session *domain.UserSession) error
+	GetByID(ctx context.Context, id string) (*domain.UserSession, error)
+	ListActiveByUserID(ctx context.Context, userID string) ([]*domain.UserSession, error)
+	// CountByUserAgent counts all of a user's sessions, active or not, and those made with userAgent
+	CountByUserAgent(ctx context.Context, userID, userAgent string) (total, matching int, err error)
+	Update(ctx context.Context, session *domain.UserSession) error
+	// Rotate saves a session's next refresh token only if its current one is still
+	// previousRefreshTokenID and it is not revoked; otherwise it returns ErrConflict
+	Rotate(ctx context.Context, session *domain.UserSession, previousRefreshTokenID string) error
+	Revoke(ctx context.Context, userID, id string) error
+	// RevokeAllExcept revokes all of a user's active sessions but exceptID (which may be empty)
+	RevokeAllExcept(ctx context.Context, userID, exceptID string) (int64, error)
+}
+
+// AccountRepository defines account-wide operations that span several tables
+type AccountRepository interface {
+	// AnonymizeAndDelete detaches and scrubs a user's bookings and reviews, then deletes the user
+	AnonymizeAndDelete(ctx context.Context, userID string) error
+}
+
+// AuditRepository defines the interface for audit event data operations
+type AuditRepository interface {
+	Create(ctx context.Context, event *domain.AuditEvent) error
+	ListByUserID(ctx context.Context, userID string, limit, offset int) ([]*domain.AuditEvent, error)
+}
+
+// DataExportRepository defines the interface for personal data export jobs
+type DataExportRepository interface {
+	Create(ctx context.Context, export *domain.DataExport) error
+	GetByID(ctx context.Context, id string) (*domain.DataExport, error)
+	ListByUserID(ctx context.Context, userID string) ([]*domain.DataExport, error)
+	ListByStatus(ctx context.Context, status domain.ExportStatus, limit int) ([]*domain.DataExport, error)
+	ListExpired(ctx context.Context, before time.Time, limit int) ([]*domain.DataExport, error)
+	Update(ctx context.Context, export *domain.DataExport) error
+	// Complete stores a built archive and updates the export, which must be completed
+	Complete(ctx context.Context, export *domain.DataExport, archive []byte) error
+	// GetArchive returns the archive of a completed export
+	GetArchive(ctx context.Context, exportID string) ([]byte, error)
+	// Expire deletes an export's archive and marks it as expired
+	Expire(ctx context.Context, export *domain.DataExport) error
+}
+
+// PersonalDataRepository collects everything stored about a user for data exports
+type PersonalDataRepository interface {
+	Collect(ctx context.Context, userID string) (*domain.PersonalData, error)
+}
+
+// PasswordHistoryRepository defines the interface for previous password hashes
+type PasswordHistoryRepository interface {
+	Add(ctx context.Context, userID, passwordHash string) error
+	GetRecent(ctx context.Context, userID string, limit int) ([]string, error)
+	Prune(ctx context.Context, userID string, keep int) error
+}
+
+// MFARepository defines the interface for MFA recovery codes and role policies
+type MFARepository interface {
+	ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error
+	ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(ctx context.Context, userID string) (int, error)
+	DeleteRecoveryCodes(ctx context.Context, userID string) error
+	GetRolePolicy(ctx context.Context, role domain.UserRole) (*domain.MFARolePolicy, error)
+	ListRolePolicies(ctx context.Context) ([]*domain.MFARolePolicy, error)
+	UpsertRolePolicy(ctx context.Context, policy *domain.MFARolePolicy) error
+}
+
 // CustomerRepository defines the interface for customer data operations
 type CustomerRepository interface {
 	Create(ctx context.Context, customer *domain.Customer) error
@@ -26,14 +101,21 @@ type CustomerRepository interface {
 	GetNearby(ctx context.Context, lat, lng float64, radiusKm float64) ([]*domain.Customer, error)
 }
 
+// SearchLimit caps the number of results of nearby searches, nearest first
+const SearchLimit = 100
+
 // ServiceProviderRepository defines the interface for service provider data operations
 type ServiceProviderRepository interface {
 	Create(ctx context.Context, provider *domain.ServiceProvider) error
 	GetByID(ctx context.Context, id string) (*domain.ServiceProvider, error)
 	GetByUserID(ctx context.Context, userID string) (*domain.ServiceProvider, error)
 	Update(ctx context.Context, provider *domain.ServiceProvider) error
+	// Search returns up to SearchLimit providers within radiusKm, nearest first
 	Search(ctx context.Context, lat, lng float64, radiusKm float64, category *domain.ServiceCategory) ([]*domain.ServiceProvider, error)
 	GetAll(ctx context.Context, limit, offset int) ([]*domain.ServiceProvider, error)
+	// RefreshStats recomputes every provider's rating, review count and
+	// reliability score, returning the IDs of those that changed
+	RefreshStats(ctx context.Context) ([]string, error)
 }
 
 // ServiceRepository defines the interface for service data operations
@@ -45,14 +127,73 @@ type ServiceRepository interface {
 	Delete(ctx context.Context, id string) error
 }
 
-// ServiceRequestRepository defines the interface for service request data operations
+// ServiceRequestRepository defines the interface for service request data operations. Each
+// change records its event in the booking's history in the same transaction.
 type ServiceRequestRepository interface {
-	Create(ctx context.Context, request *domain.ServiceRequest) error
+	Create(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error
 	GetByID(ctx context.Context, id string) (*domain.ServiceRequest, error)
 	GetByCustomerID(ctx context.Context, customerID string) ([]*domain.ServiceRequest, error)
 	GetByProviderID(ctx context.Context, providerID string) ([]*domain.ServiceRequest, error)
 	Update(ctx context.Context, request *domain.ServiceRequest) error
-	UpdateStatus(ctx context.Context, id string, status domain.RequestStatus) error
+	// HasOverlapping reports whether a provider has a confirmed booking, other than
+	// excludeID, that overlaps the period from start to end
+	HasOverlapping(ctx context.Context, providerID, excludeID string, start, end time.Time) (bool, error)
+	// UpdateStatus moves a request from one status to another, failing with
+	// ErrConflict if it is no longer in status from
+	UpdateStatus(ctx context.Context, id string, from, to domain.RequestStatus, event *domain.ServiceRequestEvent) error
+	// Confirm moves a request from request.Status to confirmed and records its
+	// schedule. It fails with ErrOverlapping if the provider has another
+	// confirmed booking at that time, checked under a lock on the provider so
+	// concurrent confirmations cannot both pass, and with ErrConflict if the
+	// request's status has changed.
+	Confirm(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error
+	// Cancel moves a request from request.Cancellation.From to cancelled, recording
+	// request.Cancellation, and fails with ErrConflict if its status has changed
+	Cancel(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error
+	// ChangePrice updates request.QuotedPrice, failing with ErrConflict if its status has changed
+	ChangePrice(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error
+	// ListExpiring lists requests still awaiting the provider that were made
+	// before createdBefore or were to start before startBefore
+	ListExpiring(ctx context.Context, createdBefore, startBefore time.Time, limit int) ([]*domain.ServiceRequest, error)
+	// ListOverdue lists confirmed bookings, not yet flagged, that ended before endedBefore
+	ListOverdue(ctx context.Context, endedBefore time.Time, limit int) ([]*domain.ServiceRequest, error)
+	// FlagOverdue records request.OverdueAt, failing with ErrConflict if the
+	// booking is no longer confirmed or was already flagged
+	FlagOverdue(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error
+}
+
+// ServiceRequestEventRepository defines the interface for bookings' append-only history
+type ServiceRequestEventRepository interface {
+	Create(ctx context.Context, event *domain.ServiceRequestEvent) error
+	ListByRequestID(ctx context.Context, requestID string) ([]*domain.ServiceRequestEvent, error)
+	// GetAnalytics summarizes the bookings requested from `from` until `to` of
+	// one provider, or of all providers if providerID is empty
+	GetAnalytics(ctx context.Context, providerID string, from, to time.Time) (*domain.BookingAnalytics, error)
+}
+
+// RescheduleProposalRepository defines the interface for bookings' reschedule negotiations.
+// Answering a proposal fails with ErrConflict if it is no longer pending. Each
+// change records its event in the booking's history in the same transaction.
+type RescheduleProposalRepository interface {
+	// Create fails with ErrConflict if the booking already has a pending proposal
+	Create(ctx context.Context, proposal *domain.RescheduleProposal, event *domain.ServiceRequestEvent) error
+	GetPending(ctx context.Context, requestID string) (*domain.RescheduleProposal, error)
+	ListByRequestID(ctx context.Context, requestID string) ([]*domain.RescheduleProposal, error)
+	Reject(ctx context.Context, proposal *domain.RescheduleProposal, event *domain.ServiceRequestEvent) error
+	// Counter records proposal as countered and creates counter in its place
+	Counter(ctx context.Context, proposal, counter *domain.RescheduleProposal, event *domain.ServiceRequestEvent) error
+	// Accept records proposal as accepted and moves request to its new dates,
+	// failing with ErrOverlapping if they overlap another of the provider's
+	// confirmed bookings (checked as in ServiceRequestRepository.Confirm) and
+	// with ErrConflict if the request's status has changed
+	Accept(ctx context.Context, proposal *domain.RescheduleProposal, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error
+}
+
+// CancellationPolicyRepository defines the interface for providers' cancellation windows
+type CancellationPolicyRepository interface {
+	GetByProviderID(ctx context.Context, providerID string) (domain.CancellationPolicy, error)
+	// Replace replaces all of a provider's windows with policy
+	Replace(ctx context.Context, providerID string, policy domain.CancellationPolicy) error
 }
 
 // ReviewRepository defines the interface for review data operations
@@ -68,6 +209,7 @@ type ReviewRepository interface {
 // AvailabilityRepository defines the interface for availability data operations
 type AvailabilityRepository interface {
 	Create(ctx context.Context, availability *domain.Availability) error
+	GetByID(ctx context.Context, id string) (*domain.Availability, error)
 	GetByProviderID(ctx context.Context, providerID string) ([]*domain.Availability, error)
 	Update(ctx context.Context, availability *domain.Availability) error
 	Delete(ctx context.Context, id string) error
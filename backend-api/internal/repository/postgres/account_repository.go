@@ -0,0 +1,56 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"karigar-backend/internal/repository"
+)
+
+type accountRepository struct {
+	db *sql.DB
+}
+
+// NewAccountRepository creates a new PostgreSQL account repository
+func NewAccountRepository(db *sql.DB) repository.AccountRepository {
+	return &accountRepository{
+		db: db,
+	}
+}
+
+// AnonymizeAndDelete scrubs personal data from a user's bookings and deletes the user.
+// Deleting the user cascades to the customer/provider profile, MFA and password history;
+// bookings and reviews are kept with their customer/provider references set to NULL
+// (see migration 011) so provider ratings and booking history stay intact.
+func (r *accountRepository) AnonymizeAndDelete(ctx context.Context, userID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// The service address and notes on a customer's bookings are personal data
+	_, err = tx.ExecContext(ctx, `
+		UPDATE service_requests
+		SET address = '[deleted]', notes = NULL
+		WHERE customer_id IN (SELECT id FROM customers WHERE user_id = $1)
+	`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to anonymize service requests: %w", err)
+	}
+
+	// So are the notes the user left in bookings' histories and reschedule proposals
+	if _, err := tx.ExecContext(ctx, `UPDATE service_request_events SET note = NULL WHERE actor_user_id = $1 AND note IS NOT NULL`, userID); err != nil {
+		return fmt.Errorf("failed to anonymize service request events: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE reschedule_proposals SET note = NULL WHERE proposed_by_user_id = $1 AND note IS NOT NULL`, userID); err != nil {
+		return fmt.Errorf("failed to anonymize reschedule proposals: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return tx.Commit()
+}
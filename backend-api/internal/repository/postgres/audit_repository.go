@@ -0,0 +1,114 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates a new PostgreSQL audit repository
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+func (r *auditRepository) Create(ctx context.Context, event *domain.AuditEvent) error {
+	query := `
+		INSERT INTO audit_events (id, user_id, actor_id, action, ip_address, user_agent, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	metadata := event.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit metadata: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, query,
+		event.ID,
+		event.UserID,
+		event.ActorID,
+		event.Action,
+		nullString(event.IPAddress),
+		nullString(event.UserAgent),
+		metadataJSON,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create audit event: %w", err)
+	}
+
+	return nil
+}
+
+// ListByUserID lists audit events about a user, newest first
+func (r *auditRepository) ListByUserID(ctx context.Context, userID string, limit, offset int) ([]*domain.AuditEvent, error) {
+	query := `
+		SELECT id, user_id, actor_id, action, ip_address, user_agent, metadata, created_at
+		FROM audit_events
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*domain.AuditEvent
+	for rows.Next() {
+		event := &domain.AuditEvent{}
+		var userIDValue, actorID uuid.NullUUID
+		var ipAddress, userAgent sql.NullString
+		var metadata []byte
+
+		err := rows.Scan(&event.ID, &userIDValue, &actorID, &event.Action, &ipAddress, &userAgent, &metadata, &event.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit event: %w", err)
+		}
+
+		if userIDValue.Valid {
+			event.UserID = &userIDValue.UUID
+		}
+		if actorID.Valid {
+			event.ActorID = &actorID.UUID
+		}
+		event.IPAddress = ipAddress.String
+		event.UserAgent = userAgent.String
+		if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode audit metadata: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// nullString converts an empty string to NULL
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
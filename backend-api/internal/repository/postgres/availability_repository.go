@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrAvailabilityNotFound = fmt.Errorf("availability %w", repository.ErrNotFound)
+)
+
+// availabilityColumns formats the TIME columns as "HH:MM" to match domain.Availability
+const availabilityColumns = `id, provider_id, day_of_week, to_char(start_time, 'HH24:MI'), to_char(end_time, 'HH24:MI'),
+		       is_available, created_at, updated_at`
+
+type availabilityRepository struct {
+	db *sql.DB
+}
+
+// NewAvailabilityRepository creates a new PostgreSQL availability repository
+func NewAvailabilityRepository(db *sql.DB) repository.AvailabilityRepository {
+	return &availabilityRepository{
+		db: db,
+	}
+}
+
+func (r *availabilityRepository) Create(ctx context.Context, availability *domain.Availability) error {
+	query := `
+		INSERT INTO availability (id, provider_id, day_of_week, start_time, end_time, is_available, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+	`
+
+	now := time.Now()
+	availability.CreatedAt = now
+	availability.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		availability.ID,
+		availability.ProviderID,
+		availability.DayOfWeek,
+		availability.StartTime,
+		availability.EndTime,
+		availability.IsAvailable,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create availability: %w", mapError(err))
+	}
+
+	return nil
+}
+
+func (r *availabilityRepository) GetByID(ctx context.Context, id string) (*domain.Availability, error) {
+	query := `SELECT ` + availabilityColumns + ` FROM availability WHERE id = $1`
+
+	availability, err := scanAvailability(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrAvailabilityNotFound
+		}
+		return nil, fmt.Errorf("failed to get availability: %w", err)
+	}
+
+	return availability, nil
+}
+
+// GetByProviderID lists a provider's weekly schedule, Sunday first
+func (r *availabilityRepository) GetByProviderID(ctx context.Context, providerID string) ([]*domain.Availability, error) {
+	query := `SELECT ` + availabilityColumns + ` FROM availability
+		WHERE provider_id = $1
+		ORDER BY day_of_week, start_time`
+
+	rows, err := r.db.QueryContext(ctx, query, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list availability: %w", err)
+	}
+	defer rows.Close()
+
+	var slots []*domain.Availability
+	for rows.Next() {
+		availability, err := scanAvailability(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan availability: %w", err)
+		}
+		slots = append(slots, availability)
+	}
+
+	return slots, rows.Err()
+}
+
+func (r *availabilityRepository) Update(ctx context.Context, availability *domain.Availability) error {
+	query := `
+		UPDATE availability
+		SET day_of_week = $2, start_time = $3, end_time = $4, is_available = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	availability.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		availability.ID,
+		availability.DayOfWeek,
+		availability.StartTime,
+		availability.EndTime,
+		availability.IsAvailable,
+		availability.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update availability: %w", mapError(err))
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrAvailabilityNotFound
+	}
+
+	return nil
+}
+
+func (r *availabilityRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM availability WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete availability: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrAvailabilityNotFound
+	}
+
+	return nil
+}
+
+func scanAvailability(row rowScanner) (*domain.Availability, error) {
+	availability := &domain.Availability{}
+	var isAvailable sql.NullBool
+
+	err := row.Scan(
+		&availability.ID,
+		&availability.ProviderID,
+		&availability.DayOfWeek,
+		&availability.StartTime,
+		&availability.EndTime,
+		&isAvailable,
+		&availability.CreatedAt,
+		&availability.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	availability.IsAvailable = isAvailable.Bool
+
+	return availability, nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+type cancellationPolicyRepository struct {
+	db *sql.DB
+}
+
+// NewCancellationPolicyRepository creates a new PostgreSQL cancellation policy repository
+func NewCancellationPolicyRepository(db *sql.DB) repository.CancellationPolicyRepository {
+	return &cancellationPolicyRepository{
+		db: db,
+	}
+}
+
+// GetByProviderID returns a provider's cancellation windows, narrowest first
+func (r *cancellationPolicyRepository) GetByProviderID(ctx context.Context, providerID string) (domain.CancellationPolicy, error) {
+	query := `
+		SELECT id, provider_id, hours_before, fee_percent, created_at
+		FROM cancellation_windows
+		WHERE provider_id = $1
+		ORDER BY hours_before
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cancellation policy: %w", err)
+	}
+	defer rows.Close()
+
+	policy := domain.CancellationPolicy{}
+	for rows.Next() {
+		var window domain.CancellationWindow
+		if err := rows.Scan(&window.ID, &window.ProviderID, &window.HoursBefore, &window.FeePercent, &window.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan cancellation window: %w", err)
+		}
+		policy = append(policy, window)
+	}
+
+	return policy, rows.Err()
+}
+
+func (r *cancellationPolicyRepository) Replace(ctx context.Context, providerID string, policy domain.CancellationPolicy) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM cancellation_windows WHERE provider_id = $1`, providerID); err != nil {
+		return fmt.Errorf("failed to clear cancellation policy: %w", err)
+	}
+
+	now := time.Now()
+	for i := range policy {
+		window := &policy[i]
+		window.ID = uuid.New()
+		window.CreatedAt = now
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO cancellation_windows (id, provider_id, hours_before, fee_percent, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+		`, window.ID, providerID, window.HoursBefore, window.FeePercent, now)
+		if err != nil {
+			return fmt.Errorf("failed to add cancellation window: %w", mapError(err))
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,175 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrCustomerNotFound = fmt.Errorf("customer %w", repository.ErrNotFound)
+)
+
+const customerColumns = `id, user_id, phone, address, latitude, longitude, created_at, updated_at`
+
+type customerRepository struct {
+	db *sql.DB
+}
+
+// NewCustomerRepository creates a new PostgreSQL customer repository
+func NewCustomerRepository(db *sql.DB) repository.CustomerRepository {
+	return &customerRepository{
+		db: db,
+	}
+}
+
+func (r *customerRepository) Create(ctx context.Context, customer *domain.Customer) error {
+	query := `
+		INSERT INTO customers (id, user_id, phone, address, latitude, longitude, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+	`
+
+	now := time.Now()
+	customer.CreatedAt = now
+	customer.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		customer.ID,
+		customer.UserID,
+		nullString(customer.Phone),
+		nullString(customer.Address),
+		nullCoordinate(customer.Latitude, customer.Longitude),
+		nullCoordinate(customer.Longitude, customer.Latitude),
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create customer: %w", mapError(err))
+	}
+
+	return nil
+}
+
+func (r *customerRepository) GetByID(ctx context.Context, id string) (*domain.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+func (r *customerRepository) GetByUserID(ctx context.Context, userID string) (*domain.Customer, error) {
+	query := `SELECT ` + customerColumns + ` FROM customers WHERE user_id = $1`
+	return r.get(ctx, query, userID)
+}
+
+func (r *customerRepository) Update(ctx context.Context, customer *domain.Customer) error {
+	query := `
+		UPDATE customers
+		SET phone = $2, address = $3, latitude = $4, longitude = $5, updated_at = $6
+		WHERE id = $1
+	`
+
+	customer.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		customer.ID,
+		nullString(customer.Phone),
+		nullString(customer.Address),
+		nullCoordinate(customer.Latitude, customer.Longitude),
+		nullCoordinate(customer.Longitude, customer.Latitude),
+		customer.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update customer: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrCustomerNotFound
+	}
+
+	return nil
+}
+
+// GetNearby finds customers within radiusKm of a point, nearest first
+func (r *customerRepository) GetNearby(ctx context.Context, lat, lng float64, radiusKm float64) ([]*domain.Customer, error) {
+	// A bounding box lets the location index narrow the rows before the exact distance check
+	latDelta := radiusKm / 111.0
+	lngDelta := radiusKm / (111.0 * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+
+	query := `
+		SELECT ` + customerColumns + ` FROM (
+			SELECT c.*,
+			       6371 * acos(LEAST(1, cos(radians($1)) * cos(radians(c.latitude)) * cos(radians(c.longitude) - radians($2))
+			                         + sin(radians($1)) * sin(radians(c.latitude)))) AS distance_km
+			FROM customers c
+			WHERE c.latitude BETWEEN $1 - $4 AND $1 + $4
+			  AND c.longitude BETWEEN $2 - $5 AND $2 + $5
+		) nearby
+		WHERE distance_km <= $3
+		ORDER BY distance_km
+		LIMIT $6
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, lat, lng, radiusKm, latDelta, lngDelta, searchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list customers: %w", err)
+	}
+	defer rows.Close()
+
+	var customers []*domain.Customer
+	for rows.Next() {
+		customer, err := scanCustomer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan customer: %w", err)
+		}
+		customers = append(customers, customer)
+	}
+
+	return customers, rows.Err()
+}
+
+func (r *customerRepository) get(ctx context.Context, query string, arg string) (*domain.Customer, error) {
+	customer, err := scanCustomer(r.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return customer, nil
+}
+
+func scanCustomer(row rowScanner) (*domain.Customer, error) {
+	customer := &domain.Customer{}
+	var phone, address sql.NullString
+	var latitude, longitude sql.NullFloat64
+
+	err := row.Scan(
+		&customer.ID,
+		&customer.UserID,
+		&phone,
+		&address,
+		&latitude,
+		&longitude,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	customer.Phone = phone.String
+	customer.Address = address.String
+	customer.Latitude = latitude.Float64
+	customer.Longitude = longitude.Float64
+
+	return customer, nil
+}
+
+// nullCoordinate stores an unset location (0, 0) as NULL, so it stays out of
+// location searches
+func nullCoordinate(value, other float64) sql.NullFloat64 {
+	return sql.NullFloat64{Float64: value, Valid: value != 0 || other != 0}
+}
@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrDataExportNotFound    = fmt.Errorf("data export %w", repository.ErrNotFound)
+	ErrExportArchiveNotFound = fmt.Errorf("data export archive %w", repository.ErrNotFound)
+)
+
+const dataExportColumns = `id, user_id, requested_by, status, file_size, error,
+		       created_at, started_at, completed_at, expires_at, updated_at`
+
+type dataExportRepository struct {
+	db *sql.DB
+}
+
+// NewDataExportRepository creates a new PostgreSQL data export repository
+func NewDataExportRepository(db *sql.DB) repository.DataExportRepository {
+	return &dataExportRepository{
+		db: db,
+	}
+}
+
+func (r *dataExportRepository) Create(ctx context.Context, export *domain.DataExport) error {
+	query := `
+		INSERT INTO data_exports (id, user_id, requested_by, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $5)
+	`
+
+	now := time.Now()
+	export.CreatedAt = now
+	export.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query, export.ID, export.UserID, export.RequestedBy, export.Status, now)
+	if err != nil {
+		return fmt.Errorf("failed to create data export: %w", err)
+	}
+
+	return nil
+}
+
+func (r *dataExportRepository) GetByID(ctx context.Context, id string) (*domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE id = $1`
+
+	export, err := scanDataExport(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrDataExportNotFound
+		}
+		return nil, fmt.Errorf("failed to get data export: %w", err)
+	}
+
+	return export, nil
+}
+
+// ListByUserID lists a user's exports, newest first
+func (r *dataExportRepository) ListByUserID(ctx context.Context, userID string) ([]*domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE user_id = $1 ORDER BY created_at DESC`
+	return r.list(ctx, query, userID)
+}
+
+// ListByStatus lists exports in a given status, oldest first
+func (r *dataExportRepository) ListByStatus(ctx context.Context, status domain.ExportStatus, limit int) ([]*domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports WHERE status = $1 ORDER BY created_at LIMIT $2`
+	return r.list(ctx, query, status, limit)
+}
+
+// ListExpired lists completed exports whose download link expired before the given time
+func (r *dataExportRepository) ListExpired(ctx context.Context, before time.Time, limit int) ([]*domain.DataExport, error) {
+	query := `SELECT ` + dataExportColumns + ` FROM data_exports
+		WHERE status = 'completed' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT $2`
+	return r.list(ctx, query, before, limit)
+}
+
+func (r *dataExportRepository) Update(ctx context.Context, export *domain.DataExport) error {
+	return updateDataExport(ctx, r.db, export)
+}
+
+func (r *dataExportRepository) Complete(ctx context.Context, export *domain.DataExport, archive []byte) error {
+	query := `
+		INSERT INTO data_export_archives (export_id, content, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (export_id) DO UPDATE SET content = EXCLUDED.content, created_at = EXCLUDED.created_at
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, export.ID, archive, time.Now()); err != nil {
+		return fmt.Errorf("failed to store data export archive: %w", err)
+	}
+	if err := updateDataExport(ctx, tx, export); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *dataExportRepository) GetArchive(ctx context.Context, exportID string) ([]byte, error) {
+	var archive []byte
+	err := r.db.QueryRowContext(ctx, `SELECT content FROM data_export_archives WHERE export_id = $1`, exportID).Scan(&archive)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrExportArchiveNotFound
+		}
+		return nil, fmt.Errorf("failed to get data export archive: %w", err)
+	}
+
+	return archive, nil
+}
+
+func (r *dataExportRepository) Expire(ctx context.Context, export *domain.DataExport) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM data_export_archives WHERE export_id = $1`, export.ID); err != nil {
+		return fmt.Errorf("failed to delete data export archive: %w", err)
+	}
+	export.Status = domain.ExportStatusExpired
+	export.FileSize = 0
+	if err := updateDataExport(ctx, tx, export); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func updateDataExport(ctx context.Context, db execer, export *domain.DataExport) error {
+	query := `
+		UPDATE data_exports
+		SET status = $2, file_size = $3, error = $4,
+		    started_at = $5, completed_at = $6, expires_at = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	_, err := db.ExecContext(ctx, query,
+		export.ID,
+		export.Status,
+		sql.NullInt64{Int64: export.FileSize, Valid: export.FileSize > 0},
+		nullString(export.Error),
+		export.StartedAt,
+		export.CompletedAt,
+		export.ExpiresAt,
+		time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update data export: %w", err)
+	}
+
+	return nil
+}
+
+func (r *dataExportRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.DataExport, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list data exports: %w", err)
+	}
+	defer rows.Close()
+
+	var exports []*domain.DataExport
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan data export: %w", err)
+		}
+		exports = append(exports, export)
+	}
+
+	return exports, rows.Err()
+}
+
+func scanDataExport(row rowScanner) (*domain.DataExport, error) {
+	export := &domain.DataExport{}
+	var requestedBy uuid.NullUUID
+	var exportError sql.NullString
+	var fileSize sql.NullInt64
+	var startedAt, completedAt, expiresAt sql.NullTime
+
+	err := row.Scan(
+		&export.ID,
+		&export.UserID,
+		&requestedBy,
+		&export.Status,
+		&fileSize,
+		&exportError,
+		&export.CreatedAt,
+		&startedAt,
+		&completedAt,
+		&expiresAt,
+		&export.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if requestedBy.Valid {
+		export.RequestedBy = &requestedBy.UUID
+	}
+	export.FileSize = fileSize.Int64
+	export.Error = exportError.String
+	if startedAt.Valid {
+		export.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+
+	return export, nil
+}
@@ -0,0 +1,22 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"karigar-backend/internal/repository"
+)
+
+// uniqueViolation is the PostgreSQL error code for unique constraint violations
+const uniqueViolation = "23505"
+
+// mapError translates driver errors into repository errors so callers can test
+// them with errors.Is without depending on the driver
+func mapError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+		return fmt.Errorf("%w: %s", repository.ErrConflict, pqErr.Constraint)
+	}
+	return err
+}
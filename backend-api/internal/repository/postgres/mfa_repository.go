@@ -0,0 +1,168 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+// NewMFARepository creates a new PostgreSQL MFA repository
+func NewMFARepository(db *sql.DB) repository.MFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+// ReplaceRecoveryCodes deletes all existing recovery codes for a user and stores new ones
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID string, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+
+	for _, hash := range codeHashes {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO mfa_recovery_codes (id, user_id, code_hash, created_at)
+			VALUES ($1, $2, $3, $4)
+		`, uuid.New(), userID, hash, time.Now())
+		if err != nil {
+			return fmt.Errorf("failed to create recovery code: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ConsumeRecoveryCode marks an unused recovery code as used. It returns false if no unused code matched.
+func (r *mfaRepository) ConsumeRecoveryCode(ctx context.Context, userID, codeHash string) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, codeHash, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to consume recovery code: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rows == 1, nil
+}
+
+// CountUnusedRecoveryCodes returns how many recovery codes a user has left
+func (r *mfaRepository) CountUnusedRecoveryCodes(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx,
+		`SELECT COUNT(*) FROM mfa_recovery_codes WHERE user_id = $1 AND used_at IS NULL`, userID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteRecoveryCodes deletes all recovery codes for a user
+func (r *mfaRepository) DeleteRecoveryCodes(ctx context.Context, userID string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	return nil
+}
+
+// GetRolePolicy gets the MFA policy for a role. Roles without a stored policy do not require MFA.
+func (r *mfaRepository) GetRolePolicy(ctx context.Context, role domain.UserRole) (*domain.MFARolePolicy, error) {
+	query := `
+		SELECT role, mfa_required, updated_by, created_at, updated_at
+		FROM mfa_role_policies
+		WHERE role = $1
+	`
+
+	policy, err := scanMFARolePolicy(r.db.QueryRowContext(ctx, query, role))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &domain.MFARolePolicy{Role: role}, nil
+		}
+		return nil, fmt.Errorf("failed to get MFA role policy: %w", err)
+	}
+
+	return policy, nil
+}
+
+// ListRolePolicies lists all stored MFA role policies
+func (r *mfaRepository) ListRolePolicies(ctx context.Context) ([]*domain.MFARolePolicy, error) {
+	query := `
+		SELECT role, mfa_required, updated_by, created_at, updated_at
+		FROM mfa_role_policies
+		ORDER BY role
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list MFA role policies: %w", err)
+	}
+	defer rows.Close()
+
+	var policies []*domain.MFARolePolicy
+	for rows.Next() {
+		policy, err := scanMFARolePolicy(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan MFA role policy: %w", err)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, rows.Err()
+}
+
+// UpsertRolePolicy creates or updates the MFA policy for a role
+func (r *mfaRepository) UpsertRolePolicy(ctx context.Context, policy *domain.MFARolePolicy) error {
+	query := `
+		INSERT INTO mfa_role_policies (role, mfa_required, updated_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (role) DO UPDATE
+		SET mfa_required = EXCLUDED.mfa_required, updated_by = EXCLUDED.updated_by, updated_at = EXCLUDED.updated_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query, policy.Role, policy.MFARequired, policy.UpdatedBy, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to save MFA role policy: %w", err)
+	}
+
+	return nil
+}
+
+func scanMFARolePolicy(row rowScanner) (*domain.MFARolePolicy, error) {
+	policy := &domain.MFARolePolicy{}
+	var updatedBy uuid.NullUUID
+
+	if err := row.Scan(&policy.Role, &policy.MFARequired, &updatedBy, &policy.CreatedAt, &policy.UpdatedAt); err != nil {
+		return nil, err
+	}
+
+	if updatedBy.Valid {
+		policy.UpdatedBy = &updatedBy.UUID
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/repository"
+)
+
+type passwordHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewPasswordHistoryRepository creates a new PostgreSQL password history repository
+func NewPasswordHistoryRepository(db *sql.DB) repository.PasswordHistoryRepository {
+	return &passwordHistoryRepository{
+		db: db,
+	}
+}
+
+// Add stores a previous password hash
+func (r *passwordHistoryRepository) Add(ctx context.Context, userID, passwordHash string) error {
+	query := `
+		INSERT INTO password_history (id, user_id, password_hash, created_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, uuid.New(), userID, passwordHash, time.Now()); err != nil {
+		return fmt.Errorf("failed to add password history: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecent returns the most recent previous password hashes, newest first
+func (r *passwordHistoryRepository) GetRecent(ctx context.Context, userID string, limit int) ([]string, error) {
+	query := `
+		SELECT password_hash
+		FROM password_history
+		WHERE user_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get password history: %w", err)
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan password history: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, rows.Err()
+}
+
+// Prune deletes all but the most recent keep entries for a user
+func (r *passwordHistoryRepository) Prune(ctx context.Context, userID string, keep int) error {
+	query := `
+		DELETE FROM password_history
+		WHERE user_id = $1 AND id NOT IN (
+			SELECT id FROM password_history
+			WHERE user_id = $1
+			ORDER BY created_at DESC
+			LIMIT $2
+		)
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, keep); err != nil {
+		return fmt.Errorf("failed to prune password history: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+// exportedUserColumns are the users columns included in a data export. Columns
+// are listed explicitly so credentials, tokens and columns added later are left
+// out until someone decides they belong in an export.
+const exportedUserColumns = `id, email, role, is_email_verified, phone, is_phone_verified, phone_verified_at,
+		mfa_enabled, mfa_enrolled_at, deletion_requested_at, deletion_scheduled_at, created_at, updated_at`
+
+type personalDataRepository struct {
+	db *sql.DB
+}
+
+// NewPersonalDataRepository creates a new PostgreSQL personal data repository
+func NewPersonalDataRepository(db *sql.DB) repository.PersonalDataRepository {
+	return &personalDataRepository{
+		db: db,
+	}
+}
+
+// Collect gathers every row that belongs to or is about a user. Apart from the
+// user's own row, rows are selected with SELECT * so columns added by later
+// migrations are exported without changes here.
+func (r *personalDataRepository) Collect(ctx context.Context, userID string) (*domain.PersonalData, error) {
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	const customerIDs = `SELECT id FROM customers WHERE user_id = $1`
+	const providerIDs = `SELECT id FROM service_providers WHERE user_id = $1`
+
+	data := &domain.PersonalData{}
+
+	users, err := queryMaps(ctx, tx, `SELECT `+exportedUserColumns+` FROM users WHERE id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect user: %w", err)
+	}
+	if len(users) == 0 {
+		return nil, ErrUserNotFound
+	}
+	data.User = users[0]
+
+	customers, err := queryMaps(ctx, tx, `SELECT * FROM customers WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect customer profile: %w", err)
+	}
+	if len(customers) > 0 {
+		data.CustomerProfile = customers[0]
+	}
+
+	providers, err := queryMaps(ctx, tx, `SELECT * FROM service_providers WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to collect service provider profile: %w", err)
+	}
+	if len(providers) > 0 {
+		data.ProviderProfile = providers[0]
+	}
+
+	sections := []struct {
+		name  string
+		query string
+		dest  *[]map[string]interface{}
+	}{
+		{"services", `SELECT * FROM services WHERE provider_id IN (` + providerIDs + `) ORDER BY created_at`, &data.Services},
+		{"bookings", `SELECT * FROM service_requests
+			WHERE customer_id IN (` + customerIDs + `) OR provider_id IN (` + providerIDs + `)
+			ORDER BY created_at`, &data.Bookings},
+		{"booking events", `SELECT * FROM service_request_events WHERE actor_user_id = $1 ORDER BY created_at`, &data.BookingEvents},
+		{"reviews written", `SELECT * FROM reviews WHERE customer_id IN (` + customerIDs + `) ORDER BY created_at`, &data.ReviewsWritten},
+		{"reviews received", `SELECT * FROM reviews WHERE provider_id IN (` + providerIDs + `) ORDER BY created_at`, &data.ReviewsReceived},
+		{"availability", `SELECT * FROM availability WHERE provider_id IN (` + providerIDs + `)`, &data.Availability},
+		{"audit events", `SELECT * FROM audit_events WHERE user_id = $1 ORDER BY created_at`, &data.AuditEvents},
+		{"sessions", `SELECT id, user_agent, ip_address, location, created_at, last_seen_at, expires_at, revoked_at
+			FROM user_sessions WHERE user_id = $1 ORDER BY created_at`, &data.Sessions},
+	}
+	for _, section := range sections {
+		rows, err := queryMaps(ctx, tx, section.query, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to collect %s: %w", section.name, err)
+		}
+		*section.dest = rows
+	}
+
+	return data, nil
+}
+
+// queryMaps runs a query and returns each row as a column name to value map
+func queryMaps(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]map[string]interface{}, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		values := make([]interface{}, len(columns))
+		pointers := make([]interface{}, len(columns))
+		for i := range values {
+			pointers[i] = &values[i]
+		}
+		if err := rows.Scan(pointers...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, column := range columns {
+			row[column.Name()] = exportValue(column.DatabaseTypeName(), values[i])
+		}
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+// exportValue converts a raw driver value into something that marshals to readable JSON.
+// The driver returns text-like types such as UUID and NUMERIC as []byte.
+func exportValue(databaseType string, value interface{}) interface{} {
+	b, ok := value.([]byte)
+	if !ok {
+		return value
+	}
+
+	switch databaseType {
+	case "JSON", "JSONB":
+		return json.RawMessage(b)
+	case "BYTEA":
+		return b
+	default:
+		return string(b)
+	}
+}
@@ -0,0 +1,277 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrRescheduleProposalNotFound = fmt.Errorf("reschedule proposal %w", repository.ErrNotFound)
+	ErrRescheduleProposalAnswered = fmt.Errorf("reschedule proposal already answered: %w", repository.ErrConflict)
+)
+
+const rescheduleProposalColumns = `id, request_id, proposed_by, proposed_by_user_id, proposed_date, note, status,
+		       counter_to_id, responded_at, responded_by_user_id, created_at`
+
+type rescheduleProposalRepository struct {
+	db *sql.DB
+}
+
+// NewRescheduleProposalRepository creates a new PostgreSQL reschedule proposal repository
+func NewRescheduleProposalRepository(db *sql.DB) repository.RescheduleProposalRepository {
+	return &rescheduleProposalRepository{
+		db: db,
+	}
+}
+
+// Create fails with ErrConflict if the booking already has a pending proposal
+func (r *rescheduleProposalRepository) Create(ctx context.Context, proposal *domain.RescheduleProposal, event *domain.ServiceRequestEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := createRescheduleProposal(ctx, tx, proposal); err != nil {
+		return err
+	}
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *rescheduleProposalRepository) GetPending(ctx context.Context, requestID string) (*domain.RescheduleProposal, error) {
+	query := `SELECT ` + rescheduleProposalColumns + ` FROM reschedule_proposals WHERE request_id = $1 AND status = 'pending'`
+
+	proposal, err := scanRescheduleProposal(r.db.QueryRowContext(ctx, query, requestID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRescheduleProposalNotFound
+		}
+		return nil, fmt.Errorf("failed to get reschedule proposal: %w", err)
+	}
+
+	return proposal, nil
+}
+
+// ListByRequestID lists a booking's proposals, oldest first
+func (r *rescheduleProposalRepository) ListByRequestID(ctx context.Context, requestID string) ([]*domain.RescheduleProposal, error) {
+	query := `SELECT ` + rescheduleProposalColumns + ` FROM reschedule_proposals
+		WHERE request_id = $1
+		ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reschedule proposals: %w", err)
+	}
+	defer rows.Close()
+
+	proposals := []*domain.RescheduleProposal{}
+	for rows.Next() {
+		proposal, err := scanRescheduleProposal(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan reschedule proposal: %w", err)
+		}
+		proposals = append(proposals, proposal)
+	}
+
+	return proposals, rows.Err()
+}
+
+func (r *rescheduleProposalRepository) Reject(ctx context.Context, proposal *domain.RescheduleProposal, event *domain.ServiceRequestEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := answerRescheduleProposal(ctx, tx, proposal); err != nil {
+		return err
+	}
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *rescheduleProposalRepository) Counter(ctx context.Context, proposal, counter *domain.RescheduleProposal, event *domain.ServiceRequestEvent) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := answerRescheduleProposal(ctx, tx, proposal); err != nil {
+		return err
+	}
+	if err := createRescheduleProposal(ctx, tx, counter); err != nil {
+		return err
+	}
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *rescheduleProposalRepository) Accept(ctx context.Context, proposal *domain.RescheduleProposal, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error {
+	query := `
+		UPDATE service_requests
+		SET scheduled_date = $3, estimated_end_date = $4, updated_at = $5
+		WHERE id = $1 AND status = $2
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Checked again under the provider's lock, which confirmations take too
+	if err := reserveSchedule(ctx, tx, request); err != nil {
+		return err
+	}
+	if err := answerRescheduleProposal(ctx, tx, proposal); err != nil {
+		return err
+	}
+
+	request.UpdatedAt = time.Now()
+	result, err := tx.ExecContext(ctx, query, request.ID, request.Status, request.ScheduledDate, request.EstimatedEndDate, request.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule service request: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to reschedule service request: %w", err)
+	}
+	if affected == 0 {
+		return ErrServiceRequestStatusChanged
+	}
+
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// execer is implemented by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+func createRescheduleProposal(ctx context.Context, db execer, proposal *domain.RescheduleProposal) error {
+	query := `
+		INSERT INTO reschedule_proposals (id, request_id, proposed_by, proposed_by_user_id, proposed_date, note,
+		                                  status, counter_to_id, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	proposal.CreatedAt = time.Now()
+	_, err := db.ExecContext(ctx, query,
+		proposal.ID,
+		proposal.RequestID,
+		proposal.ProposedBy,
+		nullUUID(proposal.ProposedByUserID),
+		proposal.ProposedDate,
+		nullString(proposal.Note),
+		proposal.Status,
+		proposal.CounterToID,
+		proposal.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create reschedule proposal: %w", mapError(err))
+	}
+
+	return nil
+}
+
+// answerRescheduleProposal records the answer to a pending proposal, failing
+// with ErrConflict if it has already been answered
+func answerRescheduleProposal(ctx context.Context, db execer, proposal *domain.RescheduleProposal) error {
+	query := `
+		UPDATE reschedule_proposals
+		SET status = $2, responded_at = $3, responded_by_user_id = $4
+		WHERE id = $1 AND status = 'pending'
+	`
+
+	result, err := db.ExecContext(ctx, query, proposal.ID, proposal.Status, proposal.RespondedAt, nullUUID(proposal.RespondedByUserID))
+	if err != nil {
+		return fmt.Errorf("failed to answer reschedule proposal: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to answer reschedule proposal: %w", err)
+	}
+	if affected == 0 {
+		return ErrRescheduleProposalAnswered
+	}
+
+	return nil
+}
+
+// expireRescheduleProposals closes a booking's pending proposal once it can no
+// longer be rescheduled, recording that the system did so
+func expireRescheduleProposals(ctx context.Context, tx *sql.Tx, requestID string) error {
+	_, err := tx.ExecContext(ctx, `
+		WITH expired AS (
+			UPDATE reschedule_proposals SET status = 'expired', responded_at = $2
+			WHERE request_id = $1 AND status = 'pending'
+			RETURNING id, request_id
+		)
+		INSERT INTO service_request_events (id, request_id, type, actor, metadata, created_at)
+		SELECT uuid_generate_v4(), request_id, 'reschedule_expired', 'system', jsonb_build_object('proposal_id', id), $2
+		FROM expired
+	`, requestID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to expire reschedule proposals: %w", err)
+	}
+
+	return nil
+}
+
+func scanRescheduleProposal(row rowScanner) (*domain.RescheduleProposal, error) {
+	proposal := &domain.RescheduleProposal{}
+	var proposedByUserID, counterToID, respondedByUserID uuid.NullUUID
+	var note sql.NullString
+	var respondedAt sql.NullTime
+
+	err := row.Scan(
+		&proposal.ID,
+		&proposal.RequestID,
+		&proposal.ProposedBy,
+		&proposedByUserID,
+		&proposal.ProposedDate,
+		&note,
+		&proposal.Status,
+		&counterToID,
+		&respondedAt,
+		&respondedByUserID,
+		&proposal.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	proposal.ProposedByUserID = proposedByUserID.UUID
+	proposal.Note = note.String
+	if counterToID.Valid {
+		proposal.CounterToID = &counterToID.UUID
+	}
+	if respondedAt.Valid {
+		proposal.RespondedAt = &respondedAt.Time
+	}
+	proposal.RespondedByUserID = respondedByUserID.UUID
+
+	return proposal, nil
+}
@@ -0,0 +1,203 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrReviewNotFound = fmt.Errorf("review %w", repository.ErrNotFound)
+)
+
+const reviewColumns = `id, request_id, customer_id, provider_id, rating, comment, created_at, updated_at`
+
+type reviewRepository struct {
+	db *sql.DB
+}
+
+// NewReviewRepository creates a new PostgreSQL review repository
+func NewReviewRepository(db *sql.DB) repository.ReviewRepository {
+	return &reviewRepository{
+		db: db,
+	}
+}
+
+// Create saves a review and refreshes the provider's rating in the same transaction
+func (r *reviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	query := `
+		INSERT INTO reviews (id, request_id, customer_id, provider_id, rating, comment, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7)
+	`
+
+	now := time.Now()
+	review.CreatedAt = now
+	review.UpdatedAt = now
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query,
+		review.ID,
+		review.RequestID,
+		nullUUID(review.CustomerID),
+		nullUUID(review.ProviderID),
+		review.Rating,
+		nullString(review.Comment),
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create review: %w", mapError(err))
+	}
+
+	if err := refreshProviderRating(ctx, tx, review.ProviderID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *reviewRepository) GetByID(ctx context.Context, id string) (*domain.Review, error) {
+	query := `SELECT ` + reviewColumns + ` FROM reviews WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+func (r *reviewRepository) GetByRequestID(ctx context.Context, requestID string) (*domain.Review, error) {
+	query := `SELECT ` + reviewColumns + ` FROM reviews WHERE request_id = $1`
+	return r.get(ctx, query, requestID)
+}
+
+// GetByProviderID lists a provider's reviews, newest first
+func (r *reviewRepository) GetByProviderID(ctx context.Context, providerID string) ([]*domain.Review, error) {
+	query := `SELECT ` + reviewColumns + ` FROM reviews WHERE provider_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reviews: %w", err)
+	}
+	defer rows.Close()
+
+	var reviews []*domain.Review
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, rows.Err()
+}
+
+// Update saves a review's rating and comment and refreshes the provider's rating
+func (r *reviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	review.UpdatedAt = time.Now()
+	result, err := tx.ExecContext(ctx, `
+		UPDATE reviews SET rating = $2, comment = $3, updated_at = $4 WHERE id = $1
+	`, review.ID, review.Rating, nullString(review.Comment), review.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update review: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrReviewNotFound
+	}
+
+	if err := refreshProviderRating(ctx, tx, review.ProviderID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetAverageRating returns a provider's average rating and number of reviews
+func (r *reviewRepository) GetAverageRating(ctx context.Context, providerID string) (float64, int, error) {
+	var average float64
+	var count int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(AVG(rating), 0), COUNT(*) FROM reviews WHERE provider_id = $1
+	`, providerID).Scan(&average, &count)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get average rating: %w", err)
+	}
+
+	return average, count, nil
+}
+
+func (r *reviewRepository) get(ctx context.Context, query string, arg string) (*domain.Review, error) {
+	review, err := scanReview(r.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("failed to get review: %w", err)
+	}
+
+	return review, nil
+}
+
+// refreshProviderRating recomputes the denormalized rating and total_reviews of a provider
+func refreshProviderRating(ctx context.Context, tx *sql.Tx, providerID uuid.UUID) error {
+	if providerID == uuid.Nil {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE service_providers sp
+		SET rating = stats.average, total_reviews = stats.total
+		FROM (
+			SELECT COALESCE(ROUND(AVG(rating), 2), 0) AS average, COUNT(*) AS total
+			FROM reviews WHERE provider_id = $1
+		) stats
+		WHERE sp.id = $1
+	`, providerID)
+	if err != nil {
+		return fmt.Errorf("failed to refresh provider rating: %w", err)
+	}
+
+	return nil
+}
+
+func scanReview(row rowScanner) (*domain.Review, error) {
+	review := &domain.Review{}
+	var customerID, providerID uuid.NullUUID
+	var comment sql.NullString
+
+	err := row.Scan(
+		&review.ID,
+		&review.RequestID,
+		&customerID,
+		&providerID,
+		&review.Rating,
+		&comment,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	review.CustomerID = customerID.UUID
+	review.ProviderID = providerID.UUID
+	review.Comment = comment.String
+
+	return review, nil
+}
+
+func nullUUID(id uuid.UUID) uuid.NullUUID {
+	return uuid.NullUUID{UUID: id, Valid: id != uuid.Nil}
+}
@@ -0,0 +1,276 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrServiceProviderNotFound = fmt.Errorf("service provider %w", repository.ErrNotFound)
+)
+
+const serviceProviderColumns = `id, user_id, business_name, phone, address, latitude, longitude,
+		       is_verified, is_active, rating, total_reviews, reliability_score, provider_cancellations,
+		       created_at, updated_at`
+
+// searchLimit caps the number of providers or customers returned by a search
+const searchLimit = repository.SearchLimit
+
+type serviceProviderRepository struct {
+	db *sql.DB
+}
+
+// NewServiceProviderRepository creates a new PostgreSQL service provider repository
+func NewServiceProviderRepository(db *sql.DB) repository.ServiceProviderRepository {
+	return &serviceProviderRepository{
+		db: db,
+	}
+}
+
+func (r *serviceProviderRepository) Create(ctx context.Context, provider *domain.ServiceProvider) error {
+	query := `
+		INSERT INTO service_providers (id, user_id, business_name, phone, address, latitude, longitude, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	`
+
+	now := time.Now()
+	provider.CreatedAt = now
+	provider.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		provider.ID,
+		provider.UserID,
+		provider.BusinessName,
+		nullString(provider.Phone),
+		nullString(provider.Address),
+		provider.Latitude,
+		provider.Longitude,
+		provider.IsActive,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create service provider: %w", mapError(err))
+	}
+
+	return nil
+}
+
+func (r *serviceProviderRepository) GetByID(ctx context.Context, id string) (*domain.ServiceProvider, error) {
+	query := `SELECT ` + serviceProviderColumns + ` FROM service_providers WHERE id = $1`
+	return r.get(ctx, query, id)
+}
+
+func (r *serviceProviderRepository) GetByUserID(ctx context.Context, userID string) (*domain.ServiceProvider, error) {
+	query := `SELECT ` + serviceProviderColumns + ` FROM service_providers WHERE user_id = $1`
+	return r.get(ctx, query, userID)
+}
+
+// Update saves the profile fields a provider can edit. Verification and rating are
+// maintained elsewhere (by admins and by review writes).
+func (r *serviceProviderRepository) Update(ctx context.Context, provider *domain.ServiceProvider) error {
+	query := `
+		UPDATE service_providers
+		SET business_name = $2, phone = $3, address = $4, latitude = $5, longitude = $6,
+		    is_active = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	provider.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		provider.ID,
+		provider.BusinessName,
+		nullString(provider.Phone),
+		nullString(provider.Address),
+		provider.Latitude,
+		provider.Longitude,
+		provider.IsActive,
+		provider.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update service provider: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrServiceProviderNotFound
+	}
+
+	return nil
+}
+
+// Search finds active providers within radiusKm of a point, nearest first. With a
+// category, only providers offering an active service in it are returned.
+func (r *serviceProviderRepository) Search(ctx context.Context, lat, lng float64, radiusKm float64, category *domain.ServiceCategory) ([]*domain.ServiceProvider, error) {
+	// A bounding box lets the location index narrow the rows before the exact distance check
+	latDelta := radiusKm / 111.0
+	lngDelta := radiusKm / (111.0 * math.Max(math.Cos(lat*math.Pi/180), 0.01))
+
+	query := `
+		SELECT ` + serviceProviderColumns + ` FROM (
+			SELECT sp.*,
+			       6371 * acos(LEAST(1, cos(radians($1)) * cos(radians(sp.latitude)) * cos(radians(sp.longitude) - radians($2))
+			                         + sin(radians($1)) * sin(radians(sp.latitude)))) AS distance_km
+			FROM service_providers sp
+			WHERE sp.is_active = TRUE
+			  AND sp.latitude BETWEEN $1 - $4 AND $1 + $4
+			  AND sp.longitude BETWEEN $2 - $5 AND $2 + $5
+			  AND ($6::text IS NULL OR EXISTS (
+			      SELECT 1 FROM services s
+			      WHERE s.provider_id = sp.id AND s.is_active = TRUE AND s.category = $6))
+		) nearby
+		WHERE distance_km <= $3
+		ORDER BY distance_km
+		LIMIT $7
+	`
+
+	var categoryArg sql.NullString
+	if category != nil {
+		categoryArg = sql.NullString{String: string(*category), Valid: true}
+	}
+
+	return r.list(ctx, query, lat, lng, radiusKm, latDelta, lngDelta, categoryArg, searchLimit)
+}
+
+// GetAll lists active providers, best rated first
+func (r *serviceProviderRepository) GetAll(ctx context.Context, limit, offset int) ([]*domain.ServiceProvider, error) {
+	query := `SELECT ` + serviceProviderColumns + ` FROM service_providers
+		WHERE is_active = TRUE
+		ORDER BY rating DESC, total_reviews DESC
+		LIMIT $1 OFFSET $2`
+	return r.list(ctx, query, limit, offset)
+}
+
+// RefreshStats recomputes the rating, review count and reliability score of
+// every provider, as refreshProviderRating and refreshProviderReliability do
+// for one, and returns the IDs of the providers whose figures had drifted
+func (r *serviceProviderRepository) RefreshStats(ctx context.Context) ([]string, error) {
+	query := `
+		WITH review_stats AS (
+			SELECT provider_id, ROUND(AVG(rating), 2) AS average, COUNT(*) AS total
+			FROM reviews
+			GROUP BY provider_id
+		), booking_stats AS (
+			SELECT provider_id,
+			       COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+			       COUNT(*) FILTER (WHERE status = 'cancelled' AND cancelled_from = 'confirmed'
+			                          AND (cancelled_by = 'provider' OR cancellation_reason = 'no_show')) AS cancelled
+			FROM service_requests
+			GROUP BY provider_id
+		), stats AS (
+			SELECT sp.id,
+			       COALESCE(rs.average, 0) AS rating,
+			       COALESCE(rs.total, 0) AS total_reviews,
+			       CASE WHEN COALESCE(bs.completed, 0) + COALESCE(bs.cancelled, 0) = 0 THEN 100
+			            ELSE ROUND(100.0 * bs.completed / (bs.completed + bs.cancelled), 2) END AS reliability_score,
+			       COALESCE(bs.cancelled, 0) AS provider_cancellations
+			FROM service_providers sp
+			LEFT JOIN review_stats rs ON rs.provider_id = sp.id
+			LEFT JOIN booking_stats bs ON bs.provider_id = sp.id
+		)
+		UPDATE service_providers sp
+		SET rating = stats.rating, total_reviews = stats.total_reviews,
+		    reliability_score = stats.reliability_score, provider_cancellations = stats.provider_cancellations
+		FROM stats
+		WHERE sp.id = stats.id
+		  AND (sp.rating, sp.total_reviews, sp.reliability_score, sp.provider_cancellations)
+		      IS DISTINCT FROM (stats.rating, stats.total_reviews, stats.reliability_score, stats.provider_cancellations)
+		RETURNING sp.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh provider stats: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan provider id: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *serviceProviderRepository) get(ctx context.Context, query string, arg string) (*domain.ServiceProvider, error) {
+	provider, err := scanServiceProvider(r.db.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrServiceProviderNotFound
+		}
+		return nil, fmt.Errorf("failed to get service provider: %w", err)
+	}
+
+	return provider, nil
+}
+
+func (r *serviceProviderRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.ServiceProvider, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service providers: %w", err)
+	}
+	defer rows.Close()
+
+	var providers []*domain.ServiceProvider
+	for rows.Next() {
+		provider, err := scanServiceProvider(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service provider: %w", err)
+		}
+		providers = append(providers, provider)
+	}
+
+	return providers, rows.Err()
+}
+
+func scanServiceProvider(row rowScanner) (*domain.ServiceProvider, error) {
+	provider := &domain.ServiceProvider{}
+	var phone, address sql.NullString
+	var latitude, longitude, rating sql.NullFloat64
+	var isVerified, isActive sql.NullBool
+	var totalReviews sql.NullInt64
+	var reliabilityScore sql.NullFloat64
+	var providerCancellations sql.NullInt64
+
+	err := row.Scan(
+		&provider.ID,
+		&provider.UserID,
+		&provider.BusinessName,
+		&phone,
+		&address,
+		&latitude,
+		&longitude,
+		&isVerified,
+		&isActive,
+		&rating,
+		&totalReviews,
+		&reliabilityScore,
+		&providerCancellations,
+		&provider.CreatedAt,
+		&provider.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider.Phone = phone.String
+	provider.Address = address.String
+	provider.Latitude = latitude.Float64
+	provider.Longitude = longitude.Float64
+	provider.IsVerified = isVerified.Bool
+	provider.IsActive = isActive.Bool
+	provider.Rating = rating.Float64
+	provider.TotalReviews = int(totalReviews.Int64)
+	provider.ReliabilityScore = reliabilityScore.Float64
+	provider.ProviderCancellations = int(providerCancellations.Int64)
+
+	return provider, nil
+}
@@ -0,0 +1,160 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrServiceNotFound = fmt.Errorf("service %w", repository.ErrNotFound)
+)
+
+const serviceColumns = `id, provider_id, category, name, description, price, duration, is_active, created_at, updated_at`
+
+type serviceRepository struct {
+	db *sql.DB
+}
+
+// NewServiceRepository creates a new PostgreSQL service repository
+func NewServiceRepository(db *sql.DB) repository.ServiceRepository {
+	return &serviceRepository{
+		db: db,
+	}
+}
+
+func (r *serviceRepository) Create(ctx context.Context, service *domain.Service) error {
+	query := `
+		INSERT INTO services (id, provider_id, category, name, description, price, duration, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+	`
+
+	now := time.Now()
+	service.CreatedAt = now
+	service.UpdatedAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		service.ID,
+		service.ProviderID,
+		service.Category,
+		service.Name,
+		nullString(service.Description),
+		service.Price,
+		service.Duration,
+		service.IsActive,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create service: %w", mapError(err))
+	}
+
+	return nil
+}
+
+func (r *serviceRepository) GetByID(ctx context.Context, id string) (*domain.Service, error) {
+	query := `SELECT ` + serviceColumns + ` FROM services WHERE id = $1`
+
+	service, err := scanService(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrServiceNotFound
+		}
+		return nil, fmt.Errorf("failed to get service: %w", err)
+	}
+
+	return service, nil
+}
+
+// GetByProviderID lists all of a provider's services, active or not
+func (r *serviceRepository) GetByProviderID(ctx context.Context, providerID string) ([]*domain.Service, error) {
+	query := `SELECT ` + serviceColumns + ` FROM services WHERE provider_id = $1 ORDER BY category, name`
+
+	rows, err := r.db.QueryContext(ctx, query, providerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services: %w", err)
+	}
+	defer rows.Close()
+
+	var services []*domain.Service
+	for rows.Next() {
+		service, err := scanService(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service: %w", err)
+		}
+		services = append(services, service)
+	}
+
+	return services, rows.Err()
+}
+
+func (r *serviceRepository) Update(ctx context.Context, service *domain.Service) error {
+	query := `
+		UPDATE services
+		SET category = $2, name = $3, description = $4, price = $5, duration = $6, is_active = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	service.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		service.ID,
+		service.Category,
+		service.Name,
+		nullString(service.Description),
+		service.Price,
+		service.Duration,
+		service.IsActive,
+		service.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update service: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrServiceNotFound
+	}
+
+	return nil
+}
+
+func (r *serviceRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM services WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete service: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrServiceNotFound
+	}
+
+	return nil
+}
+
+func scanService(row rowScanner) (*domain.Service, error) {
+	service := &domain.Service{}
+	var description sql.NullString
+	var isActive sql.NullBool
+
+	err := row.Scan(
+		&service.ID,
+		&service.ProviderID,
+		&service.Category,
+		&service.Name,
+		&description,
+		&service.Price,
+		&service.Duration,
+		&isActive,
+		&service.CreatedAt,
+		&service.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	service.Description = description.String
+	service.IsActive = isActive.Bool
+
+	return service, nil
+}
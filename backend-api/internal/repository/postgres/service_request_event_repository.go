@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+type serviceRequestEventRepository struct {
+	db *sql.DB
+}
+
+// NewServiceRequestEventRepository creates a new PostgreSQL service request event repository.
+// Events of changes made through other repositories are written by them, in the same transaction.
+func NewServiceRequestEventRepository(db *sql.DB) repository.ServiceRequestEventRepository {
+	return &serviceRequestEventRepository{
+		db: db,
+	}
+}
+
+func (r *serviceRequestEventRepository) Create(ctx context.Context, event *domain.ServiceRequestEvent) error {
+	return insertServiceRequestEvent(ctx, r.db, event)
+}
+
+// ListByRequestID lists a booking's events, oldest first
+func (r *serviceRequestEventRepository) ListByRequestID(ctx context.Context, requestID string) ([]*domain.ServiceRequestEvent, error) {
+	query := `
+		SELECT id, request_id, type, actor, actor_user_id, from_status, to_status, note, metadata, created_at
+		FROM service_request_events
+		WHERE request_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service request events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []*domain.ServiceRequestEvent{}
+	for rows.Next() {
+		event := &domain.ServiceRequestEvent{}
+		var actorUserID uuid.NullUUID
+		var fromStatus, toStatus, note sql.NullString
+		var metadata []byte
+
+		err := rows.Scan(
+			&event.ID,
+			&event.RequestID,
+			&event.Type,
+			&event.Actor,
+			&actorUserID,
+			&fromStatus,
+			&toStatus,
+			&note,
+			&metadata,
+			&event.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service request event: %w", err)
+		}
+
+		event.ActorUserID = actorUserID.UUID
+		event.FromStatus = domain.RequestStatus(fromStatus.String)
+		event.ToStatus = domain.RequestStatus(toStatus.String)
+		event.Note = note.String
+		if err := json.Unmarshal(metadata, &event.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode service request event metadata: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// GetAnalytics follows the bookings requested from `from` until `to` through
+// all of their later events
+func (r *serviceRequestEventRepository) GetAnalytics(ctx context.Context, providerID string, from, to time.Time) (*domain.BookingAnalytics, error) {
+	query := `
+		WITH cohort AS (
+			SELECT e.request_id, e.created_at AS requested_at
+			FROM service_request_events e
+			JOIN service_requests r ON r.id = e.request_id
+			WHERE e.type = 'status_changed' AND e.to_status = 'requested'
+			  AND e.created_at >= $2 AND e.created_at < $3
+			  AND ($1::uuid IS NULL OR r.provider_id = $1::uuid)
+		), events AS (
+			SELECT e.* FROM service_request_events e JOIN cohort c ON c.request_id = e.request_id
+		), answers AS (
+			SELECT request_id, MIN(created_at) AS answered_at
+			FROM events
+			WHERE type = 'status_changed' AND from_status = 'requested' AND actor = 'provider'
+			  AND to_status IN ('confirmed', 'cancelled')
+			GROUP BY request_id
+		)
+		SELECT
+			(SELECT COUNT(*) FROM cohort),
+			COUNT(*) FILTER (WHERE type = 'status_changed' AND to_status = 'confirmed'),
+			COUNT(*) FILTER (WHERE type = 'status_changed' AND to_status = 'cancelled' AND actor = 'provider' AND from_status = 'requested'),
+			COUNT(*) FILTER (WHERE type = 'status_changed' AND to_status = 'completed'),
+			COUNT(*) FILTER (WHERE type = 'status_changed' AND to_status = 'cancelled' AND actor = 'customer'),
+			COUNT(*) FILTER (WHERE type = 'status_changed' AND to_status = 'cancelled' AND actor = 'provider' AND from_status = 'confirmed'),
+			COUNT(*) FILTER (WHERE type = 'status_changed' AND to_status = 'cancelled' AND actor = 'system'),
+			COUNT(*) FILTER (WHERE type IN ('reschedule_proposed', 'reschedule_countered')),
+			COUNT(*) FILTER (WHERE type = 'reschedule_accepted'),
+			COUNT(*) FILTER (WHERE type = 'price_changed'),
+			COALESCE(SUM((metadata->>'fee')::numeric) FILTER (WHERE type = 'status_changed' AND to_status = 'cancelled'), 0),
+			(SELECT AVG(EXTRACT(EPOCH FROM a.answered_at - c.requested_at)) / 60
+			 FROM answers a JOIN cohort c ON c.request_id = a.request_id)
+		FROM events
+	`
+
+	analytics := &domain.BookingAnalytics{From: from, To: to}
+	var averageResponse sql.NullFloat64
+	err := r.db.QueryRowContext(ctx, query, nullString(providerID), from, to).Scan(
+		&analytics.Requested,
+		&analytics.Confirmed,
+		&analytics.Declined,
+		&analytics.Completed,
+		&analytics.CancelledByCustomer,
+		&analytics.CancelledByProvider,
+		&analytics.CancelledBySystem,
+		&analytics.NoShows,
+		&analytics.FlaggedOverdue,
+		&analytics.RescheduleProposals,
+		&analytics.ReschedulesAccepted,
+		&analytics.PriceChanges,
+		&analytics.CancellationFees,
+		&averageResponse,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute booking analytics: %w", err)
+	}
+	if averageResponse.Valid {
+		analytics.AverageResponseMinutes = &averageResponse.Float64
+	}
+
+	return analytics, nil
+}
+
+// insertServiceRequestEvent appends an event, in tx when called from another
+// repository's transaction
+func insertServiceRequestEvent(ctx context.Context, db execer, event *domain.ServiceRequestEvent) error {
+	query := `
+		INSERT INTO service_request_events (id, request_id, type, actor, actor_user_id, from_status, to_status,
+		                                    note, metadata, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	metadata := event.Metadata
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return fmt.Errorf("failed to encode service request event metadata: %w", err)
+	}
+
+	_, err = db.ExecContext(ctx, query,
+		event.ID,
+		event.RequestID,
+		event.Type,
+		event.Actor,
+		nullUUID(event.ActorUserID),
+		nullString(string(event.FromStatus)),
+		nullString(string(event.ToStatus)),
+		nullString(event.Note),
+		metadataJSON,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record service request event: %w", err)
+	}
+
+	return nil
+}
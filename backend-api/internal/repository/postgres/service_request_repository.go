@@ -0,0 +1,556 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrServiceRequestNotFound      = fmt.Errorf("service request %w", repository.ErrNotFound)
+	ErrServiceRequestStatusChanged = fmt.Errorf("service request status %w", repository.ErrConflict)
+)
+
+const serviceRequestColumns = `id, customer_id, provider_id, service_id, status, requested_date, scheduled_date,
+		       address, notes, service_name, quoted_price, currency, duration_minutes, estimated_end_date,
+		       cancellation_policy, cancelled_at, cancelled_by, cancelled_by_user_id, cancelled_from,
+		       cancellation_reason, cancellation_note, cancellation_fee, overdue_at, created_at, updated_at`
+
+type serviceRequestRepository struct {
+	db *sql.DB
+}
+
+// NewServiceRequestRepository creates a new PostgreSQL service request repository
+func NewServiceRequestRepository(db *sql.DB) repository.ServiceRequestRepository {
+	return &serviceRequestRepository{
+		db: db,
+	}
+}
+
+func (r *serviceRequestRepository) Create(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error {
+	query := `
+		INSERT INTO service_requests (id, customer_id, provider_id, service_id, status, requested_date, scheduled_date,
+		                              address, notes, service_name, quoted_price, currency, duration_minutes,
+		                              estimated_end_date, cancellation_policy, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $16)
+	`
+
+	policy, err := json.Marshal(cancellationPolicy(request.CancellationPolicy))
+	if err != nil {
+		return fmt.Errorf("failed to encode cancellation policy: %w", err)
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	request.CreatedAt = now
+	request.UpdatedAt = now
+
+	_, err = tx.ExecContext(ctx, query,
+		request.ID,
+		nullUUID(request.CustomerID),
+		nullUUID(request.ProviderID),
+		nullUUID(request.ServiceID),
+		request.Status,
+		request.RequestedDate,
+		request.ScheduledDate,
+		request.Address,
+		nullString(request.Notes),
+		nullString(request.ServiceName),
+		request.QuotedPrice,
+		request.Currency,
+		request.DurationMinutes,
+		request.EstimatedEndDate,
+		policy,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create service request: %w", mapError(err))
+	}
+
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *serviceRequestRepository) GetByID(ctx context.Context, id string) (*domain.ServiceRequest, error) {
+	query := `SELECT ` + serviceRequestColumns + ` FROM service_requests WHERE id = $1`
+
+	request, err := scanServiceRequest(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrServiceRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to get service request: %w", err)
+	}
+
+	return request, nil
+}
+
+// GetByCustomerID lists a customer's requests, latest first
+func (r *serviceRequestRepository) GetByCustomerID(ctx context.Context, customerID string) ([]*domain.ServiceRequest, error) {
+	query := `SELECT ` + serviceRequestColumns + ` FROM service_requests
+		WHERE customer_id = $1
+		ORDER BY COALESCE(scheduled_date, requested_date) DESC`
+	return r.list(ctx, query, customerID)
+}
+
+// GetByProviderID lists a provider's requests, latest first
+func (r *serviceRequestRepository) GetByProviderID(ctx context.Context, providerID string) ([]*domain.ServiceRequest, error) {
+	query := `SELECT ` + serviceRequestColumns + ` FROM service_requests
+		WHERE provider_id = $1
+		ORDER BY COALESCE(scheduled_date, requested_date) DESC`
+	return r.list(ctx, query, providerID)
+}
+
+func (r *serviceRequestRepository) Update(ctx context.Context, request *domain.ServiceRequest) error {
+	query := `
+		UPDATE service_requests
+		SET status = $2, requested_date = $3, scheduled_date = $4, address = $5, notes = $6,
+		    quoted_price = $7, estimated_end_date = $8, updated_at = $9
+		WHERE id = $1
+	`
+
+	request.UpdatedAt = time.Now()
+	result, err := r.db.ExecContext(ctx, query,
+		request.ID,
+		request.Status,
+		request.RequestedDate,
+		request.ScheduledDate,
+		request.Address,
+		nullString(request.Notes),
+		request.QuotedPrice,
+		request.EstimatedEndDate,
+		request.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update service request: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrServiceRequestNotFound
+	}
+
+	return nil
+}
+
+// ChangePrice updates a request's quoted price, failing with ErrConflict if its
+// status has changed
+func (r *serviceRequestRepository) ChangePrice(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error {
+	query := `UPDATE service_requests SET quoted_price = $3, updated_at = $4 WHERE id = $1 AND status = $2`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	request.UpdatedAt = time.Now()
+	result, err := tx.ExecContext(ctx, query, request.ID, request.Status, request.QuotedPrice, request.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to change service request price: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to change service request price: %w", err)
+	}
+	if affected == 0 {
+		return ErrServiceRequestStatusChanged
+	}
+
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// HasOverlapping reports whether a provider has a confirmed booking, other than
+// excludeID, that overlaps the period from start to end
+func (r *serviceRequestRepository) HasOverlapping(ctx context.Context, providerID, excludeID string, start, end time.Time) (bool, error) {
+	return hasOverlapping(ctx, r.db, providerID, excludeID, start, end)
+}
+
+func (r *serviceRequestRepository) Confirm(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error {
+	query := `
+		UPDATE service_requests
+		SET status = $3, scheduled_date = $4, estimated_end_date = $5, updated_at = $6
+		WHERE id = $1 AND status = $2
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := reserveSchedule(ctx, tx, request); err != nil {
+		return err
+	}
+
+	updatedAt := time.Now()
+	result, err := tx.ExecContext(ctx, query, request.ID, request.Status, domain.StatusConfirmed, request.ScheduledDate, request.EstimatedEndDate, updatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to confirm service request: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to confirm service request: %w", err)
+	}
+	if affected == 0 {
+		return ErrServiceRequestStatusChanged
+	}
+
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to confirm service request: %w", err)
+	}
+	request.Status = domain.StatusConfirmed
+	request.UpdatedAt = updatedAt
+
+	return nil
+}
+
+// UpdateStatus also refreshes the provider's reliability score when a booking
+// is completed, and expires its pending reschedule proposal once it is closed
+func (r *serviceRequestRepository) UpdateStatus(ctx context.Context, id string, from, to domain.RequestStatus, event *domain.ServiceRequestEvent) error {
+	query := `UPDATE service_requests SET status = $3, updated_at = $4 WHERE id = $1 AND status = $2 RETURNING provider_id`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var providerID uuid.NullUUID
+	if err := tx.QueryRowContext(ctx, query, id, from, to, time.Now()).Scan(&providerID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrServiceRequestStatusChanged
+		}
+		return fmt.Errorf("failed to update service request status: %w", err)
+	}
+
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+	if to == domain.StatusCompleted {
+		if err := refreshProviderReliability(ctx, tx, providerID.UUID); err != nil {
+			return err
+		}
+	}
+	if to == domain.StatusCompleted || to == domain.StatusCancelled {
+		if err := expireRescheduleProposals(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Cancel also refreshes the provider's reliability score and expires the
+// booking's pending reschedule proposal
+func (r *serviceRequestRepository) Cancel(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error {
+	query := `
+		UPDATE service_requests
+		SET status = $3, cancelled_at = $4, cancelled_by = $5, cancelled_by_user_id = $6, cancelled_from = $2,
+		    cancellation_reason = $7, cancellation_note = $8, cancellation_fee = $9, updated_at = $4
+		WHERE id = $1 AND status = $2
+	`
+
+	cancellation := request.Cancellation
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query,
+		request.ID,
+		cancellation.From,
+		domain.StatusCancelled,
+		cancellation.At,
+		cancellation.By,
+		nullUUID(cancellation.ByUserID),
+		cancellation.Reason,
+		nullString(cancellation.Note),
+		cancellation.Fee,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to cancel service request: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to cancel service request: %w", err)
+	}
+	if affected == 0 {
+		return ErrServiceRequestStatusChanged
+	}
+
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+	if err := refreshProviderReliability(ctx, tx, request.ProviderID); err != nil {
+		return err
+	}
+	if err := expireRescheduleProposals(ctx, tx, request.ID.String()); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to cancel service request: %w", err)
+	}
+	request.Status = domain.StatusCancelled
+	request.UpdatedAt = cancellation.At
+
+	return nil
+}
+
+// ListExpiring lists up to limit requests still awaiting the provider that
+// were made before createdBefore or were to start before startBefore, oldest first
+func (r *serviceRequestRepository) ListExpiring(ctx context.Context, createdBefore, startBefore time.Time, limit int) ([]*domain.ServiceRequest, error) {
+	query := `SELECT ` + serviceRequestColumns + ` FROM service_requests
+		WHERE status = 'requested' AND (created_at < $1 OR requested_date < $2)
+		ORDER BY created_at
+		LIMIT $3`
+	return r.list(ctx, query, createdBefore, startBefore, limit)
+}
+
+// ListOverdue lists up to limit confirmed bookings, not yet flagged, that
+// ended before endedBefore, oldest first
+func (r *serviceRequestRepository) ListOverdue(ctx context.Context, endedBefore time.Time, limit int) ([]*domain.ServiceRequest, error) {
+	query := `SELECT ` + serviceRequestColumns + ` FROM service_requests
+		WHERE status = 'confirmed' AND overdue_at IS NULL
+		  AND COALESCE(estimated_end_date, scheduled_date, requested_date) < $1
+		ORDER BY COALESCE(estimated_end_date, scheduled_date, requested_date)
+		LIMIT $2`
+	return r.list(ctx, query, endedBefore, limit)
+}
+
+// FlagOverdue records request.OverdueAt, failing with ErrConflict if the
+// booking is no longer confirmed or was already flagged
+func (r *serviceRequestRepository) FlagOverdue(ctx context.Context, request *domain.ServiceRequest, event *domain.ServiceRequestEvent) error {
+	query := `
+		UPDATE service_requests SET overdue_at = $2, updated_at = $2
+		WHERE id = $1 AND status = 'confirmed' AND overdue_at IS NULL
+	`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, request.ID, request.OverdueAt)
+	if err != nil {
+		return fmt.Errorf("failed to flag service request: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to flag service request: %w", err)
+	}
+	if affected == 0 {
+		return ErrServiceRequestStatusChanged
+	}
+
+	if err := insertServiceRequestEvent(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to flag service request: %w", err)
+	}
+	request.UpdatedAt = *request.OverdueAt
+
+	return nil
+}
+
+func (r *serviceRequestRepository) list(ctx context.Context, query string, args ...interface{}) ([]*domain.ServiceRequest, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list service requests: %w", err)
+	}
+	defer rows.Close()
+
+	var requests []*domain.ServiceRequest
+	for rows.Next() {
+		request, err := scanServiceRequest(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan service request: %w", err)
+		}
+		requests = append(requests, request)
+	}
+
+	return requests, rows.Err()
+}
+
+func scanServiceRequest(row rowScanner) (*domain.ServiceRequest, error) {
+	request := &domain.ServiceRequest{}
+	var customerID, providerID, serviceID uuid.NullUUID
+	var scheduledDate, estimatedEndDate sql.NullTime
+	var notes, serviceName sql.NullString
+	var quotedPrice, cancellationFee sql.NullFloat64
+	var durationMinutes sql.NullInt64
+	var policy []byte
+	var cancelledAt, overdueAt sql.NullTime
+	var cancelledBy, cancelledFrom, cancellationReason, cancellationNote sql.NullString
+	var cancelledByUserID uuid.NullUUID
+
+	err := row.Scan(
+		&request.ID,
+		&customerID,
+		&providerID,
+		&serviceID,
+		&request.Status,
+		&request.RequestedDate,
+		&scheduledDate,
+		&request.Address,
+		&notes,
+		&serviceName,
+		&quotedPrice,
+		&request.Currency,
+		&durationMinutes,
+		&estimatedEndDate,
+		&policy,
+		&cancelledAt,
+		&cancelledBy,
+		&cancelledByUserID,
+		&cancelledFrom,
+		&cancellationReason,
+		&cancellationNote,
+		&cancellationFee,
+		&overdueAt,
+		&request.CreatedAt,
+		&request.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	request.CustomerID = customerID.UUID
+	request.ProviderID = providerID.UUID
+	request.ServiceID = serviceID.UUID
+	if scheduledDate.Valid {
+		request.ScheduledDate = &scheduledDate.Time
+	}
+	request.Notes = notes.String
+	request.ServiceName = serviceName.String
+	request.QuotedPrice = quotedPrice.Float64
+	request.DurationMinutes = int(durationMinutes.Int64)
+	if estimatedEndDate.Valid {
+		request.EstimatedEndDate = &estimatedEndDate.Time
+	}
+	if err := json.Unmarshal(policy, &request.CancellationPolicy); err != nil {
+		return nil, fmt.Errorf("failed to decode cancellation policy: %w", err)
+	}
+	if cancelledAt.Valid {
+		request.Cancellation = &domain.Cancellation{
+			At:       cancelledAt.Time,
+			By:       domain.CancellationActor(cancelledBy.String),
+			ByUserID: cancelledByUserID.UUID,
+			From:     domain.RequestStatus(cancelledFrom.String),
+			Reason:   domain.CancellationReason(cancellationReason.String),
+			Note:     cancellationNote.String,
+			Fee:      cancellationFee.Float64,
+		}
+	}
+	if overdueAt.Valid {
+		request.OverdueAt = &overdueAt.Time
+	}
+
+	return request, nil
+}
+
+// cancellationPolicy stores a missing policy as an empty list rather than null
+func cancellationPolicy(policy domain.CancellationPolicy) domain.CancellationPolicy {
+	if policy == nil {
+		return domain.CancellationPolicy{}
+	}
+	return policy
+}
+
+// reserveSchedule locks the request's provider and fails with ErrOverlapping if
+// the provider has another confirmed booking during the request's period. The
+// lock serializes every change to a provider's confirmed schedule, so two
+// transactions cannot each miss the booking the other is confirming.
+func reserveSchedule(ctx context.Context, tx *sql.Tx, request *domain.ServiceRequest) error {
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM service_providers WHERE id = $1 FOR UPDATE`, request.ProviderID); err != nil {
+		return fmt.Errorf("failed to lock service provider: %w", err)
+	}
+
+	overlapping, err := hasOverlapping(ctx, tx, request.ProviderID.String(), request.ID.String(), request.StartDate(), *request.EstimatedEndDate)
+	if err != nil {
+		return err
+	}
+	if overlapping {
+		return fmt.Errorf("service request schedule: %w", repository.ErrOverlapping)
+	}
+
+	return nil
+}
+
+// queryRower is implemented by both *sql.DB and *sql.Tx
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func hasOverlapping(ctx context.Context, db queryRower, providerID, excludeID string, start, end time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM service_requests
+			WHERE provider_id = $1 AND id <> $2 AND status = 'confirmed'
+			  AND COALESCE(scheduled_date, requested_date) < $4
+			  AND COALESCE(estimated_end_date, scheduled_date, requested_date) > $3
+		)
+	`
+
+	var exists bool
+	if err := db.QueryRowContext(ctx, query, providerID, excludeID, start, end).Scan(&exists); err != nil {
+		return false, fmt.Errorf("failed to check overlapping service requests: %w", err)
+	}
+
+	return exists, nil
+}
+
+// refreshProviderReliability recomputes a provider's reliability score: the
+// percentage of its bookings that were completed rather than cancelled by the
+// provider after confirming them or missed, as reported by the customer.
+// Declined and expired requests do not count against it.
+func refreshProviderReliability(ctx context.Context, tx *sql.Tx, providerID uuid.UUID) error {
+	if providerID == uuid.Nil {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		UPDATE service_providers sp
+		SET reliability_score = CASE WHEN stats.completed + stats.cancelled = 0 THEN 100
+		                             ELSE ROUND(100.0 * stats.completed / (stats.completed + stats.cancelled), 2) END,
+		    provider_cancellations = stats.cancelled
+		FROM (
+			SELECT COUNT(*) FILTER (WHERE status = 'completed') AS completed,
+			       COUNT(*) FILTER (WHERE status = 'cancelled' AND cancelled_from = 'confirmed'
+			                          AND (cancelled_by = 'provider' OR cancellation_reason = 'no_show')) AS cancelled
+			FROM service_requests WHERE provider_id = $1
+		) stats
+		WHERE sp.id = $1
+	`, providerID)
+	if err != nil {
+		return fmt.Errorf("failed to refresh provider reliability: %w", err)
+	}
+
+	return nil
+}
@@ -9,21 +9,26 @@ import (
 
 	"karigar-backend/internal/domain"
 	"karigar-backend/internal/repository"
-	"karigar-backend/pkg/database"
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound = fmt.Errorf("user %w", repository.ErrNotFound)
 )
 
+// userColumns is the column list shared by all user SELECT queries; keep it in sync with scanUser
+const userColumns = `id, email, password, role, is_email_verified, email_verify_token, email_verify_expiry,
+		       password_reset_token, password_reset_expiry, phone, is_phone_verified, phone_verified_at,
+		       mfa_enabled, mfa_secret, mfa_enrolled_at, mfa_last_used_step, token_version,
+		       deletion_requested_at, deletion_scheduled_at, created_at, updated_at`
+
 type userRepository struct {
 	db *sql.DB
 }
 
 // NewUserRepository creates a new PostgreSQL user repository
-func NewUserRepository() repository.UserRepository {
+func NewUserRepository(db *sql.DB) repository.UserRepository {
 	return &userRepository{
-		db: database.GetDB(),
+		db: db,
 	}
 }
 
@@ -47,87 +52,30 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return fmt.Errorf("failed to create user: %w", mapError(err))
 	}
 
 	return nil
 }
 
 func (r *userRepository) GetByID(ctx context.Context, id string) (*domain.User, error) {
-	query := `
-		SELECT id, email, password, role, is_email_verified, email_verify_token, email_verify_expiry, 
-		       password_reset_token, password_reset_expiry, created_at, updated_at
-		FROM users
-		WHERE id = $1
-	`
-
-	user := &domain.User{}
-	var emailVerifyToken, passwordResetToken sql.NullString
-	var emailVerifyExpiry, passwordResetExpiry sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.Role,
-		&user.IsEmailVerified,
-		&emailVerifyToken,
-		&emailVerifyExpiry,
-		&passwordResetToken,
-		&passwordResetExpiry,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrUserNotFound
 		}
-		return nil, fmt.Errorf("failed to get user by email: %w", err)
-	}
-
-	if emailVerifyToken.Valid {
-		user.EmailVerifyToken = &emailVerifyToken.String
-	}
-	if emailVerifyExpiry.Valid {
-		user.EmailVerifyExpiry = &emailVerifyExpiry.Time
-	}
-	if passwordResetToken.Valid {
-		user.PasswordResetToken = &passwordResetToken.String
-	}
-	if passwordResetExpiry.Valid {
-		user.PasswordResetExpiry = &passwordResetExpiry.Time
+		return nil, fmt.Errorf("failed to get user by id: %w", err)
 	}
 
 	return user, nil
 }
 
 func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `
-		SELECT id, email, password, role, is_email_verified, email_verify_token, email_verify_expiry,
-		       password_reset_token, password_reset_expiry, created_at, updated_at
-		FROM users
-		WHERE email = $1
-	`
-
-	user := &domain.User{}
-	var emailVerifyToken, passwordResetToken sql.NullString
-	var emailVerifyExpiry, passwordResetExpiry sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.Role,
-		&user.IsEmailVerified,
-		&emailVerifyToken,
-		&emailVerifyExpiry,
-		&passwordResetToken,
-		&passwordResetExpiry,
-		&user.CreatedAt,
-		&user.UpdatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrUserNotFound
@@ -135,17 +83,19 @@ func (r *userRepository) GetByEmail(ctx context.Context, email string) (*domain.
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
 
-	if emailVerifyToken.Valid {
-		user.EmailVerifyToken = &emailVerifyToken.String
-	}
-	if emailVerifyExpiry.Valid {
-		user.EmailVerifyExpiry = &emailVerifyExpiry.Time
-	}
-	if passwordResetToken.Valid {
-		user.PasswordResetToken = &passwordResetToken.String
-	}
-	if passwordResetExpiry.Valid {
-		user.PasswordResetExpiry = &passwordResetExpiry.Time
+	return user, nil
+}
+
+// GetByVerifiedPhone gets a user by a verified E.164 phone number
+func (r *userRepository) GetByVerifiedPhone(ctx context.Context, phone string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE phone = $1 AND is_phone_verified = TRUE`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, phone))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user by phone: %w", err)
 	}
 
 	return user, nil
@@ -157,7 +107,7 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 		SET email = $2, password = $3, role = $4, is_email_verified = $5,
 		    email_verify_token = $6, email_verify_expiry = $7,
 		    password_reset_token = $8, password_reset_expiry = $9,
-		    updated_at = $10
+		    token_version = $10, updated_at = $11
 		WHERE id = $1
 	`
 
@@ -171,63 +121,161 @@ func (r *userRepository) Update(ctx context.Context, user *domain.User) error {
 		user.EmailVerifyExpiry,
 		user.PasswordResetToken,
 		user.PasswordResetExpiry,
+		user.TokenVersion,
 		time.Now(),
 	)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", mapError(err))
+	}
 
-	return err
+	return nil
 }
 
-func (r *userRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM users WHERE id = $1`
-	_, err := r.db.ExecContext(ctx, query, id)
-	return err
+// SetVerifiedPhone marks a phone number as verified for a user and copies it
+// to the user's customer or service provider profile
+func (r *userRepository) SetVerifiedPhone(ctx context.Context, userID, phone string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		UPDATE users
+		SET phone = $2, is_phone_verified = TRUE, phone_verified_at = $3, updated_at = $3
+		WHERE id = $1
+	`, userID, phone, now)
+	if err != nil {
+		return fmt.Errorf("failed to set verified phone: %w", mapError(err))
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE customers SET phone = $2 WHERE user_id = $1`, userID, phone); err != nil {
+		return fmt.Errorf("failed to update customer phone: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE service_providers SET phone = $2 WHERE user_id = $1`, userID, phone); err != nil {
+		return fmt.Errorf("failed to update service provider phone: %w", err)
+	}
+
+	return tx.Commit()
 }
 
-// GetByEmailVerifyToken gets a user by email verification token
-func (r *userRepository) GetByEmailVerifyToken(ctx context.Context, token string) (*domain.User, error) {
+// UpdateMFA updates the user's MFA enrolment state
+func (r *userRepository) UpdateMFA(ctx context.Context, user *domain.User) error {
 	query := `
-		SELECT id, email, password, role, is_email_verified, email_verify_token, email_verify_expiry,
-		       password_reset_token, password_reset_expiry, created_at, updated_at
-		FROM users
-		WHERE email_verify_token = $1
+		UPDATE users
+		SET mfa_enabled = $2, mfa_secret = $3, mfa_enrolled_at = $4, mfa_last_used_step = $5, updated_at = $6
+		WHERE id = $1
 	`
 
-	user := &domain.User{}
-	var emailVerifyToken, passwordResetToken sql.NullString
-	var emailVerifyExpiry, passwordResetExpiry sql.NullTime
-
-	err := r.db.QueryRowContext(ctx, query, token).Scan(
-		&user.ID,
-		&user.Email,
-		&user.Password,
-		&user.Role,
-		&user.IsEmailVerified,
-		&emailVerifyToken,
-		&emailVerifyExpiry,
-		&passwordResetToken,
-		&passwordResetExpiry,
-		&user.CreatedAt,
-		&user.UpdatedAt,
+	_, err := r.db.ExecContext(ctx, query,
+		user.ID,
+		user.MFAEnabled,
+		user.MFASecret,
+		user.MFAEnrolledAt,
+		user.MFALastUsedStep,
+		time.Now(),
 	)
+	if err != nil {
+		return fmt.Errorf("failed to update user MFA: %w", err)
+	}
 
+	return nil
+}
+
+// RecordMFAStep stores the last accepted TOTP time step. It returns false if the
+// step (or a later one) was already used, so a code cannot be replayed.
+func (r *userRepository) RecordMFAStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query := `
+		UPDATE users
+		SET mfa_last_used_step = $2
+		WHERE id = $1 AND (mfa_last_used_step IS NULL OR mfa_last_used_step < $2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, step)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("invalid verification token")
-		}
-		return nil, err
+		return false, fmt.Errorf("failed to record MFA step: %w", err)
 	}
 
-	if emailVerifyToken.Valid {
-		user.EmailVerifyToken = &emailVerifyToken.String
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
 	}
-	if emailVerifyExpiry.Valid {
-		user.EmailVerifyExpiry = &emailVerifyExpiry.Time
+
+	return rows == 1, nil
+}
+
+// ScheduleDeletion marks a user's account for deletion at the end of a grace period
+func (r *userRepository) ScheduleDeletion(ctx context.Context, id string, requestedAt, scheduledAt time.Time) error {
+	query := `
+		UPDATE users
+		SET deletion_requested_at = $2, deletion_scheduled_at = $3, updated_at = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, requestedAt, scheduledAt); err != nil {
+		return fmt.Errorf("failed to schedule user deletion: %w", err)
 	}
-	if passwordResetToken.Valid {
-		user.PasswordResetToken = &passwordResetToken.String
+
+	return nil
+}
+
+// CancelDeletion clears a scheduled account deletion
+func (r *userRepository) CancelDeletion(ctx context.Context, id string) error {
+	query := `
+		UPDATE users
+		SET deletion_requested_at = NULL, deletion_scheduled_at = NULL, updated_at = $2
+		WHERE id = $1
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, id, time.Now()); err != nil {
+		return fmt.Errorf("failed to cancel user deletion: %w", err)
 	}
-	if passwordResetExpiry.Valid {
-		user.PasswordResetExpiry = &passwordResetExpiry.Time
+
+	return nil
+}
+
+// ListDueForDeletion lists users whose deletion grace period ended before the given time
+func (r *userRepository) ListDueForDeletion(ctx context.Context, before time.Time, limit int) ([]*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users
+		WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= $1
+		ORDER BY deletion_scheduled_at
+		LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users due for deletion: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*domain.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM users WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// GetByEmailVerifyToken gets a user by email verification token
+func (r *userRepository) GetByEmailVerifyToken(ctx context.Context, token string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE email_verify_token = $1`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("verification token %w", repository.ErrNotFound)
+		}
+		return nil, err
 	}
 
 	return user, nil
@@ -235,18 +283,33 @@ func (r *userRepository) GetByEmailVerifyToken(ctx context.Context, token string
 
 // GetByPasswordResetToken gets a user by password reset token
 func (r *userRepository) GetByPasswordResetToken(ctx context.Context, token string) (*domain.User, error) {
-	query := `
-		SELECT id, email, password, role, is_email_verified, email_verify_token, email_verify_expiry,
-		       password_reset_token, password_reset_expiry, created_at, updated_at
-		FROM users
-		WHERE password_reset_token = $1
-	`
+	query := `SELECT ` + userColumns + ` FROM users WHERE password_reset_token = $1`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, token))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("reset token %w", repository.ErrNotFound)
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanUser scans a single users row selected with userColumns
+func scanUser(row rowScanner) (*domain.User, error) {
 	user := &domain.User{}
-	var emailVerifyToken, passwordResetToken sql.NullString
-	var emailVerifyExpiry, passwordResetExpiry sql.NullTime
+	var emailVerifyToken, passwordResetToken, phone, mfaSecret sql.NullString
+	var emailVerifyExpiry, passwordResetExpiry, phoneVerifiedAt, mfaEnrolledAt sql.NullTime
+	var deletionRequestedAt, deletionScheduledAt sql.NullTime
+	var mfaLastUsedStep sql.NullInt64
 
-	err := r.db.QueryRowContext(ctx, query, token).Scan(
+	err := row.Scan(
 		&user.ID,
 		&user.Email,
 		&user.Password,
@@ -256,14 +319,20 @@ func (r *userRepository) GetByPasswordResetToken(ctx context.Context, token stri
 		&emailVerifyExpiry,
 		&passwordResetToken,
 		&passwordResetExpiry,
+		&phone,
+		&user.IsPhoneVerified,
+		&phoneVerifiedAt,
+		&user.MFAEnabled,
+		&mfaSecret,
+		&mfaEnrolledAt,
+		&mfaLastUsedStep,
+		&user.TokenVersion,
+		&deletionRequestedAt,
+		&deletionScheduledAt,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
-
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, errors.New("invalid reset token")
-		}
 		return nil, err
 	}
 
@@ -279,7 +348,27 @@ func (r *userRepository) GetByPasswordResetToken(ctx context.Context, token stri
 	if passwordResetExpiry.Valid {
 		user.PasswordResetExpiry = &passwordResetExpiry.Time
 	}
+	if phone.Valid {
+		user.Phone = &phone.String
+	}
+	if phoneVerifiedAt.Valid {
+		user.PhoneVerifiedAt = &phoneVerifiedAt.Time
+	}
+	if mfaSecret.Valid {
+		user.MFASecret = &mfaSecret.String
+	}
+	if mfaEnrolledAt.Valid {
+		user.MFAEnrolledAt = &mfaEnrolledAt.Time
+	}
+	if mfaLastUsedStep.Valid {
+		user.MFALastUsedStep = &mfaLastUsedStep.Int64
+	}
+	if deletionRequestedAt.Valid {
+		user.DeletionRequestedAt = &deletionRequestedAt.Time
+	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
 
 	return user, nil
 }
-
@@ -0,0 +1,230 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"karigar-backend/internal/domain"
+	"karigar-backend/internal/repository"
+)
+
+var (
+	ErrUserSessionNotFound = fmt.Errorf("user session %w", repository.ErrNotFound)
+)
+
+const userSessionColumns = `id, user_id, refresh_token_id, user_agent, ip_address, location,
+		       created_at, last_seen_at, expires_at, revoked_at`
+
+type userSessionRepository struct {
+	db *sql.DB
+}
+
+// NewUserSessionRepository creates a new PostgreSQL user session repository
+func NewUserSessionRepository(db *sql.DB) repository.UserSessionRepository {
+	return &userSessionRepository{
+		db: db,
+	}
+}
+
+func (r *userSessionRepository) Create(ctx context.Context, session *domain.UserSession) error {
+	query := `
+		INSERT INTO user_sessions (id, user_id, refresh_token_id, user_agent, ip_address, location,
+		                           created_at, last_seen_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $7, $8)
+	`
+
+	now := time.Now()
+	session.CreatedAt = now
+	session.LastSeenAt = now
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		session.UserID,
+		session.RefreshTokenID,
+		nullString(session.UserAgent),
+		nullString(session.IPAddress),
+		nullString(session.Location),
+		now,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create user session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *userSessionRepository) GetByID(ctx context.Context, id string) (*domain.UserSession, error) {
+	query := `SELECT ` + userSessionColumns + ` FROM user_sessions WHERE id = $1`
+
+	session, err := scanUserSession(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get user session: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListActiveByUserID lists a user's sessions that are neither revoked nor expired, most recently used first
+func (r *userSessionRepository) ListActiveByUserID(ctx context.Context, userID string) ([]*domain.UserSession, error) {
+	query := `SELECT ` + userSessionColumns + ` FROM user_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_seen_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*domain.UserSession
+	for rows.Next() {
+		session, err := scanUserSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *userSessionRepository) CountByUserAgent(ctx context.Context, userID, userAgent string) (total, matching int, err error) {
+	query := `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE COALESCE(user_agent, '') = $2)
+		FROM user_sessions
+		WHERE user_id = $1
+	`
+
+	if err := r.db.QueryRowContext(ctx, query, userID, userAgent).Scan(&total, &matching); err != nil {
+		return 0, 0, fmt.Errorf("failed to count user sessions: %w", err)
+	}
+
+	return total, matching, nil
+}
+
+func (r *userSessionRepository) Update(ctx context.Context, session *domain.UserSession) error {
+	query := `
+		UPDATE user_sessions
+		SET refresh_token_id = $2, ip_address = $3, location = $4, last_seen_at = $5, expires_at = $6
+		WHERE id = $1
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		session.RefreshTokenID,
+		nullString(session.IPAddress),
+		nullString(session.Location),
+		session.LastSeenAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update user session: %w", err)
+	}
+
+	return nil
+}
+
+// Rotate replaces a session's refresh token, unless another refresh already
+// replaced it or the session was revoked in the meantime
+func (r *userSessionRepository) Rotate(ctx context.Context, session *domain.UserSession, previousRefreshTokenID string) error {
+	query := `
+		UPDATE user_sessions
+		SET refresh_token_id = $3, ip_address = $4, location = $5, last_seen_at = $6, expires_at = $7
+		WHERE id = $1 AND refresh_token_id = $2 AND revoked_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		previousRefreshTokenID,
+		session.RefreshTokenID,
+		nullString(session.IPAddress),
+		nullString(session.Location),
+		session.LastSeenAt,
+		session.ExpiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rotate user session: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to rotate user session: %w", err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("user session refresh token %w", repository.ErrConflict)
+	}
+
+	return nil
+}
+
+// Revoke revokes one of a user's active sessions
+func (r *userSessionRepository) Revoke(ctx context.Context, userID, id string) error {
+	query := `UPDATE user_sessions SET revoked_at = $3 WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL`
+
+	result, err := r.db.ExecContext(ctx, query, id, userID, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to revoke user session: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to revoke user session: %w", err)
+	}
+	if affected == 0 {
+		return ErrUserSessionNotFound
+	}
+
+	return nil
+}
+
+func (r *userSessionRepository) RevokeAllExcept(ctx context.Context, userID, exceptID string) (int64, error) {
+	query := `
+		UPDATE user_sessions SET revoked_at = $3
+		WHERE user_id = $1 AND revoked_at IS NULL AND ($2 = '' OR id::text <> $2)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, userID, exceptID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("failed to revoke user sessions: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+func scanUserSession(row rowScanner) (*domain.UserSession, error) {
+	session := &domain.UserSession{}
+	var userAgent, ipAddress, location sql.NullString
+	var revokedAt sql.NullTime
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.RefreshTokenID,
+		&userAgent,
+		&ipAddress,
+		&location,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&revokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	session.UserAgent = userAgent.String
+	session.IPAddress = ipAddress.String
+	session.Location = location.String
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+
+	return session, nil
+}
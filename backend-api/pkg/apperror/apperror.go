@@ -0,0 +1,114 @@
+package apperror
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies an error; each kind maps to one HTTP status
+type Kind int
+
+const (
+	Internal Kind = iota
+	Invalid
+	Unauthorized
+	Forbidden
+	NotFound
+	Conflict
+	TooManyRequests
+	Unavailable
+	TooLarge
+)
+
+// HTTPStatus returns the HTTP status code for the kind
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case Invalid:
+		return http.StatusBadRequest
+	case Unauthorized:
+		return http.StatusUnauthorized
+	case Forbidden:
+		return http.StatusForbidden
+	case NotFound:
+		return http.StatusNotFound
+	case Conflict:
+		return http.StatusConflict
+	case TooManyRequests:
+		return http.StatusTooManyRequests
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case TooLarge:
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error is an application error with a stable, machine-readable code. Its message
+// is safe to show to clients; the wrapped cause (if any) is not.
+type Error struct {
+	Kind    Kind
+	Code    string // e.g. "invalid_credentials"
+	Message string
+	Err     error        // Optional underlying cause
+	Fields  []FieldError // Set for request validation errors
+}
+
+// FieldError describes why a single request field was rejected
+type FieldError struct {
+	Field   string `json:"field,omitempty"` // Empty for errors about the whole body
+	Code    string `json:"code"`            // Validation rule, e.g. "required" or "email"
+	Message string `json:"message"`
+}
+
+// New creates an application error. Errors created with New are usually declared
+// once as package-level sentinels and compared with errors.Is.
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Wrap creates an application error with an underlying cause
+func Wrap(err error, kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the same sentinel. A wrapped copy of a sentinel
+// (see WithCause) matches the sentinel it was made from.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e.Kind == t.Kind && e.Code == t.Code && e.Message == t.Message && t.Err == nil
+}
+
+// WithCause returns a copy of the error that wraps a cause, for logging. The copy
+// still matches the original with errors.Is.
+func (e *Error) WithCause(err error) *Error {
+	return &Error{Kind: e.Kind, Code: e.Code, Message: e.Message, Err: err}
+}
+
+// As returns the first application error in err's chain
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+	return nil, false
+}
+
+// InvalidRequest wraps a request decoding or binding error
+func InvalidRequest(err error) *Error {
+	return Wrap(err, Invalid, "invalid_request", "request body or parameters are invalid")
+}
@@ -0,0 +1,43 @@
+package apperror
+
+import "net/http"
+
+// ContentType is the media type of problem details responses (RFC 7807)
+const ContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details object. Code is an extension member
+// carrying the application error code.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+	RequestID string       `json:"request_id,omitempty"` // Quote this when reporting a problem
+}
+
+// NewProblem builds a problem for an application error. When redact is true the
+// underlying cause is never included, and internal errors get a generic detail.
+func NewProblem(err *Error, instance string, redact bool) *Problem {
+	status := err.Kind.HTTPStatus()
+
+	detail := err.Message
+	if !redact && err.Err != nil && len(err.Fields) == 0 {
+		detail = err.Error()
+	}
+	if redact && status >= http.StatusInternalServerError {
+		detail = "an unexpected error occurred"
+	}
+
+	return &Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: instance,
+		Code:     err.Code,
+		Errors:   err.Fields,
+	}
+}
@@ -0,0 +1,153 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// breachedLineBuffer is how much is read to find one line of the file; lines
+// are a hash and a count, far shorter than this
+const breachedLineBuffer = 256
+
+// BreachedPasswordChecker checks passwords against a local list of breached SHA-1
+// hashes, such as a Have I Been Pwned export. The list is not loaded: each check
+// binary searches the file on disk, so it needs the lines sorted by hash (as in
+// the "ordered by hash" HIBP download) and costs a few reads per check however
+// large the list is. Plain-text passwords are never stored or compared.
+type BreachedPasswordChecker struct {
+	file     *os.File
+	size     int64
+	minCount int
+}
+
+// OpenBreachedPasswordFile opens a breached hash file. Each line is either
+// "<40 hex SHA-1>:<count>" (the HIBP downloader format) or "<40 hex SHA-1>", and
+// lines are sorted by hash. Hashes seen fewer than minCount times are ignored.
+func OpenBreachedPasswordFile(path string, minCount int) (*BreachedPasswordChecker, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to read breached password file: %w", err)
+	}
+
+	checker := &BreachedPasswordChecker{file: f, size: info.Size(), minCount: minCount}
+
+	// Catch a file in the wrong format now rather than on every check
+	line, _, err := checker.lineAt(0)
+	if err == nil && line != "" {
+		_, _, err = parseBreachedLine(line)
+	}
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return checker, nil
+}
+
+// IsBreached reports whether the password's SHA-1 hash appears in the list
+func (c *BreachedPasswordChecker) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	// Find the first line starting at or after lo whose hash is not below hash
+	lo, hi := int64(0), c.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		line, start, err := c.lineAt(mid)
+		if err != nil {
+			return false, err
+		}
+		if line == "" {
+			hi = mid
+			continue
+		}
+
+		lineHash, count, err := parseBreachedLine(line)
+		if err != nil {
+			return false, err
+		}
+		switch strings.Compare(lineHash, hash) {
+		case 0:
+			return count < 0 || count >= c.minCount, nil
+		case -1:
+			lo = start + int64(len(line)) + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return false, nil
+}
+
+// Size returns the size of the file in bytes
+func (c *BreachedPasswordChecker) Size() int64 {
+	return c.size
+}
+
+// lineAt returns the first line starting at or after offset, without its line
+// ending, and where it starts. It returns an empty line past the last one.
+func (c *BreachedPasswordChecker) lineAt(offset int64) (string, int64, error) {
+	// Read from the byte before offset, so a line starting right at offset is
+	// recognized by the line ending before it
+	from := offset
+	if from > 0 {
+		from--
+	}
+	buf := make([]byte, breachedLineBuffer)
+	n, err := c.file.ReadAt(buf, from)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", 0, fmt.Errorf("failed to read breached password file: %w", err)
+	}
+	buf = buf[:n]
+
+	start := from
+	if offset > 0 {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			if from+int64(n) >= c.size {
+				return "", 0, nil
+			}
+			return "", 0, fmt.Errorf("invalid line near byte %d of breached password file", offset)
+		}
+		buf = buf[i+1:]
+		start += int64(i) + 1
+	}
+
+	end := bytes.IndexByte(buf, '\n')
+	if end < 0 {
+		if start+int64(len(buf)) < c.size {
+			return "", 0, fmt.Errorf("invalid line near byte %d of breached password file", start)
+		}
+		end = len(buf)
+	}
+	return string(buf[:end]), start, nil
+}
+
+// parseBreachedLine splits a line into its upper-case hash and its count, which
+// is -1 when the line has none
+func parseBreachedLine(line string) (string, int, error) {
+	hash, countText, hasCount := strings.Cut(strings.TrimSpace(line), ":")
+	if len(hash) != sha1.Size*2 {
+		return "", 0, errors.New("invalid hash in breached password file")
+	}
+	if !hasCount {
+		return strings.ToUpper(hash), -1, nil
+	}
+	count, err := strconv.Atoi(countText)
+	if err != nil {
+		return "", 0, errors.New("invalid count in breached password file")
+	}
+	return strings.ToUpper(hash), count, nil
+}
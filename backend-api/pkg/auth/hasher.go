@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// Argon2Params holds argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32 // KiB
+	Iterations  uint32
+	Parallelism uint8
+}
+
+// DefaultArgon2Params follows the OWASP recommendation for argon2id
+func DefaultArgon2Params() Argon2Params {
+	return Argon2Params{
+		Memory:      19 * 1024,
+		Iterations:  2,
+		Parallelism: 1,
+	}
+}
+
+// PasswordHasher hashes new passwords with the configured algorithm and verifies
+// hashes produced by any supported algorithm, so the algorithm or cost can change
+// without invalidating existing passwords
+type PasswordHasher struct {
+	algorithm  string
+	bcryptCost int
+	argon2     Argon2Params
+}
+
+// NewBcryptHasher creates a hasher that produces bcrypt hashes
+func NewBcryptHasher(cost int) *PasswordHasher {
+	return &PasswordHasher{
+		algorithm:  AlgorithmBcrypt,
+		bcryptCost: cost,
+		argon2:     DefaultArgon2Params(),
+	}
+}
+
+// NewArgon2idHasher creates a hasher that produces argon2id hashes
+func NewArgon2idHasher(params Argon2Params) *PasswordHasher {
+	return &PasswordHasher{
+		algorithm:  AlgorithmArgon2id,
+		bcryptCost: bcryptCost,
+		argon2:     params,
+	}
+}
+
+// NewPasswordHasher creates a hasher for the named algorithm
+func NewPasswordHasher(algorithm string, cost int, params Argon2Params) (*PasswordHasher, error) {
+	switch algorithm {
+	case "", AlgorithmBcrypt:
+		if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost: %d", cost)
+		}
+		return NewBcryptHasher(cost), nil
+	case AlgorithmArgon2id:
+		return NewArgon2idHasher(params), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm: %s", algorithm)
+	}
+}
+
+// Hash hashes a password with the configured algorithm
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.algorithm == AlgorithmArgon2id {
+		return h.hashArgon2id(password)
+	}
+
+	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hashedBytes), nil
+}
+
+// Compare checks a password against a bcrypt or argon2id hash. It returns
+// ErrPasswordMismatch if the password is wrong.
+func (h *PasswordHasher) Compare(hashedPassword, password string) error {
+	switch {
+	case isBcryptHash(hashedPassword):
+		return compareBcrypt(hashedPassword, password)
+	case strings.HasPrefix(hashedPassword, "$argon2id$"):
+		return compareArgon2id(hashedPassword, password)
+	default:
+		return ErrUnknownHashFormat
+	}
+}
+
+// NeedsRehash reports whether a hash was produced with a different algorithm or
+// weaker parameters than currently configured
+func (h *PasswordHasher) NeedsRehash(hashedPassword string) bool {
+	switch h.algorithm {
+	case AlgorithmArgon2id:
+		params, _, _, err := decodeArgon2id(hashedPassword)
+		if err != nil {
+			return true
+		}
+		return params != h.argon2
+	default:
+		if !isBcryptHash(hashedPassword) {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		if err != nil {
+			return true
+		}
+		return cost != h.bcryptCost
+	}
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// hashArgon2id produces a PHC-formatted hash:
+// $argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<key>
+func (h *PasswordHasher) hashArgon2id(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.argon2.Iterations, h.argon2.Memory, h.argon2.Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		h.argon2.Memory, h.argon2.Iterations, h.argon2.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+func compareArgon2id(hashedPassword, password string) error {
+	params, salt, key, err := decodeArgon2id(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return ErrPasswordMismatch
+	}
+	return nil
+}
+
+func decodeArgon2id(hashedPassword string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[1] != AlgorithmArgon2id {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return params, nil, nil, ErrUnknownHashFormat
+	}
+
+	return params, salt, key, nil
+}
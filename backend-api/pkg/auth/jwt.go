@@ -14,11 +14,24 @@ var (
 	ErrExpiredToken = errors.New("token has expired")
 )
 
+// TokenType distinguishes what a token may be used for
+type TokenType string
+
+const (
+	TokenTypeAccess       TokenType = "access"
+	TokenTypeRefresh      TokenType = "refresh"
+	TokenTypeMFAChallenge TokenType = "mfa_challenge" // Password verified, second factor pending
+	TokenTypeMFAEnroll    TokenType = "mfa_enroll"    // Password verified, MFA enrolment required by policy
+)
+
 // JWTClaims represents the JWT claims structure
 type JWTClaims struct {
-	UserID string          `json:"user_id"`
-	Email  string          `json:"email"`
-	Role   domain.UserRole `json:"role"`
+	UserID    string          `json:"user_id"`
+	Email     string          `json:"email"`
+	Role      domain.UserRole `json:"role"`
+	TokenType TokenType       `json:"token_type,omitempty"`
+	Version   int             `json:"ver,omitempty"` // User's token version; bumping it revokes refresh tokens
+	SessionID string          `json:"sid,omitempty"` // Login session the token was issued for
 	jwt.RegisteredClaims
 }
 
@@ -27,6 +40,7 @@ type JWTManager struct {
 	secretKey     string
 	accessExpiry  time.Duration
 	refreshExpiry time.Duration
+	mfaExpiry     time.Duration
 }
 
 // NewJWTManager creates a new JWT manager
@@ -35,39 +49,46 @@ func NewJWTManager(cfg *config.JWTConfig) *JWTManager {
 		secretKey:     cfg.SecretKey,
 		accessExpiry:  15 * time.Minute,  // Access token: 15 minutes
 		refreshExpiry: 7 * 24 * time.Hour, // Refresh token: 7 days
+		mfaExpiry:     5 * time.Minute,    // MFA challenge token: 5 minutes
 	}
 }
 
 // GenerateAccessToken generates a new access token
 func (jm *JWTManager) GenerateAccessToken(user *domain.User) (string, error) {
-	claims := &JWTClaims{
-		UserID: user.ID.String(),
-		Email:  user.Email,
-		Role:   user.Role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(jm.accessExpiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-		},
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(jm.secretKey))
+	return jm.generateToken(user, TokenTypeAccess, jm.accessExpiry)
 }
 
 // GenerateRefreshToken generates a new refresh token
 func (jm *JWTManager) GenerateRefreshToken(user *domain.User) (string, error) {
-	claims := &JWTClaims{
-		UserID: user.ID.String(),
-		Email:  user.Email,
-		Role:   user.Role,
+	return jm.generateToken(user, TokenTypeRefresh, jm.refreshExpiry)
+}
+
+// GenerateMFAToken generates a short-lived token that can only be exchanged
+// for a token pair after the second factor is verified
+func (jm *JWTManager) GenerateMFAToken(user *domain.User, tokenType TokenType) (string, error) {
+	return jm.generateToken(user, tokenType, jm.mfaExpiry)
+}
+
+func (jm *JWTManager) generateToken(user *domain.User, tokenType TokenType, expiry time.Duration) (string, error) {
+	return jm.sign(jm.newClaims(user, tokenType, expiry))
+}
+
+func (jm *JWTManager) newClaims(user *domain.User, tokenType TokenType, expiry time.Duration) *JWTClaims {
+	return &JWTClaims{
+		UserID:    user.ID.String(),
+		Email:     user.Email,
+		Role:      user.Role,
+		TokenType: tokenType,
+		Version:   user.TokenVersion,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(jm.refreshExpiry)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
 	}
+}
 
+func (jm *JWTManager) sign(claims *JWTClaims) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(jm.secretKey))
 }
@@ -96,14 +117,40 @@ func (jm *JWTManager) ValidateToken(tokenString string) (*JWTClaims, error) {
 	return claims, nil
 }
 
-// GenerateTokenPair generates both access and refresh tokens
-func (jm *JWTManager) GenerateTokenPair(user *domain.User) (accessToken string, refreshToken string, err error) {
-	accessToken, err = jm.GenerateAccessToken(user)
+// ValidateTokenType validates a JWT token and checks that it was issued for the given purpose.
+// Tokens issued before token types were introduced have no type and are accepted as
+// access or refresh tokens.
+func (jm *JWTManager) ValidateTokenType(tokenString string, expected TokenType) (*JWTClaims, error) {
+	claims, err := jm.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType == expected {
+		return claims, nil
+	}
+	if claims.TokenType == "" && (expected == TokenTypeAccess || expected == TokenTypeRefresh) {
+		return claims, nil
+	}
+
+	return nil, ErrInvalidToken
+}
+
+// GenerateTokenPair generates access and refresh tokens for a login session.
+// refreshTokenID becomes the refresh token's ID (jti), so that a session can
+// accept only the newest refresh token issued for it.
+func (jm *JWTManager) GenerateTokenPair(user *domain.User, sessionID, refreshTokenID string) (accessToken string, refreshToken string, err error) {
+	access := jm.newClaims(user, TokenTypeAccess, jm.accessExpiry)
+	access.SessionID = sessionID
+	accessToken, err = jm.sign(access)
 	if err != nil {
 		return "", "", err
 	}
 
-	refreshToken, err = jm.GenerateRefreshToken(user)
+	refresh := jm.newClaims(user, TokenTypeRefresh, jm.refreshExpiry)
+	refresh.SessionID = sessionID
+	refresh.ID = refreshTokenID
+	refreshToken, err = jm.sign(refresh)
 	if err != nil {
 		return "", "", err
 	}
@@ -111,3 +158,8 @@ func (jm *JWTManager) GenerateTokenPair(user *domain.User) (accessToken string,
 	return accessToken, refreshToken, nil
 }
 
+// RefreshExpiry returns the lifetime of refresh tokens
+func (jm *JWTManager) RefreshExpiry() time.Duration {
+	return jm.refreshExpiry
+}
+
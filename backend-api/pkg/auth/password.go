@@ -2,6 +2,7 @@ package auth
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"unicode"
 
@@ -9,44 +10,62 @@ import (
 )
 
 var (
-	ErrPasswordTooShort = errors.New("password must be at least 8 characters long")
-	ErrPasswordTooWeak  = errors.New("password must contain at least one uppercase letter, one lowercase letter, and one number")
+	ErrPasswordTooShort  = errors.New("password is too short")
+	ErrPasswordTooLong   = errors.New("password is too long")
+	ErrPasswordTooWeak   = errors.New("password must contain at least one uppercase letter, one lowercase letter, and one number")
+	ErrPasswordBreached  = errors.New("password has appeared in a data breach, choose a different one")
+	ErrPasswordReused    = errors.New("password was used recently, choose a different one")
+	ErrPasswordMismatch  = errors.New("password does not match")
+	ErrUnknownHashFormat = errors.New("unknown password hash format")
 )
 
 const (
 	minPasswordLength = 8
-	bcryptCost        = 10
+	maxPasswordBytes  = 72 // bcrypt silently ignores everything after 72 bytes
+	// maxPasswordInput caps passwords when the policy sets no maximum, so huge
+	// inputs cannot tie up the hasher (argon2id has no length limit of its own)
+	maxPasswordInput = 1024
+	bcryptCost       = 10
 )
 
-// HashPassword hashes a password using bcrypt
-func HashPassword(password string) (string, error) {
-	if err := ValidatePasswordStrength(password); err != nil {
-		return "", err
-	}
-
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcryptCost)
-	if err != nil {
-		return "", err
-	}
-
-	return string(hashedBytes), nil
+// PasswordPolicy describes the rules new passwords must satisfy
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int // In bytes; maxPasswordInput if not positive
+	RequireUpper  bool
+	RequireLower  bool
+	RequireNumber bool
+	Breached      *BreachedPasswordChecker // Optional
 }
 
-// ComparePassword compares a password with a hash
-func ComparePassword(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+// DefaultPasswordPolicy returns the policy used when none is configured
+func DefaultPasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{
+		MinLength:     minPasswordLength,
+		MaxLength:     maxPasswordBytes,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireNumber: true,
+	}
 }
 
-// ValidatePasswordStrength validates password strength
-func ValidatePasswordStrength(password string) error {
-	if len(password) < minPasswordLength {
-		return ErrPasswordTooShort
+// Validate checks a candidate password against the policy
+func (p *PasswordPolicy) Validate(password string) error {
+	maxLength := p.MaxLength
+	if maxLength <= 0 {
+		maxLength = maxPasswordInput
+	}
+	if len(password) > maxLength {
+		return fmt.Errorf("%w: maximum is %d bytes", ErrPasswordTooLong, maxLength)
+	}
+	if len([]rune(password)) < p.MinLength {
+		return fmt.Errorf("%w: minimum is %d characters", ErrPasswordTooShort, p.MinLength)
 	}
 
 	var (
-		hasUpper   = false
-		hasLower   = false
-		hasNumber  = false
+		hasUpper  = false
+		hasLower  = false
+		hasNumber = false
 	)
 
 	for _, char := range password {
@@ -60,16 +79,70 @@ func ValidatePasswordStrength(password string) error {
 		}
 	}
 
-	if !hasUpper || !hasLower || !hasNumber {
+	if (p.RequireUpper && !hasUpper) || (p.RequireLower && !hasLower) || (p.RequireNumber && !hasNumber) {
 		return ErrPasswordTooWeak
 	}
 
+	if p.Breached != nil {
+		breached, err := p.Breached.IsBreached(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			return ErrPasswordBreached
+		}
+	}
+
 	return nil
 }
 
+// IsPasswordPolicyError reports whether err means the password was rejected by policy
+// (as opposed to an internal failure)
+func IsPasswordPolicyError(err error) bool {
+	return errors.Is(err, ErrPasswordTooShort) ||
+		errors.Is(err, ErrPasswordTooLong) ||
+		errors.Is(err, ErrPasswordTooWeak) ||
+		errors.Is(err, ErrPasswordBreached) ||
+		errors.Is(err, ErrPasswordReused)
+}
+
+var (
+	defaultPolicy = DefaultPasswordPolicy()
+	defaultHasher = NewBcryptHasher(bcryptCost)
+)
+
+// HashPassword validates a password against the default policy and hashes it with bcrypt
+func HashPassword(password string) (string, error) {
+	if err := ValidatePasswordStrength(password); err != nil {
+		return "", err
+	}
+
+	return defaultHasher.Hash(password)
+}
+
+// ComparePassword compares a password with a hash (bcrypt or argon2id)
+func ComparePassword(hashedPassword, password string) error {
+	return defaultHasher.Compare(hashedPassword, password)
+}
+
+// ValidatePasswordStrength validates password strength against the default policy
+func ValidatePasswordStrength(password string) error {
+	return defaultPolicy.Validate(password)
+}
+
 // IsValidEmail validates email format
 func IsValidEmail(email string) bool {
 	emailRegex := regexp.MustCompile(`^[a-zA-Z0-9._%+\-]+@[a-zA-Z0-9.\-]+\.[a-zA-Z]{2,}$`)
 	return emailRegex.MatchString(email)
 }
 
+// compareBcrypt compares a password with a bcrypt hash
+func compareBcrypt(hashedPassword, password string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return ErrPasswordMismatch
+		}
+		return err
+	}
+	return nil
+}
@@ -1,18 +1,21 @@
 package database
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
 	"time"
 
 	"karigar-backend/internal/config"
 
+	"github.com/XSAM/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+
 	_ "github.com/lib/pq" // PostgreSQL driver
 )
 
-// DB is the global database connection
-var DB *sql.DB
-
 // Connect initializes the database connection based on the driver
 func Connect(cfg *config.DatabaseConfig) (*sql.DB, error) {
 	dsn := cfg.GetDSN()
@@ -20,7 +23,19 @@ func Connect(cfg *config.DatabaseConfig) (*sql.DB, error) {
 		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
 	}
 
-	db, err := sql.Open(cfg.Driver, dsn)
+	// Queries get a span when they run inside a traced request or job; background
+	// work without a trace (pings, sweeps) is not traced on its own
+	db, err := otelsql.Open(cfg.Driver, dsn,
+		otelsql.WithAttributes(semconv.DBSystemKey.String(cfg.Driver), semconv.DBName(cfg.DBName)),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitConnPrepare:      true,
+			OmitRows:             true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanContextFromContext(ctx).IsValid()
+			},
+		}),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
@@ -36,20 +51,5 @@ func Connect(cfg *config.DatabaseConfig) (*sql.DB, error) {
 	db.SetMaxIdleConns(5)
 	db.SetConnMaxLifetime(5 * time.Minute)
 
-	DB = db
 	return db, nil
 }
-
-// Close closes the database connection
-func Close() error {
-	if DB != nil {
-		return DB.Close()
-	}
-	return nil
-}
-
-// GetDB returns the global database connection
-func GetDB() *sql.DB {
-	return DB
-}
-
@@ -0,0 +1,15 @@
+package database
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"karigar-backend/pkg/metrics"
+)
+
+// RegisterMetrics exposes the connection pool statistics (sql.DB.Stats) of db as
+// go_sql_* metrics labelled with db_name: open, in-use and idle connections,
+// waits for a free connection and connections closed by pool limits
+func RegisterMetrics(db *sql.DB, dbName string) error {
+	return metrics.Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+}
@@ -1,8 +1,10 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -11,55 +13,174 @@ import (
 	_ "github.com/lib/pq"
 )
 
-// RunMigrations runs all SQL migration files in the migrations directory
+// createMigrationsTable records which migration files have been applied, so each
+// runs once and readiness checks can compare the schema version to the code
+const createMigrationsTable = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version VARCHAR(255) PRIMARY KEY,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`
+
+// migrationLockKey is the pg_advisory_lock key held while migrating, so
+// replicas starting together don't apply the same migration twice
+const migrationLockKey = 7245311902
+
+// RunMigrations runs the SQL migration files in the migrations directory that
+// have not been applied yet. Each file runs in a transaction together with its
+// schema_migrations row, so a failed migration is never recorded as applied
 func RunMigrations(db *sql.DB, migrationsDir string) error {
-	// Read migration files
-	files, err := os.ReadDir(migrationsDir)
+	ctx := context.Background()
+
+	migrationFiles, err := listMigrations(migrationsDir)
 	if err != nil {
-		return fmt.Errorf("failed to read migrations directory: %w", err)
+		return err
 	}
 
-	// Filter and sort migration files
-	var migrationFiles []string
-	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
-			migrationFiles = append(migrationFiles, file.Name())
+	// Advisory locks belong to a session, so everything runs on one connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+			slog.Error("failed to release migration lock", "error", err)
 		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
 	}
 
-	sort.Strings(migrationFiles)
+	// Read after taking the lock, so migrations applied by another replica
+	// while we waited are skipped
+	applied, err := appliedMigrations(ctx, conn)
+	if err != nil {
+		return err
+	}
 
 	// Execute migrations in order
 	for _, filename := range migrationFiles {
+		if applied[filename] {
+			continue
+		}
+
 		filePath := filepath.Join(migrationsDir, filename)
 		sqlContent, err := os.ReadFile(filePath)
 		if err != nil {
 			return fmt.Errorf("failed to read migration file %s: %w", filename, err)
 		}
 
-		// Execute migration
-		if _, err := db.Exec(string(sqlContent)); err != nil {
-			// Check if error is about table already existing (not a fatal error)
-			errStr := err.Error()
-			if strings.Contains(errStr, "already exists") || strings.Contains(errStr, "duplicate") {
-				fmt.Printf("⚠ Migration %s skipped (already applied): %v\n", filename, err)
-				continue
-			}
-			return fmt.Errorf("failed to execute migration %s: %w", filename, err)
+		if err := applyMigration(ctx, conn, filename, string(sqlContent)); err != nil {
+			return err
 		}
+		slog.Info("executed migration", "migration", filename)
+	}
+
+	return nil
+}
 
-		fmt.Printf("✓ Executed migration: %s\n", filename)
+// applyMigration runs one migration file and records it in schema_migrations in
+// a single transaction
+func applyMigration(ctx context.Context, conn *sql.Conn, filename, sqlContent string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", filename, err)
 	}
+	defer tx.Rollback()
 
+	if _, err := tx.ExecContext(ctx, sqlContent); err != nil {
+		return fmt.Errorf("failed to execute migration %s: %w", filename, err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES ($1)`, filename); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", filename, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", filename, err)
+	}
 	return nil
 }
 
-// RunMigrationsFromPath runs migrations from a given directory path
+// LatestMigration returns the newest migration file in the migrations directory,
+// i.e. the schema version the code expects
+func LatestMigration(migrationsDir string) (string, error) {
+	migrationFiles, err := listMigrations(migrationsDir)
+	if err != nil {
+		return "", err
+	}
+	if len(migrationFiles) == 0 {
+		return "", fmt.Errorf("no migrations found in %s", migrationsDir)
+	}
+	return migrationFiles[len(migrationFiles)-1], nil
+}
+
+// CurrentMigration returns the newest migration applied to the database
+func CurrentMigration(ctx context.Context, db *sql.DB) (string, error) {
+	var version sql.NullString
+	if err := db.QueryRowContext(ctx, `SELECT MAX(version) FROM schema_migrations`).Scan(&version); err != nil {
+		return "", fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return version.String, nil
+}
+
+// listMigrations returns the SQL files in the migrations directory in the order
+// they must run
+func listMigrations(migrationsDir string) ([]string, error) {
+	files, err := os.ReadDir(migrationsDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var migrationFiles []string
+	for _, file := range files {
+		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
+			migrationFiles = append(migrationFiles, file.Name())
+		}
+	}
+
+	sort.Strings(migrationFiles)
+	return migrationFiles, nil
+}
+
+// appliedMigrations returns the migrations recorded in schema_migrations
+func appliedMigrations(ctx context.Context, conn *sql.Conn) (map[string]bool, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]bool)
+	for rows.Next() {
+		var version string
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// RunMigrationsFromPath runs migrations from the first migrations directory found
 func RunMigrationsFromPath(db *sql.DB) error {
+	migrationsDir, err := FindMigrationsDir()
+	if err != nil {
+		return err
+	}
+
+	return RunMigrations(db, migrationsDir)
+}
+
+// FindMigrationsDir locates the migrations directory relative to the working directory
+func FindMigrationsDir() (string, error) {
 	// Get the current working directory
 	wd, err := os.Getwd()
 	if err != nil {
-		return fmt.Errorf("failed to get working directory: %w", err)
+		return "", fmt.Errorf("failed to get working directory: %w", err)
 	}
 
 	// Try multiple possible paths for migrations directory
@@ -69,6 +190,7 @@ func RunMigrationsFromPath(db *sql.DB) error {
 		filepath.Join(wd, "pkg", "database", "migrations"),             // From backend-api root
 		filepath.Join("pkg", "database", "migrations"),                 // Relative
 		filepath.Join(".", "pkg", "database", "migrations"),            // Current dir
+		filepath.Join(wd, "migrations"),                                // Docker image
 	}
 
 	var migrationsDir string
@@ -79,14 +201,14 @@ func RunMigrationsFromPath(db *sql.DB) error {
 		if _, err := os.Stat(path); err == nil {
 			migrationsDir = path
 			found = true
-			fmt.Printf("Found migrations directory at: %s\n", absPath)
+			slog.Info("found migrations directory", "path", absPath)
 			break
 		}
 	}
 
 	if !found {
-		return fmt.Errorf("migrations directory not found. Working directory: %s, Tried: %v", wd, possiblePaths)
+		return "", fmt.Errorf("migrations directory not found. Working directory: %s, Tried: %v", wd, possiblePaths)
 	}
 
-	return RunMigrations(db, migrationsDir)
+	return migrationsDir, nil
 }
@@ -0,0 +1,15 @@
+-- Migration: Add phone verification to users
+-- Description: Stores a verified E.164 phone number on users for OTP verification and passwordless login
+-- Created: 2026-10-19
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone VARCHAR(20);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_phone_verified BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS phone_verified_at TIMESTAMP;
+
+-- A verified phone number can only belong to one user, since it is used as a login method
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_verified_phone_unique ON users(phone) WHERE is_phone_verified = TRUE;
+
+-- Add comments
+COMMENT ON COLUMN users.phone IS 'Phone number in E.164 format (e.g. +923001234567)';
+COMMENT ON COLUMN users.is_phone_verified IS 'Whether the phone number has been verified via OTP';
+COMMENT ON COLUMN users.phone_verified_at IS 'When the phone number was last verified';
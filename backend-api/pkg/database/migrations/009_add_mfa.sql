@@ -0,0 +1,38 @@
+-- Migration: Add TOTP two-factor authentication
+-- Description: Adds TOTP enrolment to users, one-time recovery codes and per-role MFA enforcement
+-- Created: 2026-10-19
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_secret TEXT;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_enrolled_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mfa_last_used_step BIGINT;
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_hash ON mfa_recovery_codes(user_id, code_hash);
+
+CREATE TABLE IF NOT EXISTS mfa_role_policies (
+    role VARCHAR(50) PRIMARY KEY CHECK (role IN ('customer', 'service_provider', 'admin')),
+    mfa_required BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+DROP TRIGGER IF EXISTS update_mfa_role_policies_updated_at ON mfa_role_policies;
+CREATE TRIGGER update_mfa_role_policies_updated_at BEFORE UPDATE ON mfa_role_policies
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments
+COMMENT ON COLUMN users.mfa_enabled IS 'Whether TOTP two-factor authentication is enabled';
+COMMENT ON COLUMN users.mfa_secret IS 'TOTP secret encrypted with AES-GCM (pending until mfa_enabled is true)';
+COMMENT ON COLUMN users.mfa_last_used_step IS 'Last accepted TOTP time step, used to reject code replay';
+COMMENT ON TABLE mfa_recovery_codes IS 'One-time MFA recovery codes (SHA-256 hashed)';
+COMMENT ON TABLE mfa_role_policies IS 'Per-role MFA enforcement set by admins';
@@ -0,0 +1,18 @@
+-- Migration: Create password_history table
+-- Description: Keeps previous password hashes so users cannot reuse recent passwords
+-- Created: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS password_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_password_history_user_created ON password_history(user_id, created_at DESC);
+
+-- Add comments
+COMMENT ON TABLE password_history IS 'Previous password hashes, used to prevent password reuse';
+COMMENT ON COLUMN password_history.password_hash IS 'Hash of a previous password (bcrypt or argon2id)';
+COMMENT ON COLUMN users.password IS 'Hashed password using bcrypt or argon2id (rehashed at login when parameters change)';
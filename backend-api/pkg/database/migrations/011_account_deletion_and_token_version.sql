@@ -0,0 +1,48 @@
+-- Migration: Account deletion and session revocation
+-- Description: Adds token versioning (to revoke sessions on password change) and scheduled
+--              account deletion. Bookings and reviews no longer cascade when a customer or
+--              provider is deleted: their references are set to NULL so history and provider
+--              ratings survive the deletion in anonymized form.
+-- Created: 2026-10-19
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_requested_at TIMESTAMP;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_users_deletion_scheduled_at ON users(deletion_scheduled_at) WHERE deletion_scheduled_at IS NOT NULL;
+
+-- service_requests: keep bookings when the customer, provider or service is deleted
+ALTER TABLE service_requests ALTER COLUMN customer_id DROP NOT NULL;
+ALTER TABLE service_requests ALTER COLUMN provider_id DROP NOT NULL;
+ALTER TABLE service_requests ALTER COLUMN service_id DROP NOT NULL;
+
+ALTER TABLE service_requests DROP CONSTRAINT IF EXISTS service_requests_customer_id_fkey;
+ALTER TABLE service_requests ADD CONSTRAINT service_requests_customer_id_fkey
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE SET NULL;
+ALTER TABLE service_requests DROP CONSTRAINT IF EXISTS service_requests_provider_id_fkey;
+ALTER TABLE service_requests ADD CONSTRAINT service_requests_provider_id_fkey
+    FOREIGN KEY (provider_id) REFERENCES service_providers(id) ON DELETE SET NULL;
+ALTER TABLE service_requests DROP CONSTRAINT IF EXISTS service_requests_service_id_fkey;
+ALTER TABLE service_requests ADD CONSTRAINT service_requests_service_id_fkey
+    FOREIGN KEY (service_id) REFERENCES services(id) ON DELETE SET NULL;
+
+-- reviews: keep reviews (and therefore provider ratings) when the customer is deleted
+ALTER TABLE reviews ALTER COLUMN customer_id DROP NOT NULL;
+ALTER TABLE reviews ALTER COLUMN provider_id DROP NOT NULL;
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_customer_id_fkey;
+ALTER TABLE reviews ADD CONSTRAINT reviews_customer_id_fkey
+    FOREIGN KEY (customer_id) REFERENCES customers(id) ON DELETE SET NULL;
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_provider_id_fkey;
+ALTER TABLE reviews ADD CONSTRAINT reviews_provider_id_fkey
+    FOREIGN KEY (provider_id) REFERENCES service_providers(id) ON DELETE SET NULL;
+
+-- Add comments
+COMMENT ON COLUMN users.token_version IS 'Incremented to revoke all refresh tokens (e.g. on password change)';
+COMMENT ON COLUMN users.deletion_requested_at IS 'When the user requested account deletion';
+COMMENT ON COLUMN users.deletion_scheduled_at IS 'When the account will be anonymized and deleted (end of grace period)';
+COMMENT ON COLUMN service_requests.customer_id IS 'Foreign key reference to customers table (NULL once the customer account is deleted)';
+COMMENT ON COLUMN service_requests.provider_id IS 'Foreign key reference to service_providers table (NULL once the provider account is deleted)';
+COMMENT ON COLUMN service_requests.service_id IS 'Foreign key reference to services table (NULL once the service is deleted)';
+COMMENT ON COLUMN reviews.customer_id IS 'Foreign key reference to customers table (NULL once the customer account is deleted)';
+COMMENT ON COLUMN reviews.provider_id IS 'Foreign key reference to service_providers table (NULL once the provider account is deleted)';
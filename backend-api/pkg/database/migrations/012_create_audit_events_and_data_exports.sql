@@ -0,0 +1,53 @@
+-- Migration: Create audit_events and data_exports tables
+-- Description: Records security-relevant account events and tracks personal data export jobs
+-- Created: 2026-10-19
+
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    action VARCHAR(100) NOT NULL,
+    ip_address VARCHAR(45),
+    user_agent TEXT,
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_audit_events_user_created ON audit_events(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_action ON audit_events(action);
+
+CREATE TABLE IF NOT EXISTS data_exports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'expired')),
+    file_path TEXT,
+    file_size BIGINT,
+    error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_data_exports_user_id ON data_exports(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_data_exports_status ON data_exports(status);
+CREATE INDEX IF NOT EXISTS idx_data_exports_expires_at ON data_exports(expires_at) WHERE status = 'completed';
+
+-- Create trigger to automatically update updated_at
+DROP TRIGGER IF EXISTS update_data_exports_updated_at ON data_exports;
+CREATE TRIGGER update_data_exports_updated_at BEFORE UPDATE ON data_exports
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Add comments
+COMMENT ON TABLE audit_events IS 'Security-relevant account events (logins, password and MFA changes, exports)';
+COMMENT ON COLUMN audit_events.user_id IS 'User the event is about';
+COMMENT ON COLUMN audit_events.actor_id IS 'User who performed the action (differs from user_id for admin actions)';
+COMMENT ON TABLE data_exports IS 'Personal data export jobs; the archive is downloadable until expires_at';
+COMMENT ON COLUMN data_exports.requested_by IS 'Admin who requested the export on behalf of the user (NULL for self-service)';
+COMMENT ON COLUMN data_exports.file_path IS 'Path of the generated zip archive';
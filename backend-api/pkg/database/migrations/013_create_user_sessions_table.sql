@@ -0,0 +1,28 @@
+-- Migration: Create user_sessions table
+-- Description: Records each login (the refresh tokens it is issued) with device metadata, so
+--              users can list where they are logged in and revoke individual sessions
+-- Created: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    refresh_token_id UUID NOT NULL,
+    user_agent TEXT,
+    ip_address VARCHAR(45),
+    location VARCHAR(255),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_active ON user_sessions(user_id, last_seen_at DESC) WHERE revoked_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_user_sessions_user_id ON user_sessions(user_id);
+
+-- Add comments
+COMMENT ON TABLE user_sessions IS 'Logins and the devices they were made from; refresh tokens are only accepted for active sessions';
+COMMENT ON COLUMN user_sessions.refresh_token_id IS 'ID (jti) of the only refresh token currently valid for the session; rotated on each refresh';
+COMMENT ON COLUMN user_sessions.location IS 'Approximate location resolved from ip_address, if a GeoIP source is configured';
+COMMENT ON COLUMN user_sessions.expires_at IS 'Expiry of the current refresh token';
+COMMENT ON COLUMN user_sessions.revoked_at IS 'Set when the session is revoked (by the user, logout or a password change)';
@@ -0,0 +1,30 @@
+-- Migration: Snapshot service terms onto bookings
+-- Description: Copies the service's name, price, duration and currency onto each booking when
+--              it is made, so later changes to the service do not alter existing bookings,
+--              and stores the booking's estimated end time
+-- Created: 2026-10-19
+
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS service_name VARCHAR(255);
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS quoted_price DECIMAL(10, 2) CHECK (quoted_price >= 0);
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'PKR';
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS duration_minutes INTEGER CHECK (duration_minutes > 0);
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS estimated_end_date TIMESTAMP;
+
+-- Backfill existing bookings from their service's current terms, the best record there is
+UPDATE service_requests sr
+SET service_name = s.name,
+    quoted_price = s.price,
+    duration_minutes = s.duration,
+    estimated_end_date = COALESCE(sr.scheduled_date, sr.requested_date) + make_interval(mins => s.duration)
+FROM services s
+WHERE sr.service_id = s.id AND sr.quoted_price IS NULL;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_service_requests_provider_schedule ON service_requests(provider_id, requested_date) WHERE status IN ('requested', 'confirmed');
+
+-- Add comments
+COMMENT ON COLUMN service_requests.service_name IS 'Name of the service when it was booked';
+COMMENT ON COLUMN service_requests.quoted_price IS 'Price of the service when it was booked; what the customer agreed to pay';
+COMMENT ON COLUMN service_requests.currency IS 'ISO 4217 currency of quoted_price';
+COMMENT ON COLUMN service_requests.duration_minutes IS 'Duration of the service when it was booked';
+COMMENT ON COLUMN service_requests.estimated_end_date IS 'Start (scheduled_date, else requested_date) plus duration_minutes';
@@ -0,0 +1,40 @@
+-- Migration: Booking cancellation policies, fees and provider reliability
+-- Description: Lets providers define cancellation windows with fees, which are copied onto each
+--              booking when it is made; records who cancelled a booking, why, when and the fee
+--              charged; and tracks a reliability score for providers based on the bookings they
+--              cancel after confirming them
+-- Created: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS cancellation_windows (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider_id UUID NOT NULL REFERENCES service_providers(id) ON DELETE CASCADE,
+    hours_before INTEGER NOT NULL CHECK (hours_before > 0),
+    fee_percent DECIMAL(5, 2) NOT NULL CHECK (fee_percent >= 0 AND fee_percent <= 100),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider_id, hours_before)
+);
+
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS cancellation_policy JSONB NOT NULL DEFAULT '[]';
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP;
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS cancelled_by VARCHAR(20) CHECK (cancelled_by IN ('customer', 'provider', 'system'));
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS cancelled_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS cancelled_from VARCHAR(50);
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS cancellation_reason VARCHAR(50);
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS cancellation_note TEXT;
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS cancellation_fee DECIMAL(10, 2) CHECK (cancellation_fee >= 0);
+
+ALTER TABLE service_providers ADD COLUMN IF NOT EXISTS reliability_score DECIMAL(5, 2) NOT NULL DEFAULT 100.00 CHECK (reliability_score >= 0 AND reliability_score <= 100);
+ALTER TABLE service_providers ADD COLUMN IF NOT EXISTS provider_cancellations INTEGER NOT NULL DEFAULT 0;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_cancellation_windows_provider_id ON cancellation_windows(provider_id);
+
+-- Add comments
+COMMENT ON TABLE cancellation_windows IS 'Provider cancellation policies: a customer cancelling less than hours_before the start pays fee_percent of the price';
+COMMENT ON COLUMN service_requests.cancellation_policy IS 'The provider''s cancellation windows when the booking was made';
+COMMENT ON COLUMN service_requests.cancelled_by IS 'Who cancelled: customer, provider or system';
+COMMENT ON COLUMN service_requests.cancelled_from IS 'Status the booking was cancelled from: requested or confirmed';
+COMMENT ON COLUMN service_requests.cancellation_reason IS 'Reason code, e.g. schedule_conflict or provider_unavailable';
+COMMENT ON COLUMN service_requests.cancellation_fee IS 'Fee charged to the customer under the booking''s cancellation policy';
+COMMENT ON COLUMN service_providers.reliability_score IS 'Percentage of confirmed bookings the provider did not cancel (100 with no history)';
+COMMENT ON COLUMN service_providers.provider_cancellations IS 'Number of confirmed bookings the provider cancelled';
@@ -0,0 +1,29 @@
+-- Migration: Create reschedule_proposals table
+-- Description: Lets either party to a requested or confirmed booking propose a new date, which
+--              the other party accepts, rejects or counters with a date of their own; every
+--              proposal is kept as the booking's negotiation history
+-- Created: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS reschedule_proposals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES service_requests(id) ON DELETE CASCADE,
+    proposed_by VARCHAR(20) NOT NULL CHECK (proposed_by IN ('customer', 'provider')),
+    proposed_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    proposed_date TIMESTAMP NOT NULL,
+    note TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'accepted', 'rejected', 'countered', 'expired')),
+    counter_to_id UUID REFERENCES reschedule_proposals(id) ON DELETE SET NULL,
+    responded_at TIMESTAMP,
+    responded_by_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_reschedule_proposals_request_id ON reschedule_proposals(request_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reschedule_proposals_one_pending ON reschedule_proposals(request_id) WHERE status = 'pending';
+
+-- Add comments
+COMMENT ON TABLE reschedule_proposals IS 'Proposed new dates for bookings and how the other party answered them';
+COMMENT ON COLUMN reschedule_proposals.proposed_by IS 'Party that proposed the date: customer or provider';
+COMMENT ON COLUMN reschedule_proposals.status IS 'pending until answered; expired if the booking is completed or cancelled first';
+COMMENT ON COLUMN reschedule_proposals.counter_to_id IS 'The proposal this one was made in answer to, if it is a counter-proposal';
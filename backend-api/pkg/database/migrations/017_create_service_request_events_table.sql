@@ -0,0 +1,71 @@
+-- Migration: Create service_request_events table
+-- Description: Append-only history of every booking: status transitions, reschedule proposals and
+--              their answers, notes and price changes, with who made them and when. Booking
+--              analytics are computed from it. Existing bookings are backfilled from their
+--              current state.
+-- Created: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS service_request_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    request_id UUID NOT NULL REFERENCES service_requests(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    actor VARCHAR(20) NOT NULL CHECK (actor IN ('customer', 'provider', 'system')),
+    actor_user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50),
+    note TEXT,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Events are never changed. The only exceptions are clearing the actor and the
+-- note when a user's account is deleted, and deleting a booking's events along
+-- with it.
+CREATE OR REPLACE FUNCTION prevent_service_request_event_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        IF pg_trigger_depth() > 1 THEN
+            RETURN OLD;
+        END IF;
+    ELSIF (to_jsonb(NEW) - 'actor_user_id' - 'note') = (to_jsonb(OLD) - 'actor_user_id' - 'note')
+          AND (NEW.actor_user_id IS NULL OR NEW.actor_user_id = OLD.actor_user_id)
+          AND (NEW.note IS NULL OR NEW.note = OLD.note) THEN
+        RETURN NEW;
+    END IF;
+    RAISE EXCEPTION 'service_request_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER service_request_events_append_only BEFORE UPDATE OR DELETE ON service_request_events
+    FOR EACH ROW EXECUTE FUNCTION prevent_service_request_event_changes();
+
+-- Backfill: the creation of every existing booking, then its move to its current status
+INSERT INTO service_request_events (request_id, type, actor, to_status, metadata, created_at)
+SELECT id, 'status_changed', 'customer', 'requested', '{"backfilled": true}', created_at
+FROM service_requests;
+
+INSERT INTO service_request_events (request_id, type, actor, from_status, to_status, metadata, created_at)
+SELECT id, 'status_changed',
+       CASE WHEN status = 'cancelled' THEN COALESCE(cancelled_by, 'system')
+            ELSE 'provider' END,
+       CASE WHEN status = 'cancelled' THEN COALESCE(cancelled_from, 'requested')
+            WHEN status = 'completed' THEN 'confirmed'
+            ELSE 'requested' END,
+       status,
+       jsonb_build_object('backfilled', true, 'fee', cancellation_fee, 'reason', cancellation_reason),
+       COALESCE(cancelled_at, updated_at)
+FROM service_requests
+WHERE status <> 'requested';
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_service_request_events_request_id ON service_request_events(request_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_service_request_events_created ON service_request_events(created_at) WHERE type = 'status_changed' AND to_status = 'requested';
+
+-- Add comments
+COMMENT ON TABLE service_request_events IS 'Append-only history of each booking, shown as its timeline and used for booking analytics';
+COMMENT ON COLUMN service_request_events.type IS 'status_changed, reschedule_proposed, reschedule_accepted, reschedule_rejected, reschedule_countered, reschedule_expired, note_added or price_changed';
+COMMENT ON COLUMN service_request_events.actor IS 'Who acted: customer, provider or system';
+COMMENT ON COLUMN service_request_events.from_status IS 'For status_changed events, the previous status (NULL when the booking was created)';
+COMMENT ON COLUMN service_request_events.note IS 'Free text from the actor; cleared when their account is deleted';
+COMMENT ON COLUMN service_request_events.metadata IS 'Details of the event, e.g. the proposed date, the old and new price, or the cancellation reason and fee';
@@ -0,0 +1,18 @@
+-- Migration: Booking expiry, overdue flags and no-shows
+-- Description: Supports the booking sweeper, which expires requests providers never answer and
+--              flags confirmed bookings that were not completed long after they ended, and lets
+--              customers report provider no-shows, which count against the provider's reliability
+-- Created: 2026-10-19
+
+ALTER TABLE service_requests ADD COLUMN IF NOT EXISTS overdue_at TIMESTAMP;
+
+-- Create indexes
+CREATE INDEX IF NOT EXISTS idx_service_requests_requested_created ON service_requests(created_at) WHERE status = 'requested';
+CREATE INDEX IF NOT EXISTS idx_service_requests_confirmed_end ON service_requests(estimated_end_date) WHERE status = 'confirmed' AND overdue_at IS NULL;
+
+-- Add comments
+COMMENT ON COLUMN service_requests.overdue_at IS 'When the booking was flagged for not being completed long after its estimated end';
+COMMENT ON COLUMN service_requests.cancellation_reason IS 'Reason code, e.g. schedule_conflict, provider_unavailable, no_show (reported by the customer) or expired (by the system)';
+COMMENT ON COLUMN service_providers.reliability_score IS 'Percentage of confirmed bookings the provider neither cancelled nor missed (100 with no history)';
+COMMENT ON COLUMN service_providers.provider_cancellations IS 'Number of confirmed bookings the provider cancelled or did not show up for';
+COMMENT ON COLUMN service_request_events.type IS 'status_changed, reschedule_proposed, reschedule_accepted, reschedule_rejected, reschedule_countered, reschedule_expired, note_added, price_changed or flagged_overdue';
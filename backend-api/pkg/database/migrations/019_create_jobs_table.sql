@@ -0,0 +1,37 @@
+-- Migration: Create jobs table
+-- Description: Background job queue (pkg/jobs). Workers on every instance claim due jobs with
+--              FOR UPDATE SKIP LOCKED; failed jobs are retried with backoff and kept as dead
+--              letters once out of attempts. Recurring jobs get a unique key per run time so
+--              only one instance enqueues each run.
+-- Created: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'completed', 'dead')),
+    unique_key VARCHAR(255),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL DEFAULT 1 CHECK (max_attempts > 0),
+    run_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    locked_until TIMESTAMP,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+-- Create indexes
+CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_unique_key ON jobs(unique_key) WHERE unique_key IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_jobs_queued ON jobs(run_at) WHERE status = 'queued';
+CREATE INDEX IF NOT EXISTS idx_jobs_running ON jobs(locked_until) WHERE status = 'running';
+CREATE INDEX IF NOT EXISTS idx_jobs_completed ON jobs(finished_at) WHERE status = 'completed';
+CREATE INDEX IF NOT EXISTS idx_jobs_updated ON jobs(updated_at DESC);
+
+-- Add comments
+COMMENT ON TABLE jobs IS 'Background job queue; completed jobs are pruned after JOBS_RETENTION, dead letters are kept';
+COMMENT ON COLUMN jobs.kind IS 'Handler the job runs, e.g. exports.build or bookings.sweep';
+COMMENT ON COLUMN jobs.status IS 'queued (waiting for run_at), running (locked by a worker until locked_until), completed or dead';
+COMMENT ON COLUMN jobs.unique_key IS 'At most one stored job per key, e.g. one per export or per run time of a recurring job';
+COMMENT ON COLUMN jobs.attempts IS 'Runs started so far; a job that fails on attempt max_attempts is dead';
+COMMENT ON COLUMN jobs.locked_until IS 'When a running job is considered abandoned and may be claimed again';
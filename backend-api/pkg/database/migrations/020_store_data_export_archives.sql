@@ -0,0 +1,20 @@
+-- Migration: Store data export archives in the database
+-- Description: Export archives were written to a directory local to the instance that built them, so
+--              downloads failed on other instances once exports ran on the shared job queue. They are
+--              now stored in data_export_archives, deleted with their export (and so with the user).
+--              Archives already built on disk are not carried over; those exports are expired and can
+--              be requested again.
+-- Created: 2026-10-19
+
+CREATE TABLE IF NOT EXISTS data_export_archives (
+    export_id UUID PRIMARY KEY REFERENCES data_exports(id) ON DELETE CASCADE,
+    content BYTEA NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+UPDATE data_exports SET status = 'expired', file_size = NULL WHERE status = 'completed';
+ALTER TABLE data_exports DROP COLUMN IF EXISTS file_path;
+
+-- Add comments
+COMMENT ON TABLE data_export_archives IS 'Zip archive of each completed data export, deleted when its download link expires';
+COMMENT ON COLUMN data_export_archives.content IS 'The zip archive of JSON files served by the signed download link';
@@ -0,0 +1,23 @@
+-- Migration: Store booking times with their time zone
+-- Description: Booking times come from clients with a UTC offset (e.g. 10:00+05:00). Columns of type
+--              TIMESTAMP drop the offset, so such a booking was stored, compared for overlaps and returned
+--              as 10:00 UTC. Booking, reschedule and booking history times become TIMESTAMPTZ. Existing
+--              values are taken to be UTC, which is how they have been read back so far.
+-- Created: 2026-10-19
+
+ALTER TABLE service_requests
+    ALTER COLUMN requested_date TYPE TIMESTAMPTZ USING requested_date AT TIME ZONE 'UTC',
+    ALTER COLUMN scheduled_date TYPE TIMESTAMPTZ USING scheduled_date AT TIME ZONE 'UTC',
+    ALTER COLUMN estimated_end_date TYPE TIMESTAMPTZ USING estimated_end_date AT TIME ZONE 'UTC',
+    ALTER COLUMN cancelled_at TYPE TIMESTAMPTZ USING cancelled_at AT TIME ZONE 'UTC',
+    ALTER COLUMN overdue_at TYPE TIMESTAMPTZ USING overdue_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMPTZ USING updated_at AT TIME ZONE 'UTC';
+
+ALTER TABLE reschedule_proposals
+    ALTER COLUMN proposed_date TYPE TIMESTAMPTZ USING proposed_date AT TIME ZONE 'UTC',
+    ALTER COLUMN responded_at TYPE TIMESTAMPTZ USING responded_at AT TIME ZONE 'UTC',
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
+
+ALTER TABLE service_request_events
+    ALTER COLUMN created_at TYPE TIMESTAMPTZ USING created_at AT TIME ZONE 'UTC';
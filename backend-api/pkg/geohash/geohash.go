@@ -0,0 +1,122 @@
+package geohash
+
+import (
+	"math"
+	"strings"
+)
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// earthRadiusKm is the mean radius of the Earth
+const earthRadiusKm = 6371.0
+
+// Cell is the rectangle covered by a geohash
+type Cell struct {
+	MinLat, MaxLat float64
+	MinLng, MaxLng float64
+}
+
+// Center returns the centre of the cell
+func (c Cell) Center() (lat, lng float64) {
+	return (c.MinLat + c.MaxLat) / 2, (c.MinLng + c.MaxLng) / 2
+}
+
+// RadiusKm returns the distance from the centre of the cell to its farthest
+// corner, so a search around the centre widened by it covers the whole cell
+func (c Cell) RadiusKm() float64 {
+	lat, lng := c.Center()
+	return math.Max(
+		Distance(lat, lng, c.MinLat, c.MinLng),
+		Distance(lat, lng, c.MaxLat, c.MaxLng),
+	)
+}
+
+// Encode returns the geohash of a point with the given number of characters.
+// Precision 5 cells are about 4.9 x 4.9 km, precision 6 about 1.2 x 0.6 km.
+func Encode(lat, lng float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	var hash strings.Builder
+	hash.Grow(precision)
+
+	bits, ch := 0, 0
+	evenBit := true
+	for hash.Len() < precision {
+		if evenBit {
+			mid := (minLng + maxLng) / 2
+			if lng >= mid {
+				ch = ch<<1 | 1
+				minLng = mid
+			} else {
+				ch <<= 1
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				minLat = mid
+			} else {
+				ch <<= 1
+				maxLat = mid
+			}
+		}
+		evenBit = !evenBit
+
+		if bits++; bits == 5 {
+			hash.WriteByte(base32[ch])
+			bits, ch = 0, 0
+		}
+	}
+
+	return hash.String()
+}
+
+// Decode returns the cell covered by a geohash. Invalid characters are ignored.
+func Decode(hash string) Cell {
+	cell := Cell{MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+
+	evenBit := true
+	for _, r := range strings.ToLower(hash) {
+		idx := strings.IndexRune(base32, r)
+		if idx < 0 {
+			continue
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := idx>>bit&1 == 1
+			if evenBit {
+				mid := (cell.MinLng + cell.MaxLng) / 2
+				if set {
+					cell.MinLng = mid
+				} else {
+					cell.MaxLng = mid
+				}
+			} else {
+				mid := (cell.MinLat + cell.MaxLat) / 2
+				if set {
+					cell.MinLat = mid
+				} else {
+					cell.MaxLat = mid
+				}
+			}
+			evenBit = !evenBit
+		}
+	}
+
+	return cell
+}
+
+// Distance returns the great-circle distance between two points in kilometres
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
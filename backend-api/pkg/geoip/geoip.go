@@ -0,0 +1,108 @@
+// Package geoip resolves IP addresses to approximate locations
+package geoip
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Locator resolves an IP address to an approximate, human-readable location such
+// as "Lahore, PK". It returns "" when the location is unknown.
+type Locator interface {
+	Locate(ctx context.Context, ip string) string
+}
+
+// NewLocator creates a locator for the given provider name
+func NewLocator(provider, filePath string) (Locator, error) {
+	switch provider {
+	case "", "none":
+		return NoopLocator{}, nil
+	case "file":
+		return LoadFile(filePath)
+	default:
+		return nil, fmt.Errorf("unsupported GeoIP provider: %s", provider)
+	}
+}
+
+// NoopLocator never knows the location
+type NoopLocator struct{}
+
+// Locate returns ""
+func (NoopLocator) Locate(context.Context, string) string {
+	return ""
+}
+
+// FileLocator looks addresses up in a list of networks loaded from a CSV file of
+// "network,location" lines, e.g. "39.32.0.0/11,Lahore, PK". Blank lines and lines
+// starting with # are ignored. When networks overlap, the most specific wins.
+// Lookups scan the list, so it suits lists of up to a few thousand networks.
+type FileLocator struct {
+	networks []network
+}
+
+type network struct {
+	prefix   netip.Prefix
+	location string
+}
+
+// LoadFile loads a FileLocator from path
+func LoadFile(path string) (*FileLocator, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open GeoIP file: %w", err)
+	}
+	defer f.Close()
+
+	locator := &FileLocator{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		cidr, location, ok := strings.Cut(text, ",")
+		if !ok {
+			return nil, fmt.Errorf("GeoIP file line %d: expected network,location", line)
+		}
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("GeoIP file line %d: %w", line, err)
+		}
+		locator.networks = append(locator.networks, network{
+			prefix:   prefix.Masked(),
+			location: strings.TrimSpace(location),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read GeoIP file: %w", err)
+	}
+
+	// Most specific networks first, so the first match is the best one
+	sort.SliceStable(locator.networks, func(i, j int) bool {
+		return locator.networks[i].prefix.Bits() > locator.networks[j].prefix.Bits()
+	})
+
+	return locator, nil
+}
+
+// Locate returns the location of the most specific network containing ip
+func (l *FileLocator) Locate(_ context.Context, ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	for _, n := range l.networks {
+		if n.prefix.Contains(addr) {
+			return n.location
+		}
+	}
+	return ""
+}
@@ -0,0 +1,145 @@
+// Package jobs runs background work outside of requests: one-off jobs, which
+// may be delayed, and jobs scheduled on a cron or interval. Jobs are stored in
+// Postgres or Redis and claimed by whichever instance is free, so every
+// instance can run a Queue. Failed jobs are retried with exponential backoff;
+// those that run out of attempts are kept as dead letters for inspection.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Status is where a job is in its lifecycle
+type Status string
+
+const (
+	StatusQueued    Status = "queued"  // Waiting for its run time, or for a retry
+	StatusRunning   Status = "running" // Claimed by a worker
+	StatusCompleted Status = "completed"
+	StatusDead      Status = "dead" // Failed on its last attempt, or permanently
+)
+
+// IsValid reports whether s is a known status
+func (s Status) IsValid() bool {
+	switch s {
+	case StatusQueued, StatusRunning, StatusCompleted, StatusDead:
+		return true
+	}
+	return false
+}
+
+// Job is a unit of background work of a registered kind
+type Job struct {
+	ID          string          `json:"id"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      Status          `json:"status"`
+	UniqueKey   string          `json:"unique_key,omitempty"` // At most one job is stored per key
+	Attempts    int             `json:"attempts"`             // Including the current run
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"` // When the job, or its next retry, is due
+	LockedUntil *time.Time      `json:"locked_until,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"` // Completed or dead
+}
+
+// ListFilter selects jobs to list; empty fields match every job
+type ListFilter struct {
+	Status Status
+	Kind   string
+	Limit  int
+}
+
+// Store persists jobs. Claim must hand each due job to a single worker, also
+// when several instances claim at once; the other methods only change a job
+// still running under the attempt it was claimed with, so a worker whose lock
+// expired cannot overwrite the outcome of the worker that took the job over.
+type Store interface {
+	// Enqueue stores a new job, failing with ErrDuplicate if a job with its
+	// unique key is already stored
+	Enqueue(ctx context.Context, job *Job) error
+	// Claim locks up to limit jobs of the given kinds until lockedUntil: queued
+	// jobs that are due, and running jobs whose lock has expired. Those of the
+	// latter that are out of attempts become dead ("lock expired") instead.
+	Claim(ctx context.Context, kinds []string, limit int, lockedUntil time.Time) ([]*Job, error)
+	Complete(ctx context.Context, job *Job) error
+	// Retry queues job to run again at runAt
+	Retry(ctx context.Context, job *Job, runAt time.Time) error
+	// Kill moves job to the dead letters
+	Kill(ctx context.Context, job *Job) error
+	// List lists jobs, most recently updated first
+	List(ctx context.Context, filter ListFilter) ([]*Job, error)
+	// Prune deletes jobs completed before the given time and returns how many
+	Prune(ctx context.Context, completedBefore time.Time) (int, error)
+}
+
+// Notifier is implemented by stores that announce newly enqueued jobs, so idle
+// workers claim them right away instead of at their next poll
+type Notifier interface {
+	// Enqueued signals on the returned channel when a due job was enqueued,
+	// until ctx is cancelled. Signals may be coalesced or, while the store is
+	// unreachable, lost; polling still picks those jobs up.
+	Enqueued(ctx context.Context) (<-chan struct{}, error)
+}
+
+var (
+	// ErrDuplicate is returned when enqueueing a job whose unique key is taken
+	ErrDuplicate = errors.New("a job with this unique key already exists")
+	// ErrLockLost is returned when a job's outcome is recorded after its lock
+	// expired and another worker took it over
+	ErrLockLost = errors.New("job lock lost")
+)
+
+// permanentError marks a failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so the job goes straight to the dead letters instead of
+// being retried, e.g. when the record it works on no longer exists
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Option customizes a job when it is enqueued
+type Option func(*Job)
+
+// Delay runs the job no earlier than d from now
+func Delay(d time.Duration) Option {
+	return func(j *Job) { j.RunAt = time.Now().Add(d) }
+}
+
+// At runs the job no earlier than t
+func At(t time.Time) Option {
+	return func(j *Job) { j.RunAt = t }
+}
+
+// MaxAttempts overrides the queue's default number of attempts
+func MaxAttempts(n int) Option {
+	return func(j *Job) { j.MaxAttempts = n }
+}
+
+// UniqueKey stores the job only if no job with key is stored (see ErrDuplicate)
+func UniqueKey(key string) Option {
+	return func(j *Job) { j.UniqueKey = key }
+}
+
+type jobContextKey struct{}
+
+// LastAttempt reports whether the job running with ctx will not be retried if
+// it fails, e.g. to record the failure on the record it works on
+func LastAttempt(ctx context.Context) bool {
+	job, ok := ctx.Value(jobContextKey{}).(*Job)
+	return ok && job.Attempts >= job.MaxAttempts
+}
@@ -0,0 +1,194 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const jobColumns = `id, kind, payload, status, unique_key, attempts, max_attempts, run_at, locked_until,
+		       last_error, created_at, updated_at, finished_at`
+
+// PostgresStore stores jobs in the jobs table. Workers claim jobs with
+// FOR UPDATE SKIP LOCKED, so instances claiming at once never wait on or take
+// each other's jobs.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore creates a job store on db
+func NewPostgresStore(db *sql.DB) *PostgresStore {
+	return &PostgresStore{db: db}
+}
+
+func (s *PostgresStore) Enqueue(ctx context.Context, job *Job) error {
+	query := `
+		INSERT INTO jobs (id, kind, payload, status, unique_key, attempts, max_attempts, run_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, 0, $6, $7, $8, $8)
+		ON CONFLICT (unique_key) WHERE unique_key IS NOT NULL DO NOTHING
+	`
+
+	result, err := s.db.ExecContext(ctx, query,
+		job.ID,
+		job.Kind,
+		[]byte(job.Payload),
+		job.Status,
+		sql.NullString{String: job.UniqueKey, Valid: job.UniqueKey != ""},
+		job.MaxAttempts,
+		job.RunAt,
+		job.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	if rows, err := result.RowsAffected(); err == nil && rows == 0 {
+		return ErrDuplicate
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) Claim(ctx context.Context, kinds []string, limit int, lockedUntil time.Time) ([]*Job, error) {
+	query := `
+		WITH expired AS (
+			UPDATE jobs
+			SET status = 'dead', locked_until = NULL, last_error = 'lock expired', updated_at = $4, finished_at = $4
+			WHERE kind = ANY($1) AND status = 'running' AND locked_until < $4 AND attempts >= max_attempts
+		)
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, locked_until = $3, updated_at = $4
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE kind = ANY($1)
+			  AND ((status = 'queued' AND run_at <= $4)
+			    OR (status = 'running' AND locked_until < $4 AND attempts < max_attempts))
+			ORDER BY run_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	return s.list(ctx, query, pq.Array(kinds), limit, lockedUntil, time.Now())
+}
+
+func (s *PostgresStore) Complete(ctx context.Context, job *Job) error {
+	query := `
+		UPDATE jobs SET status = 'completed', locked_until = NULL, updated_at = $3, finished_at = $3
+		WHERE id = $1 AND status = 'running' AND attempts = $2
+	`
+	return s.finish(ctx, query, job.ID, job.Attempts, time.Now())
+}
+
+func (s *PostgresStore) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	query := `
+		UPDATE jobs SET status = 'queued', run_at = $3, locked_until = NULL, last_error = $4, updated_at = $5
+		WHERE id = $1 AND status = 'running' AND attempts = $2
+	`
+	return s.finish(ctx, query, job.ID, job.Attempts, runAt, job.LastError, time.Now())
+}
+
+func (s *PostgresStore) Kill(ctx context.Context, job *Job) error {
+	query := `
+		UPDATE jobs SET status = 'dead', locked_until = NULL, last_error = $3, updated_at = $4, finished_at = $4
+		WHERE id = $1 AND status = 'running' AND attempts = $2
+	`
+	return s.finish(ctx, query, job.ID, job.Attempts, job.LastError, time.Now())
+}
+
+func (s *PostgresStore) List(ctx context.Context, filter ListFilter) ([]*Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs
+		WHERE ($1 = '' OR status = $1) AND ($2 = '' OR kind = $2)
+		ORDER BY updated_at DESC
+		LIMIT $3`
+
+	return s.list(ctx, query, string(filter.Status), filter.Kind, filter.Limit)
+}
+
+func (s *PostgresStore) Prune(ctx context.Context, completedBefore time.Time) (int, error) {
+	result, err := s.db.ExecContext(ctx, `DELETE FROM jobs WHERE status = 'completed' AND finished_at < $1`, completedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune jobs: %w", err)
+	}
+
+	pruned, err := result.RowsAffected()
+	return int(pruned), err
+}
+
+// finish records a job's outcome, failing with ErrLockLost if it was claimed again
+func (s *PostgresStore) finish(ctx context.Context, query string, args ...interface{}) error {
+	result, err := s.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	if rows == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+func (s *PostgresStore) list(ctx context.Context, query string, args ...interface{}) ([]*Job, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	defer rows.Close()
+
+	jobs := []*Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func scanJob(rows *sql.Rows) (*Job, error) {
+	job := &Job{}
+	var uniqueKey, lastError sql.NullString
+	var lockedUntil, finishedAt sql.NullTime
+	var payload []byte
+
+	err := rows.Scan(
+		&job.ID,
+		&job.Kind,
+		&payload,
+		&job.Status,
+		&uniqueKey,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAt,
+		&lockedUntil,
+		&lastError,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&finishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Payload = payload
+	job.UniqueKey = uniqueKey.String
+	job.LastError = lastError.String
+	if lockedUntil.Valid {
+		job.LockedUntil = &lockedUntil.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+
+	return job, nil
+}
+
+var _ Store = (*PostgresStore)(nil)
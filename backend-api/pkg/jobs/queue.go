@@ -0,0 +1,337 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"karigar-backend/pkg/metrics"
+)
+
+// Config holds queue configuration
+type Config struct {
+	Concurrency  int           // Jobs run at once by this instance
+	PollInterval time.Duration // How often idle workers look for due jobs
+	Timeout      time.Duration // How long a run may take before it is cancelled and counted as failed
+	MaxAttempts  int           // Default number of attempts of a job
+	BackoffBase  time.Duration // Delay before the first retry, doubled for each further one
+	BackoffMax   time.Duration
+	DrainTimeout time.Duration // How long Run waits for running jobs once ctx is cancelled
+	Retention    time.Duration // How long completed jobs are kept; dead jobs are kept until deleted
+}
+
+// lockGrace is added to the timeout when locking a job, so a job whose run is
+// being cancelled is not taken over before its outcome is recorded
+const lockGrace = time.Minute
+
+// storeTimeout bounds recording a job's outcome, which must also happen while draining
+const storeTimeout = 10 * time.Second
+
+// pruneKind is the built-in job that deletes old completed jobs
+var pruneKind = NewKind[struct{}]("jobs.prune")
+
+// Kind names a kind of job and the type of its payload, which is stored as JSON
+type Kind[T any] struct {
+	name string
+}
+
+// NewKind creates a job kind; names are stored with each job, so keep them stable
+func NewKind[T any](name string) Kind[T] {
+	return Kind[T]{name: name}
+}
+
+// Name returns the kind's name
+func (k Kind[T]) Name() string {
+	return k.name
+}
+
+type handlerFunc func(ctx context.Context, payload json.RawMessage) error
+
+type recurring struct {
+	kind     string
+	schedule Schedule
+	payload  json.RawMessage
+	next     time.Time
+}
+
+// Queue enqueues jobs and runs the handlers registered for them. Register
+// handlers and recurring jobs with Handle and Repeat before calling Run.
+type Queue struct {
+	store     Store
+	cfg       Config
+	handlers  map[string]handlerFunc
+	recurring []*recurring
+}
+
+// NewQueue creates a queue on store
+func NewQueue(store Store, cfg Config) *Queue {
+	q := &Queue{
+		store:    store,
+		cfg:      cfg,
+		handlers: make(map[string]handlerFunc),
+	}
+
+	if cfg.Retention > 0 {
+		Handle(q, pruneKind, func(ctx context.Context, _ struct{}) error {
+			pruned, err := store.Prune(ctx, time.Now().Add(-cfg.Retention))
+			if pruned > 0 {
+				slog.InfoContext(ctx, "pruned completed jobs", "count", pruned)
+			}
+			return err
+		})
+		Repeat(q, pruneKind, Every(time.Hour), struct{}{})
+	}
+
+	return q
+}
+
+// Handle registers the handler of a kind of job. A handler that returns an
+// error is retried, unless the error is Permanent or the job is out of
+// attempts. Jobs may run more than once (e.g. when an instance dies mid-run),
+// so handlers must be idempotent.
+func Handle[T any](q *Queue, kind Kind[T], handler func(ctx context.Context, payload T) error) {
+	q.handlers[kind.name] = func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Permanent(fmt.Errorf("failed to decode %s payload: %w", kind.name, err))
+		}
+		return handler(ctx, payload)
+	}
+}
+
+// Enqueue stores a job of the given kind, to run as soon as a worker is free
+// unless delayed with an Option
+func Enqueue[T any](ctx context.Context, q *Queue, kind Kind[T], payload T, opts ...Option) (*Job, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", kind.name, err)
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          uuid.New().String(),
+		Kind:        kind.name,
+		Payload:     raw,
+		Status:      StatusQueued,
+		MaxAttempts: q.cfg.MaxAttempts,
+		RunAt:       now,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if job.MaxAttempts < 1 {
+		job.MaxAttempts = 1
+	}
+
+	if err := q.store.Enqueue(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// Repeat enqueues a job of the given kind with payload at every run time of
+// schedule while the queue runs. Each run time is enqueued once however many
+// instances run the queue, and run times missed while no instance was running
+// are skipped.
+func Repeat[T any](q *Queue, kind Kind[T], schedule Schedule, payload T) error {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s payload: %w", kind.name, err)
+	}
+
+	q.recurring = append(q.recurring, &recurring{kind: kind.name, schedule: schedule, payload: raw})
+	return nil
+}
+
+// List lists stored jobs, most recently updated first
+func (q *Queue) List(ctx context.Context, filter ListFilter) ([]*Job, error) {
+	return q.store.List(ctx, filter)
+}
+
+// Run claims and runs due jobs, and enqueues scheduled ones, until ctx is
+// cancelled. It then stops claiming and waits up to the drain timeout for
+// running jobs to finish; those still running are cancelled and retried later.
+func (q *Queue) Run(ctx context.Context) {
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	now := time.Now()
+	for _, r := range q.recurring {
+		r.next = r.schedule.Next(now)
+	}
+
+	// Running jobs outlive ctx until the drain timeout
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	slots := make(chan struct{}, q.cfg.Concurrency)
+	var running sync.WaitGroup
+	ticker := time.NewTicker(q.cfg.PollInterval)
+	defer ticker.Stop()
+
+	// Wake up for jobs enqueued between polls, where the store announces them
+	var enqueued <-chan struct{}
+	if notifier, ok := q.store.(Notifier); ok {
+		var err error
+		if enqueued, err = notifier.Enqueued(ctx); err != nil {
+			slog.WarnContext(ctx, "failed to watch for enqueued jobs; polling only", "error", err)
+		}
+	}
+
+	for ctx.Err() == nil {
+		q.enqueueRecurring(ctx)
+
+		claimed := 0
+		if free := cap(slots) - len(slots); free > 0 {
+			jobs, err := q.store.Claim(ctx, kinds, free, time.Now().Add(q.cfg.Timeout+lockGrace))
+			if err != nil && ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to claim jobs", "error", err)
+			}
+			for _, job := range jobs {
+				slots <- struct{}{}
+				running.Add(1)
+				go func(job *Job) {
+					defer func() {
+						<-slots
+						running.Done()
+					}()
+					q.execute(jobCtx, job)
+				}(job)
+			}
+			claimed = len(jobs)
+		}
+
+		// Look again right away while there may be more due jobs than free workers
+		if claimed > 0 && len(slots) < cap(slots) {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+		case <-ticker.C:
+		case _, ok := <-enqueued:
+			if !ok {
+				enqueued = nil
+			}
+		}
+	}
+
+	drained := make(chan struct{})
+	go func() {
+		running.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(q.cfg.DrainTimeout):
+		slog.Warn("job drain timed out; cancelling running jobs")
+		cancelJobs()
+		<-drained
+	}
+}
+
+// enqueueRecurring enqueues the scheduled jobs that are due. The unique key
+// of each run time keeps other instances from enqueueing it again.
+func (q *Queue) enqueueRecurring(ctx context.Context) {
+	now := time.Now()
+	for _, r := range q.recurring {
+		if r.next.IsZero() || now.Before(r.next) {
+			continue
+		}
+
+		job := &Job{
+			ID:          uuid.New().String(),
+			Kind:        r.kind,
+			Payload:     r.payload,
+			Status:      StatusQueued,
+			UniqueKey:   r.kind + "@" + strconv.FormatInt(r.next.Unix(), 10),
+			MaxAttempts: 1, // The next run time is the retry
+			RunAt:       r.next,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := q.store.Enqueue(ctx, job); err != nil && !errors.Is(err, ErrDuplicate) {
+			slog.ErrorContext(ctx, "failed to enqueue scheduled job", "kind", r.kind, "error", err)
+			continue
+		}
+		r.next = r.schedule.Next(now)
+	}
+}
+
+// execute runs a claimed job and records its outcome
+func (q *Queue) execute(ctx context.Context, job *Job) {
+	start := time.Now()
+	err := q.run(ctx, job)
+	metrics.JobDuration.WithLabelValues(job.Kind).Observe(time.Since(start).Seconds())
+
+	storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), storeTimeout)
+	defer cancel()
+
+	outcome := metrics.JobCompleted
+	var permanent *permanentError
+	switch {
+	case err == nil:
+		err = q.store.Complete(storeCtx, job)
+	case errors.As(err, &permanent) || job.Attempts >= job.MaxAttempts:
+		outcome = metrics.JobDead
+		job.LastError = err.Error()
+		slog.ErrorContext(ctx, "job failed", "job_id", job.ID, "kind", job.Kind, "attempts", job.Attempts, "error", err)
+		err = q.store.Kill(storeCtx, job)
+	default:
+		outcome = metrics.JobRetried
+		job.LastError = err.Error()
+		runAt := time.Now().Add(q.backoff(job.Attempts))
+		slog.WarnContext(ctx, "job failed; retrying", "job_id", job.ID, "kind", job.Kind, "attempts", job.Attempts, "retry_at", runAt, "error", err)
+		err = q.store.Retry(storeCtx, job, runAt)
+	}
+	metrics.JobRuns.WithLabelValues(job.Kind, outcome).Inc()
+
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to record job outcome", "job_id", job.ID, "kind", job.Kind, "outcome", outcome, "error", err)
+	}
+}
+
+// run calls the job's handler with the timeout, turning a panic into an error
+func (q *Queue) run(ctx context.Context, job *Job) (err error) {
+	handler, ok := q.handlers[job.Kind]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job kind %q", job.Kind))
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithValue(ctx, jobContextKey{}, job), q.cfg.Timeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, job.Payload)
+}
+
+// backoff returns the delay before retrying a job that failed its attempt-th
+// run: the base delay doubled for each earlier attempt, capped, with up to 10%
+// jitter so jobs that failed together do not retry together
+func (q *Queue) backoff(attempt int) time.Duration {
+	delay := q.cfg.BackoffBase
+	for i := 1; i < attempt && delay < q.cfg.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > q.cfg.BackoffMax {
+		delay = q.cfg.BackoffMax
+	}
+	if jitter := int64(delay / 10); jitter > 0 {
+		delay += time.Duration(rand.Int63n(jitter))
+	}
+	return delay
+}
@@ -0,0 +1,326 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"karigar-backend/pkg/redis"
+)
+
+// RedisStore stores jobs in Redis, for deployments that would rather keep
+// queue traffic off the database. Each job is a JSON document; sorted sets
+// index the queued jobs by run time, the running ones by lock expiry, and the
+// completed and dead ones by when they finished. Every change runs as a Lua
+// script, so claims are atomic across instances. Jobs are lost with Redis's
+// data unless it persists to disk.
+//
+// Listing considers the most recently updated listScanLimit jobs.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a job store on client
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+const (
+	// listScanLimit bounds the jobs List looks through
+	listScanLimit = 10000
+	// pruneBatchSize is the number of jobs Prune deletes per script run
+	pruneBatchSize = 1000
+)
+
+// redisJobsKey prefixes every key of the store; scripts append to it
+var redisJobsKey = redis.Key("jobs")
+
+// enqueuedChannel is the pub/sub channel announcing due jobs to idle workers
+var enqueuedChannel = redis.Key("jobs", "enqueued")
+
+// redisJob is a job as stored in Redis. The payload is kept as a string so the
+// scripts, which decode and re-encode jobs, leave it untouched.
+type redisJob struct {
+	Job
+	Payload string `json:"payload"`
+}
+
+var enqueueScript = redis.NewScript(`
+local p = KEYS[1]
+if ARGV[4] ~= '' and not redis.call('SET', p .. ':unique:' .. ARGV[4], ARGV[1], 'NX') then
+	return 0
+end
+redis.call('SET', p .. ':job:' .. ARGV[1], ARGV[2])
+redis.call('ZADD', p .. ':queued', ARGV[3], ARGV[1])
+redis.call('ZADD', p .. ':all', ARGV[5], ARGV[1])
+return 1`)
+
+func (s *RedisStore) Enqueue(ctx context.Context, job *Job) error {
+	data, err := encodeRedisJob(job)
+	if err != nil {
+		return err
+	}
+
+	created, err := s.client.RunScript(ctx, enqueueScript, []string{redisJobsKey},
+		job.ID, data, job.RunAt.UnixMilli(), job.UniqueKey, job.CreatedAt.UnixMilli())
+	if err != nil {
+		return fmt.Errorf("failed to enqueue job: %w", err)
+	}
+	if created == int64(0) {
+		return ErrDuplicate
+	}
+
+	// Delayed jobs are left to polling
+	if !job.RunAt.After(time.Now()) {
+		if err := s.client.Publish(ctx, enqueuedChannel, job.ID); err != nil {
+			slog.WarnContext(ctx, "failed to announce enqueued job", "job_id", job.ID, "error", err)
+		}
+	}
+
+	return nil
+}
+
+// Enqueued signals when a due job is enqueued by any instance
+func (s *RedisStore) Enqueued(ctx context.Context) (<-chan struct{}, error) {
+	messages, err := s.client.Subscribe(ctx, enqueuedChannel)
+	if err != nil {
+		return nil, fmt.Errorf("failed to subscribe to enqueued jobs: %w", err)
+	}
+
+	signals := make(chan struct{}, 1)
+	go func() {
+		defer close(signals)
+		for range messages {
+			select {
+			case signals <- struct{}{}:
+			default: // A signal is already pending
+			}
+		}
+	}()
+
+	return signals, nil
+}
+
+// claimScript takes running jobs whose lock expired, then due queued jobs.
+// Expired ones out of attempts become dead instead.
+var claimScript = redis.NewScript(`
+local p = KEYS[1]
+local limit = tonumber(ARGV[2])
+local kinds = {}
+for i = 6, #ARGV do
+	kinds[ARGV[i]] = true
+end
+
+local claimed = {}
+for _, set in ipairs({'running', 'queued'}) do
+	local max = ARGV[1]
+	if set == 'running' then
+		max = '(' .. ARGV[1]
+	end
+	local ids = redis.call('ZRANGEBYSCORE', p .. ':' .. set, '-inf', max, 'LIMIT', 0, 1000)
+	for _, id in ipairs(ids) do
+		if #claimed >= limit then
+			return claimed
+		end
+		local data = redis.call('GET', p .. ':job:' .. id)
+		if not data then
+			redis.call('ZREM', p .. ':' .. set, id)
+		else
+			local job = cjson.decode(data)
+			if kinds[job.kind] and set == 'running' and job.attempts >= job.max_attempts then
+				job.status = 'dead'
+				job.locked_until = nil
+				job.last_error = 'lock expired'
+				job.updated_at = ARGV[5]
+				job.finished_at = ARGV[5]
+				redis.call('SET', p .. ':job:' .. id, cjson.encode(job))
+				redis.call('ZREM', p .. ':running', id)
+				redis.call('ZADD', p .. ':dead', ARGV[1], id)
+				redis.call('ZADD', p .. ':all', ARGV[1], id)
+			elseif kinds[job.kind] then
+				job.status = 'running'
+				job.attempts = job.attempts + 1
+				job.locked_until = ARGV[4]
+				job.updated_at = ARGV[5]
+				data = cjson.encode(job)
+				redis.call('SET', p .. ':job:' .. id, data)
+				redis.call('ZREM', p .. ':' .. set, id)
+				redis.call('ZADD', p .. ':running', ARGV[3], id)
+				redis.call('ZADD', p .. ':all', ARGV[1], id)
+				table.insert(claimed, data)
+			end
+		end
+	end
+end
+return claimed`)
+
+func (s *RedisStore) Claim(ctx context.Context, kinds []string, limit int, lockedUntil time.Time) ([]*Job, error) {
+	now := time.Now()
+	args := []interface{}{now.UnixMilli(), limit, lockedUntil.UnixMilli(), formatRedisTime(lockedUntil), formatRedisTime(now)}
+	for _, kind := range kinds {
+		args = append(args, kind)
+	}
+
+	result, err := s.client.RunScript(ctx, claimScript, []string{redisJobsKey}, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim jobs: %w", err)
+	}
+	return decodeRedisJobs(result)
+}
+
+// finishScript records the outcome of a run, if the job is still running
+// under the attempt it was claimed with
+var finishScript = redis.NewScript(`
+local p = KEYS[1]
+local key = p .. ':job:' .. ARGV[1]
+local data = redis.call('GET', key)
+if not data then
+	return 0
+end
+local job = cjson.decode(data)
+if job.status ~= 'running' or job.attempts ~= tonumber(ARGV[2]) then
+	return 0
+end
+
+job.status = ARGV[3]
+job.updated_at = ARGV[4]
+job.locked_until = nil
+if ARGV[6] ~= '' then
+	job.last_error = ARGV[6]
+end
+redis.call('ZREM', p .. ':running', ARGV[1])
+if ARGV[3] == 'queued' then
+	job.run_at = ARGV[8]
+	redis.call('ZADD', p .. ':queued', ARGV[7], ARGV[1])
+else
+	job.finished_at = ARGV[4]
+	redis.call('ZADD', p .. ':' .. ARGV[3], ARGV[5], ARGV[1])
+end
+redis.call('SET', key, cjson.encode(job))
+redis.call('ZADD', p .. ':all', ARGV[5], ARGV[1])
+return 1`)
+
+func (s *RedisStore) Complete(ctx context.Context, job *Job) error {
+	return s.finish(ctx, job, StatusCompleted, time.Time{})
+}
+
+func (s *RedisStore) Retry(ctx context.Context, job *Job, runAt time.Time) error {
+	return s.finish(ctx, job, StatusQueued, runAt)
+}
+
+func (s *RedisStore) Kill(ctx context.Context, job *Job) error {
+	return s.finish(ctx, job, StatusDead, time.Time{})
+}
+
+var listScript = redis.NewScript(`
+local p = KEYS[1]
+local limit = tonumber(ARGV[3])
+local jobs = {}
+for _, id in ipairs(redis.call('ZREVRANGE', p .. ':all', 0, tonumber(ARGV[4]) - 1)) do
+	if #jobs >= limit then
+		break
+	end
+	local data = redis.call('GET', p .. ':job:' .. id)
+	if data then
+		local job = cjson.decode(data)
+		if (ARGV[1] == '' or job.status == ARGV[1]) and (ARGV[2] == '' or job.kind == ARGV[2]) then
+			table.insert(jobs, data)
+		end
+	end
+end
+return jobs`)
+
+func (s *RedisStore) List(ctx context.Context, filter ListFilter) ([]*Job, error) {
+	result, err := s.client.RunScript(ctx, listScript, []string{redisJobsKey},
+		string(filter.Status), filter.Kind, filter.Limit, listScanLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return decodeRedisJobs(result)
+}
+
+var pruneScript = redis.NewScript(`
+local p = KEYS[1]
+local ids = redis.call('ZRANGEBYSCORE', p .. ':completed', '-inf', '(' .. ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, id in ipairs(ids) do
+	local data = redis.call('GET', p .. ':job:' .. id)
+	if data then
+		local job = cjson.decode(data)
+		if job.unique_key then
+			redis.call('DEL', p .. ':unique:' .. job.unique_key)
+		end
+	end
+	redis.call('DEL', p .. ':job:' .. id)
+	redis.call('ZREM', p .. ':completed', id)
+	redis.call('ZREM', p .. ':all', id)
+end
+return #ids`)
+
+func (s *RedisStore) Prune(ctx context.Context, completedBefore time.Time) (int, error) {
+	pruned := 0
+	for {
+		result, err := s.client.RunScript(ctx, pruneScript, []string{redisJobsKey}, completedBefore.UnixMilli(), pruneBatchSize)
+		if err != nil {
+			return pruned, fmt.Errorf("failed to prune jobs: %w", err)
+		}
+		count, _ := result.(int64)
+		pruned += int(count)
+		if count < pruneBatchSize {
+			return pruned, nil
+		}
+	}
+}
+
+// finish moves a running job to status, failing with ErrLockLost if it was claimed again
+func (s *RedisStore) finish(ctx context.Context, job *Job, status Status, runAt time.Time) error {
+	now := time.Now()
+	updated, err := s.client.RunScript(ctx, finishScript, []string{redisJobsKey},
+		job.ID, job.Attempts, string(status), formatRedisTime(now), now.UnixMilli(), job.LastError,
+		runAt.UnixMilli(), formatRedisTime(runAt))
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	if updated == int64(0) {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+func encodeRedisJob(job *Job) (string, error) {
+	data, err := json.Marshal(redisJob{Job: *job, Payload: string(job.Payload)})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode job: %w", err)
+	}
+	return string(data), nil
+}
+
+func decodeRedisJobs(result interface{}) ([]*Job, error) {
+	items, _ := result.([]interface{})
+	jobs := make([]*Job, 0, len(items))
+	for _, item := range items {
+		data, _ := item.(string)
+		var stored redisJob
+		if err := json.Unmarshal([]byte(data), &stored); err != nil {
+			return nil, fmt.Errorf("failed to decode job: %w", err)
+		}
+		job := stored.Job
+		job.Payload = json.RawMessage(stored.Payload)
+		jobs = append(jobs, &job)
+	}
+
+	return jobs, nil
+}
+
+// formatRedisTime formats a time the way encoding/json does, so scripts can
+// set time fields of stored jobs
+func formatRedisTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+var (
+	_ Store    = (*RedisStore)(nil)
+	_ Notifier = (*RedisStore)(nil)
+)
@@ -0,0 +1,145 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "karigar"
+
+// Registry holds all application metrics. A dedicated registry (rather than the
+// global default) keeps /metrics limited to what we register explicitly.
+var Registry = prometheus.NewRegistry()
+
+var factory = promauto.With(Registry)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// HTTP metrics
+var (
+	HTTPRequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by route template, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	HTTPRequestsInFlight = factory.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_in_flight",
+		Help:      "Number of HTTP requests currently being served.",
+	})
+)
+
+// Redis metrics
+var (
+	RedisCommandDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "redis",
+		Name:      "command_duration_seconds",
+		Help:      "Redis command latency by command and result.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"command", "result"})
+)
+
+// Cache metrics
+var (
+	CacheRequests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "cache",
+		Name:      "requests_total",
+		Help:      "Read-through cache lookups by cache (provider, services, availability, search) and result.",
+	}, []string{"cache", "result"})
+)
+
+// Job queue metrics
+var (
+	JobRuns = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "runs_total",
+		Help:      "Background job runs by kind and outcome (completed, retried, dead).",
+	}, []string{"kind", "outcome"})
+
+	JobDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "jobs",
+		Name:      "duration_seconds",
+		Help:      "Background job run time by kind.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300},
+	}, []string{"kind"})
+)
+
+// Business metrics
+var (
+	Registrations = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "registrations_total",
+		Help:      "Completed user registrations by role.",
+	}, []string{"role"})
+
+	Logins = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "logins_total",
+		Help:      "Login attempts by method (password, phone, mfa) and outcome.",
+	}, []string{"method", "outcome"})
+
+	BookingTransitions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "booking_transitions_total",
+		Help:      "Booking status transitions.",
+	}, []string{"from", "to"})
+
+	Reviews = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_total",
+		Help:      "Submitted reviews by rating.",
+	}, []string{"rating"})
+)
+
+// Login outcomes
+const (
+	LoginSuccess               = "success"
+	LoginFailure               = "failure"
+	LoginMFARequired           = "mfa_required"
+	LoginMFAEnrollmentRequired = "mfa_enrollment_required"
+)
+
+// BookingCreated is the "from" label of a booking's first transition, into requested
+const BookingCreated = "created"
+
+// Job run outcomes
+const (
+	JobCompleted = "completed"
+	JobRetried   = "retried"
+	JobDead      = "dead" // Out of attempts, or failed permanently
+)
+
+// Cache lookup results
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error" // The cache was unreachable; the value was loaded from the database
+)
+
+// ObserveReview counts a submitted review
+func ObserveReview(rating int) {
+	Reviews.WithLabelValues(strconv.Itoa(rating)).Inc()
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,85 @@
+package phone
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	ErrInvalidPhone = errors.New("invalid phone number")
+)
+
+const (
+	minE164Digits = 8
+	maxE164Digits = 15
+)
+
+// NormalizeE164 converts a user-entered phone number to E.164 format (e.g. "+923001234567").
+// Numbers written in national format ("0300 1234567") or without a leading "+"
+// are interpreted using defaultCountryCode (e.g. "92").
+func NormalizeE164(raw, defaultCountryCode string) (string, error) {
+	trimmed := strings.TrimSpace(raw)
+	if trimmed == "" {
+		return "", ErrInvalidPhone
+	}
+
+	// Strip common formatting characters
+	var digits strings.Builder
+	for i, char := range trimmed {
+		switch {
+		case char >= '0' && char <= '9':
+			digits.WriteRune(char)
+		case char == '+' && i == 0:
+			// Leading plus is handled below
+		case char == ' ' || char == '-' || char == '.' || char == '(' || char == ')':
+			// Formatting characters are ignored
+		default:
+			return "", ErrInvalidPhone
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(trimmed, "+"):
+		// Already international
+	case strings.HasPrefix(number, "00"):
+		// International call prefix, e.g. 0092...
+		number = strings.TrimPrefix(number, "00")
+	case strings.HasPrefix(number, "0"):
+		// National trunk prefix, e.g. 0300...
+		number = defaultCountryCode + strings.TrimPrefix(number, "0")
+	case defaultCountryCode != "" && !strings.HasPrefix(number, defaultCountryCode):
+		number = defaultCountryCode + number
+	}
+
+	if len(number) < minE164Digits || len(number) > maxE164Digits || number[0] == '0' {
+		return "", ErrInvalidPhone
+	}
+
+	return "+" + number, nil
+}
+
+// IsE164 reports whether a phone number is already in E.164 format
+func IsE164(number string) bool {
+	if !strings.HasPrefix(number, "+") {
+		return false
+	}
+	digits := number[1:]
+	if len(digits) < minE164Digits || len(digits) > maxE164Digits || digits[0] == '0' {
+		return false
+	}
+	for _, char := range digits {
+		if char < '0' || char > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Mask hides all but the last few digits of a phone number for display and logs
+func Mask(number string) string {
+	if len(number) <= 4 {
+		return "****"
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
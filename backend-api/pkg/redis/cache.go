@@ -1,82 +1,151 @@
 package redis
 
 import (
+	"context"
 	"encoding/json"
+	"strconv"
 	"time"
 )
 
-// CacheService provides caching functionality
+// CacheService provides caching functionality on top of a Store
 type CacheService struct {
-	client *redis.Client
+	client Store
 }
 
 // NewCacheService creates a new cache service
-func NewCacheService(client *redis.Client) *CacheService {
+func NewCacheService(client Store) *CacheService {
 	return &CacheService{client: client}
 }
 
-// SetJSON stores a JSON-serializable object
-func (c *CacheService) SetJSON(key string, value interface{}, expiration time.Duration) error {
+// SetJSON stores a JSON-serializable object. The key is recorded under each
+// tag so that InvalidateTags can delete it along with the rest of the tag;
+// entries sharing a tag should share an expiration, which the tag inherits.
+func (c *CacheService) SetJSON(ctx context.Context, key string, value interface{}, expiration time.Duration, tags ...string) error {
 	jsonData, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
-	return Set(key, jsonData, expiration)
+	if err := c.client.Set(ctx, key, jsonData, expiration); err != nil {
+		return err
+	}
+
+	for _, tag := range tags {
+		if err := c.client.SAdd(ctx, TagKey(tag), key); err != nil {
+			return err
+		}
+		if expiration > 0 {
+			if err := c.client.Expire(ctx, TagKey(tag), expiration); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
 }
 
 // GetJSON retrieves and deserializes a JSON object
-func (c *CacheService) GetJSON(key string, dest interface{}) error {
-	data, err := Get(key)
+func (c *CacheService) GetJSON(ctx context.Context, key string, dest interface{}) error {
+	data, err := c.client.Get(ctx, key)
 	if err != nil {
 		return err
 	}
 	return json.Unmarshal([]byte(data), dest)
 }
 
+// InvalidateTags deletes every key cached under the given tags
+func (c *CacheService) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		keys, err := c.client.SMembers(ctx, TagKey(tag))
+		if err != nil {
+			return err
+		}
+		if err := c.client.Delete(ctx, append(keys, TagKey(tag))...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InvalidatePattern deletes every key matching a glob pattern, e.g. "user:*".
+// It scans the keyspace, so prefer tags for anything on a request path.
+func (c *CacheService) InvalidatePattern(ctx context.Context, match string) error {
+	return c.client.Scan(ctx, match, func(keys []string) error {
+		return c.client.Delete(ctx, keys...)
+	})
+}
+
 // CacheUser caches user data
-func (c *CacheService) CacheUser(userID string, userData interface{}, expiration time.Duration) error {
-	key := "user:" + userID
-	return c.SetJSON(key, userData, expiration)
+func (c *CacheService) CacheUser(ctx context.Context, userID string, userData interface{}, expiration time.Duration) error {
+	return c.SetJSON(ctx, userKey(userID), userData, expiration)
 }
 
 // GetCachedUser retrieves cached user data
-func (c *CacheService) GetCachedUser(userID string, dest interface{}) error {
-	key := "user:" + userID
-	return c.GetJSON(key, dest)
+func (c *CacheService) GetCachedUser(ctx context.Context, userID string, dest interface{}) error {
+	return c.GetJSON(ctx, userKey(userID), dest)
 }
 
 // InvalidateUser invalidates user cache
-func (c *CacheService) InvalidateUser(userID string) error {
-	key := "user:" + userID
-	return Delete(key)
+func (c *CacheService) InvalidateUser(ctx context.Context, userID string) error {
+	return c.client.Delete(ctx, userKey(userID))
 }
 
 // CacheSession stores session data
-func (c *CacheService) CacheSession(sessionID string, sessionData interface{}, expiration time.Duration) error {
-	key := "session:" + sessionID
-	return c.SetJSON(key, sessionData, expiration)
+func (c *CacheService) CacheSession(ctx context.Context, sessionID string, sessionData interface{}, expiration time.Duration) error {
+	return c.SetJSON(ctx, sessionKey(sessionID), sessionData, expiration)
 }
 
 // GetCachedSession retrieves cached session data
-func (c *CacheService) GetCachedSession(sessionID string, dest interface{}) error {
-	key := "session:" + sessionID
-	return c.GetJSON(key, dest)
+func (c *CacheService) GetCachedSession(ctx context.Context, sessionID string, dest interface{}) error {
+	return c.GetJSON(ctx, sessionKey(sessionID), dest)
+}
+
+// InvalidateSession deletes session data
+func (c *CacheService) InvalidateSession(ctx context.Context, sessionID string) error {
+	return c.client.Delete(ctx, sessionKey(sessionID))
+}
+
+// RateLimitResult is the outcome of counting a request against a rate limit
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int       // Requests left in the current window
+	Reset     time.Time // When the current window ends
 }
 
-// RateLimit checks and increments rate limit counter
-func (c *CacheService) RateLimit(key string, limit int, window time.Duration) (bool, error) {
-	current, err := Increment(key)
+// RateLimit counts a request against a fixed window of limit requests per
+// window. Windows are aligned to the clock, so every instance agrees on when
+// one ends without reading the counter's expiry.
+func (c *CacheService) RateLimit(ctx context.Context, key string, limit int, window time.Duration) (RateLimitResult, error) {
+	start := time.Now().Truncate(window)
+	key = Key("ratelimit", key, strconv.FormatInt(start.Unix(), 10))
+
+	current, err := c.client.Increment(ctx, key)
 	if err != nil {
-		return false, err
+		return RateLimitResult{}, err
 	}
 
 	if current == 1 {
 		// First request, set expiration
-		if err := Expire(key, window); err != nil {
-			return false, err
+		if err := c.client.Expire(ctx, key, window); err != nil {
+			return RateLimitResult{}, err
 		}
 	}
 
-	return current <= int64(limit), nil
+	remaining := int64(limit) - current
+	if remaining < 0 {
+		remaining = 0
+	}
+	return RateLimitResult{
+		Allowed:   current <= int64(limit),
+		Limit:     limit,
+		Remaining: int(remaining),
+		Reset:     start.Add(window),
+	}, nil
 }
 
+func userKey(userID string) string {
+	return Key("user", userID)
+}
+
+func sessionKey(sessionID string) string {
+	return Key("session", sessionID)
+}
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	"karigar-backend/pkg/apperror"
+)
+
+// ErrUnavailable is wrapped around connection failures and timeouts
+var ErrUnavailable = apperror.New(apperror.Unavailable, "redis_unavailable", "service temporarily unavailable, please try again later")
+
+// errorHook marks connection failures as ErrUnavailable so that requests which
+// depend on Redis fail with a 503 instead of an internal error. Command errors
+// (and redis.Nil) are left as they are.
+type errorHook struct{}
+
+func (errorHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (errorHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if isConnectionError(err) {
+			err = ErrUnavailable.WithCause(err)
+			cmd.SetErr(err)
+		}
+		return err
+	}
+}
+
+func (errorHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		err := next(ctx, cmds)
+		if isConnectionError(err) {
+			err = ErrUnavailable.WithCause(err)
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+		}
+		return err
+	}
+}
+
+func isConnectionError(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return false
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, redis.ErrClosed) ||
+		errors.Is(err, context.DeadlineExceeded)
+}
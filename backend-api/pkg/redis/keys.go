@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"strings"
+)
+
+// keySeparator separates the parts of a key
+const keySeparator = ":"
+
+// scanBatchSize is the COUNT hint of each SCAN call
+const scanBatchSize = 100
+
+// Key joins parts into a key, e.g. Key("otp", "login", "+923001234567") is
+// "otp:login:+923001234567". Keys are relative to the store's namespace.
+func Key(parts ...string) string {
+	return strings.Join(parts, keySeparator)
+}
+
+// TagKey returns the key of the set that records the keys cached under tag
+func TagKey(tag string) string {
+	return Key("tag", tag)
+}
+
+// Keyspace maps keys into a namespace, so several environments or applications
+// can share one Redis without their keys colliding. The zero value uses no
+// namespace.
+type Keyspace struct {
+	prefix string
+}
+
+// NewKeyspace creates a keyspace for namespace, e.g. "karigar:production"
+func NewKeyspace(namespace string) Keyspace {
+	namespace = strings.TrimSuffix(namespace, keySeparator)
+	if namespace == "" {
+		return Keyspace{}
+	}
+	return Keyspace{prefix: namespace + keySeparator}
+}
+
+// Key returns the namespaced form of key
+func (k Keyspace) Key(key string) string {
+	return k.prefix + key
+}
+
+// Keys returns the namespaced form of keys
+func (k Keyspace) Keys(keys []string) []string {
+	if k.prefix == "" {
+		return keys
+	}
+	namespaced := make([]string, len(keys))
+	for i, key := range keys {
+		namespaced[i] = k.prefix + key
+	}
+	return namespaced
+}
+
+// Strip removes the namespace from a namespaced key
+func (k Keyspace) Strip(key string) string {
+	return strings.TrimPrefix(key, k.prefix)
+}
+
+// Pattern returns the namespaced form of a SCAN match pattern. Glob characters
+// in the namespace itself are escaped so they match literally.
+func (k Keyspace) Pattern(match string) string {
+	return escapeGlob(k.prefix) + match
+}
+
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
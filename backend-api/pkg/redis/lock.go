@@ -0,0 +1,46 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+)
+
+// ErrLockHeld is returned by AcquireLock when another owner holds the lock
+var ErrLockHeld = errors.New("lock is held by another owner")
+
+// Lock is a lock held in a Store. It expires after its TTL, so a crashed owner
+// cannot hold it forever; keep the work done under it shorter than the TTL.
+type Lock struct {
+	store Store
+	key   string
+	token string
+}
+
+// AcquireLock takes the lock named key, or returns ErrLockHeld
+func AcquireLock(ctx context.Context, store Store, key string, ttl time.Duration) (*Lock, error) {
+	tokenBytes := make([]byte, 16)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(tokenBytes)
+
+	ok, err := store.SetNX(ctx, Key("lock", key), token, ttl)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrLockHeld
+	}
+
+	return &Lock{store: store, key: Key("lock", key), token: token}, nil
+}
+
+// Release releases the lock if it is still ours; a lock that expired and was
+// taken by someone else is left alone
+func (l *Lock) Release(ctx context.Context) error {
+	_, err := l.store.DeleteIfValue(ctx, l.key, l.token)
+	return err
+}
@@ -0,0 +1,343 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval is how often expired keys are removed from a MemoryStore
+const memorySweepInterval = time.Minute
+
+// MemoryStore is an in-process Store. State is not shared between server
+// instances, so it only suits single-instance deployments and development.
+type MemoryStore struct {
+	mu          sync.Mutex
+	items       map[string]memoryItem
+	subscribers map[string]map[chan Message]struct{}
+	stop        chan struct{}
+	closeOnce   sync.Once
+}
+
+type memoryItem struct {
+	value     string
+	members   map[string]struct{} // Set members, for keys written with SAdd
+	expiresAt time.Time           // Zero means no expiry
+}
+
+func (i memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && !now.Before(i.expiresAt)
+}
+
+// NewMemoryStore creates an in-memory store. Close stops its expiry sweeper.
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		items:       make(map[string]memoryItem),
+		subscribers: make(map[string]map[chan Message]struct{}),
+		stop:        make(chan struct{}),
+	}
+	go s.sweep()
+	return s
+}
+
+// Get retrieves a value by key
+func (s *MemoryStore) Get(ctx context.Context, key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.get(key)
+	if !ok {
+		return "", Nil
+	}
+	return item.value, nil
+}
+
+// Set stores a key-value pair with expiration (0 means no expiry)
+func (s *MemoryStore) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[key] = memoryItem{value: formatValue(value), expiresAt: expiresAt(expiration)}
+	return nil
+}
+
+// SetNX sets a key only if it doesn't exist (for locking)
+func (s *MemoryStore) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.get(key); ok {
+		return false, nil
+	}
+	s.items[key] = memoryItem{value: formatValue(value), expiresAt: expiresAt(expiration)}
+	return true, nil
+}
+
+// Delete removes keys
+func (s *MemoryStore) Delete(ctx context.Context, keys ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		delete(s.items, key)
+	}
+	return nil
+}
+
+// Scan calls fn with batches of the keys matching a glob pattern. The keys are
+// collected up front, so fn may modify the store.
+func (s *MemoryStore) Scan(ctx context.Context, match string, fn func(keys []string) error) error {
+	pattern, err := globRegexp(match)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	now := time.Now()
+	var keys []string
+	for key, item := range s.items {
+		if !item.expired(now) && pattern.MatchString(key) {
+			keys = append(keys, key)
+		}
+	}
+	s.mu.Unlock()
+	sort.Strings(keys)
+
+	for start := 0; start < len(keys); start += scanBatchSize {
+		end := start + scanBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		if err := fn(keys[start:end]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SAdd adds members to a set
+func (s *MemoryStore) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.get(key)
+	if !ok {
+		item = memoryItem{}
+	}
+	if item.members == nil {
+		item.members = make(map[string]struct{}, len(members))
+	}
+	for _, member := range members {
+		item.members[formatValue(member)] = struct{}{}
+	}
+	s.items[key] = item
+	return nil
+}
+
+// SMembers returns all members of a set
+func (s *MemoryStore) SMembers(ctx context.Context, key string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, _ := s.get(key)
+	members := make([]string, 0, len(item.members))
+	for member := range item.members {
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// Exists checks if a key exists
+func (s *MemoryStore) Exists(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.get(key)
+	return ok, nil
+}
+
+// Increment increments a key's value, keeping its expiry
+func (s *MemoryStore) Increment(ctx context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.get(key)
+	var current int64
+	if ok {
+		var err error
+		if current, err = strconv.ParseInt(item.value, 10, 64); err != nil {
+			return 0, fmt.Errorf("value of %s is not an integer", key)
+		}
+	}
+
+	current++
+	item.value = strconv.FormatInt(current, 10)
+	s.items[key] = item
+	return current, nil
+}
+
+// Expire sets expiration on a key
+func (s *MemoryStore) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if item, ok := s.get(key); ok {
+		item.expiresAt = expiresAt(expiration)
+		s.items[key] = item
+	}
+	return nil
+}
+
+// DeleteIfValue deletes key only if it holds value
+func (s *MemoryStore) DeleteIfValue(ctx context.Context, key, value string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	item, ok := s.get(key)
+	if !ok || item.value != value {
+		return false, nil
+	}
+	delete(s.items, key)
+	return true, nil
+}
+
+// Publish sends a message to the subscribers of channel. Like Redis, it does
+// not wait for slow subscribers: a message they cannot take is dropped.
+func (s *MemoryStore) Publish(ctx context.Context, channel, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ch := range s.subscribers[channel] {
+		select {
+		case ch <- Message{Channel: channel, Payload: message}:
+		default:
+		}
+	}
+	return nil
+}
+
+// Subscribe receives messages on the given channels until ctx is cancelled
+func (s *MemoryStore) Subscribe(ctx context.Context, channels ...string) (<-chan Message, error) {
+	ch := make(chan Message, 64)
+
+	s.mu.Lock()
+	for _, channel := range channels {
+		if s.subscribers[channel] == nil {
+			s.subscribers[channel] = make(map[chan Message]struct{})
+		}
+		s.subscribers[channel][ch] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, channel := range channels {
+			delete(s.subscribers[channel], ch)
+			if len(s.subscribers[channel]) == 0 {
+				delete(s.subscribers, channel)
+			}
+		}
+		close(ch)
+	}()
+
+	return ch, nil
+}
+
+// Ping always succeeds
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close stops the expiry sweeper
+func (s *MemoryStore) Close() error {
+	s.closeOnce.Do(func() { close(s.stop) })
+	return nil
+}
+
+// get returns a live item, dropping it if it has expired. Callers hold mu.
+func (s *MemoryStore) get(key string) (memoryItem, bool) {
+	item, ok := s.items[key]
+	if !ok {
+		return memoryItem{}, false
+	}
+	if item.expired(time.Now()) {
+		delete(s.items, key)
+		return memoryItem{}, false
+	}
+	return item, true
+}
+
+// sweep periodically removes expired keys that are never read again
+func (s *MemoryStore) sweep() {
+	ticker := time.NewTicker(memorySweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			s.mu.Lock()
+			for key, item := range s.items {
+				if item.expired(now) {
+					delete(s.items, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+func expiresAt(expiration time.Duration) time.Time {
+	if expiration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(expiration)
+}
+
+// globRegexp compiles a Redis glob pattern. Only *, ? and \ escapes are
+// supported; character classes are matched literally.
+func globRegexp(match string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(match); i++ {
+		switch c := match[i]; c {
+		case '*':
+			b.WriteString("(?s:.*)")
+		case '?':
+			b.WriteString("(?s:.)")
+		case '\\':
+			if i+1 < len(match) {
+				i++
+			}
+			fallthrough
+		default:
+			b.WriteString(regexp.QuoteMeta(match[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+	return regexp.Compile(b.String())
+}
+
+// formatValue converts a value the way go-redis does when writing it
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
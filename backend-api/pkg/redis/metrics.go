@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"karigar-backend/pkg/metrics"
+)
+
+// metricsHook records the latency of every Redis command
+type metricsHook struct{}
+
+func (metricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (metricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		observeCommand(cmd.Name(), start, err)
+		return err
+	}
+}
+
+func (metricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		observeCommand("pipeline", start, err)
+		return err
+	}
+}
+
+func observeCommand(name string, start time.Time, err error) {
+	result := "ok"
+	switch {
+	case errors.Is(err, redis.Nil):
+		result = "nil"
+	case err != nil:
+		result = "error"
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			result = "timeout"
+		}
+	}
+
+	metrics.RedisCommandDuration.WithLabelValues(name, result).Observe(time.Since(start).Seconds())
+}
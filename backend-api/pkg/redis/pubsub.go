@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+)
+
+// Publish sends a message to the subscribers of channel
+func (c *Client) Publish(ctx context.Context, channel, message string) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.Publish(ctx, c.keys.Key(channel), message).Err()
+}
+
+// Subscribe receives messages on the given channels until ctx is cancelled.
+// Channels are namespaced like keys.
+// go-redis resubscribes on its own after a dropped connection; messages
+// published while disconnected are lost.
+func (c *Client) Subscribe(ctx context.Context, channels ...string) (<-chan Message, error) {
+	if c == nil {
+		return nil, ErrDisabled
+	}
+
+	pubsub := c.rdb.Subscribe(ctx, c.keys.Keys(channels)...)
+	// Wait for the subscription to be confirmed so connection errors surface here
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		if isConnectionError(err) {
+			return nil, ErrUnavailable.WithCause(err)
+		}
+		return nil, err
+	}
+
+	out := make(chan Message)
+	go func() {
+		defer close(out)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case out <- Message{Channel: c.keys.Strip(msg.Channel), Payload: msg.Payload}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -2,162 +2,294 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-// Note: Install Redis client with: go get github.com/redis/go-redis/v9
+// Nil is returned by Get and friends when the key does not exist
+const Nil = redis.Nil
 
-var (
-	client *redis.Client
-	ctx    = context.Background()
-)
+// ErrDisabled is returned by every method of a nil Client, i.e. when Redis is disabled
+var ErrDisabled = errors.New("redis is disabled")
 
 // Config holds Redis configuration
 type Config struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	Host      string
+	Port      string
+	Password  string
+	DB        int
+	Namespace string // Prefix of every key and channel, e.g. "karigar:production"
 }
 
-// Connect initializes Redis connection
-func Connect(cfg *Config) (*redis.Client, error) {
-	addr := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+// Client wraps a go-redis client with metrics and tracing hooks. Keys and
+// channels are namespaced transparently: callers use keys relative to the
+// namespace. A nil Client is valid and fails every call with ErrDisabled.
+type Client struct {
+	rdb  *redis.Client
+	keys Keyspace
+}
 
-	client = redis.NewClient(&redis.Options{
-		Addr:     addr,
+// New creates a Redis client. It does not connect; go-redis dials lazily and
+// reconnects on its own, so use Ping to check the connection.
+func New(cfg *Config) *Client {
+	rdb := redis.NewClient(&redis.Options{
+		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
 		Password: cfg.Password,
 		DB:       cfg.DB,
 	})
+	rdb.AddHook(metricsHook{})
+	rdb.AddHook(tracingHook{})
+	rdb.AddHook(errorHook{})
 
-	// Test connection
-	_, err := client.Ping(ctx).Result()
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
-	}
-
-	return client, nil
+	return &Client{rdb: rdb, keys: NewKeyspace(cfg.Namespace)}
 }
 
-// GetClient returns the Redis client instance
-func GetClient() *redis.Client {
-	return client
+// Ping checks that Redis is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	if err := c.rdb.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return nil
 }
 
 // Close closes the Redis connection
-func Close() error {
-	if client != nil {
-		return client.Close()
+func (c *Client) Close() error {
+	if c == nil {
+		return nil
 	}
-	return nil
+	return c.rdb.Close()
 }
 
 // Set stores a key-value pair with expiration
-func Set(key string, value interface{}, expiration time.Duration) error {
-	return client.Set(ctx, key, value, expiration).Err()
+func (c *Client) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.Set(ctx, c.keys.Key(key), value, expiration).Err()
 }
 
 // Get retrieves a value by key
-func Get(key string) (string, error) {
-	return client.Get(ctx, key).Result()
+func (c *Client) Get(ctx context.Context, key string) (string, error) {
+	if c == nil {
+		return "", ErrDisabled
+	}
+	return c.rdb.Get(ctx, c.keys.Key(key)).Result()
 }
 
-// Delete removes a key
-func Delete(key string) error {
-	return client.Del(ctx, key).Err()
+// Delete removes keys
+func (c *Client) Delete(ctx context.Context, keys ...string) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.rdb.Del(ctx, c.keys.Keys(keys)...).Err()
 }
 
 // Exists checks if a key exists
-func Exists(key string) (bool, error) {
-	count, err := client.Exists(ctx, key).Result()
+func (c *Client) Exists(ctx context.Context, key string) (bool, error) {
+	if c == nil {
+		return false, ErrDisabled
+	}
+	count, err := c.rdb.Exists(ctx, c.keys.Key(key)).Result()
 	return count > 0, err
 }
 
 // SetNX sets a key only if it doesn't exist (for locking)
-func SetNX(key string, value interface{}, expiration time.Duration) (bool, error) {
-	return client.SetNX(ctx, key, value, expiration).Result()
+func (c *Client) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error) {
+	if c == nil {
+		return false, ErrDisabled
+	}
+	return c.rdb.SetNX(ctx, c.keys.Key(key), value, expiration).Result()
+}
+
+// deleteIfValueScript deletes a key only if it still holds the expected value
+var deleteIfValueScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// DeleteIfValue deletes key only if it holds value (e.g. to release a lock we own)
+func (c *Client) DeleteIfValue(ctx context.Context, key, value string) (bool, error) {
+	if c == nil {
+		return false, ErrDisabled
+	}
+	deleted, err := deleteIfValueScript.Run(ctx, c.rdb, []string{c.keys.Key(key)}, value).Int()
+	return deleted == 1, err
+}
+
+// Script is a Lua script that Redis runs atomically
+type Script = redis.Script
+
+// NewScript creates a script from its Lua source
+func NewScript(src string) *Script {
+	return redis.NewScript(src)
+}
+
+// RunScript runs script with keys, which are namespaced like those of any
+// other call, and args. Keys a script builds itself must start with one of
+// the keys it was given to be namespaced.
+func (c *Client) RunScript(ctx context.Context, script *Script, keys []string, args ...interface{}) (interface{}, error) {
+	if c == nil {
+		return nil, ErrDisabled
+	}
+	return script.Run(ctx, c.rdb, c.keys.Keys(keys), args...).Result()
 }
 
 // Increment increments a key's value
-func Increment(key string) (int64, error) {
-	return client.Incr(ctx, key).Result()
+func (c *Client) Increment(ctx context.Context, key string) (int64, error) {
+	if c == nil {
+		return 0, ErrDisabled
+	}
+	return c.rdb.Incr(ctx, c.keys.Key(key)).Result()
 }
 
 // Expire sets expiration on a key
-func Expire(key string, expiration time.Duration) error {
-	return client.Expire(ctx, key, expiration).Err()
+func (c *Client) Expire(ctx context.Context, key string, expiration time.Duration) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.Expire(ctx, c.keys.Key(key), expiration).Err()
 }
 
-// Keys returns all keys matching a pattern
-func Keys(pattern string) ([]string, error) {
-	return client.Keys(ctx, pattern).Result()
+// Scan calls fn with batches of the keys matching a glob pattern, iterating
+// with SCAN so Redis is never blocked the way KEYS blocks it. Keys added or
+// removed during the scan may or may not be seen, and a key may be seen twice.
+func (c *Client) Scan(ctx context.Context, match string, fn func(keys []string) error) error {
+	if c == nil {
+		return ErrDisabled
+	}
+
+	var cursor uint64
+	for {
+		keys, next, err := c.rdb.Scan(ctx, cursor, c.keys.Pattern(match), scanBatchSize).Result()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 {
+			for i, key := range keys {
+				keys[i] = c.keys.Strip(key)
+			}
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
 }
 
 // HSet sets a field in a hash
-func HSet(key, field string, value interface{}) error {
-	return client.HSet(ctx, key, field, value).Err()
+func (c *Client) HSet(ctx context.Context, key, field string, value interface{}) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.HSet(ctx, c.keys.Key(key), field, value).Err()
 }
 
 // HGet gets a field from a hash
-func HGet(key, field string) (string, error) {
-	return client.HGet(ctx, key, field).Result()
+func (c *Client) HGet(ctx context.Context, key, field string) (string, error) {
+	if c == nil {
+		return "", ErrDisabled
+	}
+	return c.rdb.HGet(ctx, c.keys.Key(key), field).Result()
 }
 
 // HGetAll gets all fields from a hash
-func HGetAll(key string) (map[string]string, error) {
-	return client.HGetAll(ctx, key).Result()
+func (c *Client) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	if c == nil {
+		return nil, ErrDisabled
+	}
+	return c.rdb.HGetAll(ctx, c.keys.Key(key)).Result()
 }
 
 // HDel deletes fields from a hash
-func HDel(key string, fields ...string) error {
-	return client.HDel(ctx, key, fields...).Err()
+func (c *Client) HDel(ctx context.Context, key string, fields ...string) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.HDel(ctx, c.keys.Key(key), fields...).Err()
 }
 
 // LPush pushes values to the left of a list
-func LPush(key string, values ...interface{}) error {
-	return client.LPush(ctx, key, values...).Err()
+func (c *Client) LPush(ctx context.Context, key string, values ...interface{}) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.LPush(ctx, c.keys.Key(key), values...).Err()
 }
 
 // RPush pushes values to the right of a list
-func RPush(key string, values ...interface{}) error {
-	return client.RPush(ctx, key, values...).Err()
+func (c *Client) RPush(ctx context.Context, key string, values ...interface{}) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.RPush(ctx, c.keys.Key(key), values...).Err()
 }
 
 // LPop pops a value from the left of a list
-func LPop(key string) (string, error) {
-	return client.LPop(ctx, key).Result()
+func (c *Client) LPop(ctx context.Context, key string) (string, error) {
+	if c == nil {
+		return "", ErrDisabled
+	}
+	return c.rdb.LPop(ctx, c.keys.Key(key)).Result()
 }
 
 // RPop pops a value from the right of a list
-func RPop(key string) (string, error) {
-	return client.RPop(ctx, key).Result()
+func (c *Client) RPop(ctx context.Context, key string) (string, error) {
+	if c == nil {
+		return "", ErrDisabled
+	}
+	return c.rdb.RPop(ctx, c.keys.Key(key)).Result()
 }
 
 // LLen returns the length of a list
-func LLen(key string) (int64, error) {
-	return client.LLen(ctx, key).Result()
+func (c *Client) LLen(ctx context.Context, key string) (int64, error) {
+	if c == nil {
+		return 0, ErrDisabled
+	}
+	return c.rdb.LLen(ctx, c.keys.Key(key)).Result()
 }
 
 // SAdd adds members to a set
-func SAdd(key string, members ...interface{}) error {
-	return client.SAdd(ctx, key, members...).Err()
+func (c *Client) SAdd(ctx context.Context, key string, members ...interface{}) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.SAdd(ctx, c.keys.Key(key), members...).Err()
 }
 
 // SMembers returns all members of a set
-func SMembers(key string) ([]string, error) {
-	return client.SMembers(ctx, key).Result()
+func (c *Client) SMembers(ctx context.Context, key string) ([]string, error) {
+	if c == nil {
+		return nil, ErrDisabled
+	}
+	return c.rdb.SMembers(ctx, c.keys.Key(key)).Result()
 }
 
 // SIsMember checks if a member exists in a set
-func SIsMember(key string, member interface{}) (bool, error) {
-	return client.SIsMember(ctx, key, member).Result()
+func (c *Client) SIsMember(ctx context.Context, key string, member interface{}) (bool, error) {
+	if c == nil {
+		return false, ErrDisabled
+	}
+	return c.rdb.SIsMember(ctx, c.keys.Key(key), member).Result()
 }
 
 // SRem removes members from a set
-func SRem(key string, members ...interface{}) error {
-	return client.SRem(ctx, key, members...).Err()
+func (c *Client) SRem(ctx context.Context, key string, members ...interface{}) error {
+	if c == nil {
+		return ErrDisabled
+	}
+	return c.rdb.SRem(ctx, c.keys.Key(key), members...).Err()
 }
 
@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+// Store is the subset of Redis the application relies on: a key-value cache
+// with expiry, sets, counters for rate limiting, compare-and-delete for locks,
+// and pub/sub. Client implements it on Redis; MemoryStore implements it in process
+// for single-instance deployments and development without Redis.
+//
+// Keys are relative to the store's namespace (see Keyspace); build them with Key.
+// Get returns Nil when the key does not exist. When Redis is unreachable, calls
+// fail with an apperror of kind Unavailable, so requests depending on them get
+// a 503 rather than a 500.
+type Store interface {
+	Get(ctx context.Context, key string) (string, error)
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) (bool, error)
+	Delete(ctx context.Context, keys ...string) error
+	Exists(ctx context.Context, key string) (bool, error)
+
+	// Scan calls fn with batches of the keys matching a glob pattern without
+	// blocking the store; see Client.Scan for its consistency guarantees
+	Scan(ctx context.Context, match string, fn func(keys []string) error) error
+
+	// SAdd and SMembers maintain sets of strings, e.g. the keys cached under a tag
+	SAdd(ctx context.Context, key string, members ...interface{}) error
+	SMembers(ctx context.Context, key string) ([]string, error)
+
+	// Increment increments a counter, creating it at 1; Expire sets its window
+	Increment(ctx context.Context, key string) (int64, error)
+	Expire(ctx context.Context, key string, expiration time.Duration) error
+
+	// DeleteIfValue deletes key only if it holds value, atomically
+	DeleteIfValue(ctx context.Context, key, value string) (bool, error)
+
+	// Publish sends a message to the subscribers of channel. Subscribe receives
+	// messages on the given channels until ctx is cancelled, then closes the
+	// returned channel.
+	Publish(ctx context.Context, channel, message string) error
+	Subscribe(ctx context.Context, channels ...string) (<-chan Message, error)
+
+	Ping(ctx context.Context) error
+	Close() error
+}
+
+// Message is a pub/sub message
+type Message struct {
+	Channel string
+	Payload string
+}
+
+var (
+	_ Store = (*Client)(nil)
+	_ Store = (*MemoryStore)(nil)
+)
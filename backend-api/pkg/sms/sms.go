@@ -0,0 +1,68 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"karigar-backend/pkg/phone"
+)
+
+// Sender delivers SMS messages. Production gateways implement this interface;
+// LogSender and FileSender are intended for development.
+type Sender interface {
+	Send(ctx context.Context, to, message string) error
+}
+
+// NewSender creates a sender for the given provider name. The development
+// senders are refused in production, where they would never deliver codes.
+func NewSender(provider, filePath, environment string) (Sender, error) {
+	if environment == "production" && (provider == "" || provider == "log" || provider == "file") {
+		return nil, fmt.Errorf("SMS provider %q is for development only; configure an SMS gateway in production", provider)
+	}
+
+	switch provider {
+	case "", "log":
+		return &LogSender{}, nil
+	case "file":
+		return &FileSender{path: filePath}, nil
+	default:
+		return nil, fmt.Errorf("unsupported SMS provider: %s", provider)
+	}
+}
+
+// LogSender records in the application log that a message was sent. The
+// message itself (which may hold a code) is not logged; use FileSender to read it.
+type LogSender struct{}
+
+// Send logs the masked recipient instead of delivering the message
+func (s *LogSender) Send(ctx context.Context, to, message string) error {
+	slog.InfoContext(ctx, "sms not delivered (log provider)", "to", phone.Mask(to), "length", len(message))
+	return nil
+}
+
+// FileSender appends messages to a local file
+type FileSender struct {
+	path string
+	mu   sync.Mutex
+}
+
+// Send appends the message to the outbox file
+func (s *FileSender) Send(ctx context.Context, to, message string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open SMS outbox: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "%s\t%s\t%s\n", time.Now().Format(time.RFC3339), to, message); err != nil {
+		return fmt.Errorf("failed to write SMS outbox: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package validator
+
+// Supported message languages
+const (
+	English = "en"
+	Urdu    = "ur"
+)
+
+// messages holds the message catalogue per language, keyed by validation rule.
+// {field} and {param} are replaced with the field name and the rule parameter.
+// Rules whose message depends on the field type have a ".string" variant.
+var messages = map[string]map[string]string{
+	English: {
+		"validation_failed": "request validation failed",
+		"malformed":         "request body is not valid JSON",
+		"type":              "{field} has the wrong type",
+		"required":          "{field} is required",
+		"required_without":  "{field} is required when {param} is not provided",
+		"email":             "{field} must be a valid email address",
+		"min":               "{field} must be at least {param}",
+		"min.string":        "{field} must be at least {param} characters long",
+		"max":               "{field} must be at most {param}",
+		"max.string":        "{field} must be at most {param} characters long",
+		"len":               "{field} must be exactly {param}",
+		"len.string":        "{field} must be exactly {param} characters long",
+		"numeric":           "{field} must contain only digits",
+		"oneof":             "{field} must be one of: {param}",
+		"ne":                "{field} cannot be {param}",
+		"uuid":              "{field} must be a valid ID",
+		"userrole":          "{field} must be a valid role",
+		"service_category":  "{field} must be a valid service category",
+		"hhmm":              "{field} must be a time in HH:MM format",
+		"lat":               "{field} must be a latitude between -90 and 90",
+		"lng":               "{field} must be a longitude between -180 and 180",
+		"phone":             "{field} must be a valid phone number",
+		"invalid":           "{field} is invalid",
+	},
+	Urdu: {
+		"validation_failed": "درخواست کی جانچ ناکام ہو گئی",
+		"malformed":         "درخواست کا JSON درست نہیں ہے",
+		"type":              "{field} کی قسم درست نہیں ہے",
+		"required":          "{field} لازمی ہے",
+		"required_without":  "{param} نہ ہونے کی صورت میں {field} لازمی ہے",
+		"email":             "{field} ایک درست ای میل ایڈریس ہونا چاہیے",
+		"min":               "{field} کم از کم {param} ہونا چاہیے",
+		"min.string":        "{field} کم از کم {param} حروف پر مشتمل ہونا چاہیے",
+		"max":               "{field} زیادہ سے زیادہ {param} ہو سکتا ہے",
+		"max.string":        "{field} زیادہ سے زیادہ {param} حروف پر مشتمل ہو سکتا ہے",
+		"len":               "{field} بالکل {param} ہونا چاہیے",
+		"len.string":        "{field} بالکل {param} حروف پر مشتمل ہونا چاہیے",
+		"numeric":           "{field} میں صرف ہندسے ہونے چاہئیں",
+		"oneof":             "{field} ان میں سے ایک ہونا چاہیے: {param}",
+		"ne":                "{field} کی قدر {param} نہیں ہو سکتی",
+		"uuid":              "{field} ایک درست شناخت ہونی چاہیے",
+		"userrole":          "{field} ایک درست کردار ہونا چاہیے",
+		"service_category":  "{field} ایک درست سروس کیٹیگری ہونی چاہیے",
+		"hhmm":              "{field} کا وقت HH:MM کی شکل میں ہونا چاہیے",
+		"lat":               "{field} ‎-90 اور 90 کے درمیان عرض بلد ہونا چاہیے",
+		"lng":               "{field} ‎-180 اور 180 کے درمیان طول بلد ہونا چاہیے",
+		"phone":             "{field} ایک درست فون نمبر ہونا چاہیے",
+		"invalid":           "{field} درست نہیں ہے",
+	},
+}
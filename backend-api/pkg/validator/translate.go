@@ -0,0 +1,133 @@
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	govalidator "github.com/go-playground/validator/v10"
+	"karigar-backend/pkg/apperror"
+)
+
+// Language picks the best supported message language from an Accept-Language
+// header, defaulting to English
+func Language(acceptLanguage string) string {
+	type candidate struct {
+		lang string
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if value, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = value
+				}
+			}
+		}
+
+		// "ur-PK" matches "ur"
+		base := strings.SplitN(tag, "-", 2)[0]
+		if _, ok := messages[base]; ok && q > 0 {
+			candidates = append(candidates, candidate{lang: base, q: q})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	if len(candidates) > 0 {
+		return candidates[0].lang
+	}
+	return English
+}
+
+// Message returns a catalogue message in the given language
+func Message(lang, key string) string {
+	catalogue, ok := messages[lang]
+	if !ok {
+		catalogue = messages[English]
+	}
+	if message, ok := catalogue[key]; ok {
+		return message
+	}
+	return messages[English][key]
+}
+
+// Translate converts request binding errors into localized field errors. It
+// reports false if err is not a binding error.
+func Translate(err error, lang string) ([]apperror.FieldError, bool) {
+	var validationErrs govalidator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]apperror.FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			fields = append(fields, translateFieldError(fe, lang))
+		}
+		return fields, true
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		return []apperror.FieldError{{
+			Field:   field,
+			Code:    "type",
+			Message: format(Message(lang, "type"), field, ""),
+		}}, true
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return []apperror.FieldError{{
+			Code:    "malformed",
+			Message: Message(lang, "malformed"),
+		}}, true
+	}
+
+	return nil, false
+}
+
+func translateFieldError(fe govalidator.FieldError, lang string) apperror.FieldError {
+	field := fieldPath(fe)
+	tag := fe.Tag()
+
+	key := tag
+	if fe.Kind() == reflect.String {
+		if _, ok := messages[English][tag+".string"]; ok {
+			key = tag + ".string"
+		}
+	}
+	if _, ok := messages[English][key]; !ok {
+		key = "invalid"
+	}
+
+	return apperror.FieldError{
+		Field:   field,
+		Code:    tag,
+		Message: format(Message(lang, key), field, fe.Param()),
+	}
+}
+
+// fieldPath returns the field's path without the root struct, e.g. "address.city"
+func fieldPath(fe govalidator.FieldError) string {
+	namespace := fe.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return fe.Field()
+}
+
+func format(message, field, param string) string {
+	return strings.NewReplacer("{field}", field, "{param}", param).Replace(message)
+}
@@ -0,0 +1,85 @@
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	govalidator "github.com/go-playground/validator/v10"
+	"karigar-backend/internal/domain"
+	"karigar-backend/pkg/phone"
+)
+
+var hhmmRegex = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+// Register adds the custom validation rules to Gin's validator and makes field
+// errors use the JSON (or uri/form) name of a field instead of its Go name.
+// defaultCountryCode is used by the "phone" rule to accept national numbers.
+func Register(defaultCountryCode string) error {
+	v, ok := binding.Validator.Engine().(*govalidator.Validate)
+	if !ok {
+		return errors.New("unexpected validator engine")
+	}
+
+	v.RegisterTagNameFunc(fieldName)
+
+	rules := map[string]govalidator.Func{
+		"userrole": func(fl govalidator.FieldLevel) bool {
+			return domain.UserRole(fl.Field().String()).IsValid()
+		},
+		"service_category": func(fl govalidator.FieldLevel) bool {
+			return domain.ServiceCategory(fl.Field().String()).IsValid()
+		},
+		"hhmm": func(fl govalidator.FieldLevel) bool {
+			return hhmmRegex.MatchString(fl.Field().String())
+		},
+		"lat": func(fl govalidator.FieldLevel) bool {
+			return inRange(fl.Field(), -90, 90)
+		},
+		"lng": func(fl govalidator.FieldLevel) bool {
+			return inRange(fl.Field(), -180, 180)
+		},
+		"phone": func(fl govalidator.FieldLevel) bool {
+			_, err := phone.NormalizeE164(fl.Field().String(), defaultCountryCode)
+			return err == nil
+		},
+	}
+	for tag, fn := range rules {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return fmt.Errorf("failed to register %s validator: %w", tag, err)
+		}
+	}
+
+	return nil
+}
+
+// fieldName returns the name clients use for a struct field
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "uri", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// inRange checks that a numeric field lies within [min, max]
+func inRange(field reflect.Value, min, max float64) bool {
+	var value float64
+	switch field.Kind() {
+	case reflect.Float32, reflect.Float64:
+		value = field.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value = float64(field.Int())
+	default:
+		return false
+	}
+	return value >= min && value <= max
+}